@@ -0,0 +1,114 @@
+package saferbq
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// keywordRegex matches SQL keywords, optionally made of multiple words,
+// such as DESC or NULLS FIRST, and function names, such as SAFE.SUM
+var keywordRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*([ .][A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// KeywordSet is a set of SQL keywords that a $identifier may resolve to.
+// The keyword is injected unquoted, so it can be used where an identifier
+// can't, such as the sort direction of an ORDER BY clause.
+//
+// Use KeywordIn() to declare a new KeywordSet.
+type KeywordSet struct {
+	keywords []string
+	allowed  map[string]string
+	// exact disables case-insensitive matching
+	exact bool
+}
+
+// KeywordIn declares the SQL keywords that a $identifier may resolve to.
+// Values are matched case-insensitively and replaced by the keyword as it
+// was declared. It is intended to be declared once, as a package level
+// variable.
+//
+// KeywordIn panics if no keywords are given or if any of the keywords is
+// not made of words of letters, digits and underscores (separated by a
+// space or a dot), similar to regexp.MustCompile.
+//
+// Example:
+//
+//	var sortDirection = saferbq.KeywordIn("ASC", "DESC")
+//
+//	q := client.Query("SELECT * FROM events ORDER BY created_at $dir")
+//	q.SetParams(map[string]any{"$dir": sortDirection.Value(userChoice)})
+func KeywordIn(keywords ...string) *KeywordSet {
+	return newKeywordSet("KeywordIn", keywords, false)
+}
+
+// ExactKeywordIn declares the SQL keywords or function names that a
+// $identifier may resolve to, like KeywordIn, but values must match one of
+// the keywords exactly, including case. It is intended for pickers where
+// the value comes from a fixed set of options, such as an aggregation.
+//
+// Example:
+//
+//	var aggregation = saferbq.ExactKeywordIn("SUM", "AVG", "COUNT")
+//
+//	q := client.Query("SELECT day, $agg(amount) FROM orders GROUP BY day")
+//	q.SetParams(map[string]any{"$agg": aggregation.Value(userChoice)})
+func ExactKeywordIn(keywords ...string) *KeywordSet {
+	return newKeywordSet("ExactKeywordIn", keywords, true)
+}
+
+// newKeywordSet validates the keywords and returns the set, the function
+// name is used in panic messages.
+func newKeywordSet(function string, keywords []string, exact bool) *KeywordSet {
+	if len(keywords) == 0 {
+		panic(fmt.Sprintf("saferbq: %s(): no keywords", function))
+	}
+	allowed := make(map[string]string, len(keywords))
+	for _, keyword := range keywords {
+		if !keywordRegex.MatchString(keyword) {
+			panic(fmt.Sprintf("saferbq: %s(%q): invalid keyword", function, keyword))
+		}
+		allowed[keywordKey(keyword, exact)] = keyword
+	}
+	return &KeywordSet{keywords: keywords, allowed: allowed, exact: exact}
+}
+
+// keywordKey returns the key of the keyword in the allowed map.
+func keywordKey(keyword string, exact bool) string {
+	if exact {
+		return keyword
+	}
+	return strings.ToUpper(keyword)
+}
+
+// Keywords returns the allowed keywords of the set.
+func (s *KeywordSet) Keywords() []string {
+	return append([]string(nil), s.keywords...)
+}
+
+// Value returns the $identifier value that resolves to the keyword.
+// Translation fails with ErrIdentifierNotAllowed when the keyword is not
+// in the set.
+func (s *KeywordSet) Value(keyword string) any {
+	return keywordValue{set: s, value: keyword}
+}
+
+// keywordValue is the value of a keyword parameter.
+type keywordValue struct {
+	set   *KeywordSet
+	value string
+}
+
+// render checks the value against the allowed keywords and returns the
+// keyword unquoted.
+func (v keywordValue) render(name string) (string, error) {
+	keyword, ok := v.set.allowed[keywordKey(v.value, v.set.exact)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be one of %s", ErrIdentifierNotAllowed, name, strings.Join(v.set.keywords, ", "))
+	}
+	return keyword, nil
+}
+
+// String returns the value of the keyword parameter.
+func (v keywordValue) String() string {
+	return v.value
+}
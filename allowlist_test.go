@@ -0,0 +1,143 @@
+package saferbq
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestKeywordIn(t *testing.T) {
+	direction := KeywordIn("ASC", "DESC", "DESC NULLS LAST")
+
+	if len(direction.Keywords()) != 3 {
+		t.Errorf("Keywords() = %v, want 3 keywords", direction.Keywords())
+	}
+
+	tests := []struct {
+		name         string
+		value        string
+		sqlOut       string
+		errorMessage string
+	}{
+		{
+			name:   "allowed keyword",
+			value:  "DESC",
+			sqlOut: "SELECT * FROM events ORDER BY created_at DESC",
+		},
+		{
+			name:   "case insensitive",
+			value:  "asc",
+			sqlOut: "SELECT * FROM events ORDER BY created_at ASC",
+		},
+		{
+			name:   "multiple words",
+			value:  "desc nulls last",
+			sqlOut: "SELECT * FROM events ORDER BY created_at DESC NULLS LAST",
+		},
+		{
+			name:         "keyword not allowed",
+			value:        "RANDOM",
+			errorMessage: "identifier is not allowed: $dir must be one of ASC, DESC, DESC NULLS LAST",
+		},
+		{
+			name:         "injection attempt",
+			value:        "DESC; DROP TABLE events",
+			errorMessage: "identifier is not allowed: $dir must be one of ASC, DESC, DESC NULLS LAST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT * FROM events ORDER BY created_at $dir", []bigquery.QueryParameter{{Name: "$dir", Value: direction.Value(tt.value)}})
+			if err != nil {
+				if tt.errorMessage == "" {
+					t.Fatalf("translate() unexpected error: %v", err)
+				}
+				if err.Error() != tt.errorMessage {
+					t.Fatalf("translate() error = %q, want %q", err.Error(), tt.errorMessage)
+				}
+			} else if tt.errorMessage != "" {
+				t.Fatalf("translate() expected error %q but got none", tt.errorMessage)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestKeywordInPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		keywords []string
+	}{
+		{"no keywords", nil},
+		{"empty keyword", []string{"ASC", ""}},
+		{"invalid keyword", []string{"ASC", "DESC;"}},
+		{"double space", []string{"NULLS  FIRST"}},
+		{"trailing dot", []string{"SAFE."}},
+		{"function call", []string{"SUM(x)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("KeywordIn(%v) expected panic", tt.keywords)
+				}
+			}()
+			KeywordIn(tt.keywords...)
+		})
+	}
+}
+
+func TestExactKeywordIn(t *testing.T) {
+	aggregation := ExactKeywordIn("SUM", "AVG", "COUNT", "SAFE.SUM")
+
+	tests := []struct {
+		name         string
+		value        string
+		sqlOut       string
+		errorMessage string
+	}{
+		{
+			name:   "allowed function",
+			value:  "AVG",
+			sqlOut: "SELECT day, AVG(amount) FROM orders GROUP BY day",
+		},
+		{
+			name:   "dotted function",
+			value:  "SAFE.SUM",
+			sqlOut: "SELECT day, SAFE.SUM(amount) FROM orders GROUP BY day",
+		},
+		{
+			name:         "case mismatch",
+			value:        "avg",
+			errorMessage: "identifier is not allowed: $agg must be one of SUM, AVG, COUNT, SAFE.SUM",
+		},
+		{
+			name:         "injection attempt",
+			value:        "SUM(amount) FROM orders; DROP TABLE orders; SELECT MAX",
+			errorMessage: "identifier is not allowed: $agg must be one of SUM, AVG, COUNT, SAFE.SUM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT day, $agg(amount) FROM orders GROUP BY day", []bigquery.QueryParameter{{Name: "$agg", Value: aggregation.Value(tt.value)}})
+			if err != nil {
+				if tt.errorMessage == "" {
+					t.Fatalf("translate() unexpected error: %v", err)
+				}
+				if err.Error() != tt.errorMessage {
+					t.Fatalf("translate() error = %q, want %q", err.Error(), tt.errorMessage)
+				}
+			} else if tt.errorMessage != "" {
+				t.Fatalf("translate() expected error %q but got none", tt.errorMessage)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
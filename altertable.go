@@ -0,0 +1,131 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// TableAlteration builds an ALTER TABLE statement that applies one or more
+// alterations to a table. Use Client.AlterTable to create one. Invalid
+// column names, types or options are reported by SQL and Exec.
+type TableAlteration struct {
+	client  *Client
+	dataset string
+	table   string
+	actions []string
+	errs    []error
+}
+
+// AlterTable returns a builder for an ALTER TABLE statement on the table.
+// The dataset and table IDs are validated (with the rules of DatasetID and
+// TableID) and the column names with the rules of ColumnName, and all names
+// are quoted. The alterations are applied in a single statement.
+//
+// Example:
+//
+//	err := client.AlterTable("analytics", "events_"+tenant).
+//	    AddColumn("country", bigquery.StringFieldType).
+//	    RenameColumn("ts", "created_at").
+//	    DropColumn("legacy_id").
+//	    SetOptions(saferbq.WithDescription("events of " + tenant)).
+//	    Exec(ctx)
+func (c *Client) AlterTable(dataset, table string) *TableAlteration {
+	return &TableAlteration{client: c, dataset: dataset, table: table}
+}
+
+// AddColumn adds a nullable column of the type. Record columns can't be
+// added with AddColumn, as they need a schema.
+func (a *TableAlteration) AddColumn(name string, fieldType bigquery.FieldType) *TableAlteration {
+	column, err := columnDefinitions(bigquery.Schema{{Name: name, Type: fieldType}})
+	return a.add("ADD COLUMN "+column, err)
+}
+
+// DropColumn drops the column.
+func (a *TableAlteration) DropColumn(name string) *TableAlteration {
+	column, err := ColumnName(name).render("column " + name)
+	return a.add("DROP COLUMN "+column, err)
+}
+
+// RenameColumn renames the column from oldName to newName.
+func (a *TableAlteration) RenameColumn(oldName, newName string) *TableAlteration {
+	from, fromErr := ColumnName(oldName).render("column " + oldName)
+	to, toErr := ColumnName(newName).render("column " + newName)
+	return a.add("RENAME COLUMN "+from+" TO "+to, errors.Join(fromErr, toErr))
+}
+
+// SetOptions sets the table options. Only ExpiresAt, WithDescription and
+// WithTableLabels can be altered.
+func (a *TableAlteration) SetOptions(opts ...TableOption) *TableAlteration {
+	var o tableOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ifNotExists || o.orReplace || o.partitionBy != "" || len(o.clusterBy) > 0 {
+		return a.add("", fmt.Errorf("%w: only the expiration, description and labels can be altered", ErrInvalidDDL))
+	}
+	options, err := o.options()
+	if err != nil {
+		return a.add("", err)
+	}
+	if len(options) == 0 {
+		return a.add("", fmt.Errorf("%w: SetOptions needs at least one option", ErrInvalidDDL))
+	}
+	return a.add("SET OPTIONS ("+strings.Join(options, ", ")+")", nil)
+}
+
+// add records the alteration, or the error that occurred building it.
+func (a *TableAlteration) add(action string, err error) *TableAlteration {
+	if err != nil {
+		a.errs = append(a.errs, err)
+	} else {
+		a.actions = append(a.actions, action)
+	}
+	return a
+}
+
+// query returns the ALTER TABLE query with the dataset and table bound.
+func (a *TableAlteration) query() (*Query, error) {
+	if err := errors.Join(a.errs...); err != nil {
+		return nil, err
+	}
+	if len(a.actions) == 0 {
+		return nil, fmt.Errorf("%w: no alterations", ErrInvalidDDL)
+	}
+	q := a.client.Query("ALTER TABLE $dataset.$table " + strings.Join(a.actions, ", "))
+	q.SetParams(map[string]any{"$dataset": DatasetID(a.dataset), "$table": TableID(a.table)})
+	return q, nil
+}
+
+// SQL returns the translated ALTER TABLE statement without executing it.
+//
+// Returns an error if an alteration or identifier is not valid.
+func (a *TableAlteration) SQL() (string, error) {
+	q, err := a.query()
+	if err == nil {
+		err = q.translate()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to alter table: %w", err)
+	}
+	return q.QueryConfig.Q, nil
+}
+
+// Exec runs the ALTER TABLE statement.
+//
+// Returns an error if an alteration or identifier is not valid, or if the
+// statement fails.
+func (a *TableAlteration) Exec(ctx context.Context) error {
+	q, err := a.query()
+	if err == nil {
+		_, err = q.Exec(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to alter table: %w", err)
+	}
+	a.client.InvalidateTableSchema(a.dataset, a.table)
+	return nil
+}
@@ -0,0 +1,66 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestClientAlterTable(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	err := client.AlterTable("analytics", "events").
+		AddColumn("country", bigquery.StringFieldType).
+		AddColumn("score", bigquery.FloatFieldType).
+		RenameColumn("ts", "created_at").
+		DropColumn("legacy_id").
+		SetOptions(WithDescription("it's events"), ExpiresAt(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))).
+		Exec(context.Background())
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	expected := "ALTER TABLE `analytics`.`events` ADD COLUMN `country` STRING, ADD COLUMN `score` FLOAT64, " +
+		"RENAME COLUMN `ts` TO `created_at`, DROP COLUMN `legacy_id`, " +
+		"SET OPTIONS (expiration_timestamp = TIMESTAMP '2025-01-01 00:00:00+00:00', description = 'it\\'s events')"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Exec() queries = %q, want %q", queries, expected)
+	}
+}
+
+func TestTableAlterationSQL(t *testing.T) {
+	client := &Client{}
+	tests := []struct {
+		name       string
+		alteration *TableAlteration
+		expected   string
+		err        error
+	}{
+		{"add column", client.AlterTable("analytics", "events").AddColumn("country", bigquery.StringFieldType),
+			"ALTER TABLE `analytics`.`events` ADD COLUMN `country` STRING", nil},
+		{"labels", client.AlterTable("analytics", "events").SetOptions(WithTableLabels(map[string]string{"env": "prod"})),
+			"ALTER TABLE `analytics`.`events` SET OPTIONS (labels = [('env', 'prod')])", nil},
+		{"no alterations", client.AlterTable("analytics", "events"), "", ErrInvalidDDL},
+		{"invalid table", client.AlterTable("analytics", "a.b").DropColumn("id"), "", ErrIdentifierInvalidChars},
+		{"invalid column", client.AlterTable("analytics", "events").DropColumn("id`, DROP COLUMN name"), "", ErrIdentifierInvalidChars},
+		{"invalid rename target", client.AlterTable("analytics", "events").RenameColumn("ts", "_PARTITIONTIME"), "", ErrIdentifierNotAllowed},
+		{"record column", client.AlterTable("analytics", "events").AddColumn("device", bigquery.RecordFieldType), "", ErrInvalidDDL},
+		{"partition option", client.AlterTable("analytics", "events").SetOptions(PartitionBy("day")), "", ErrInvalidDDL},
+		{"empty options", client.AlterTable("analytics", "events").SetOptions(), "", ErrInvalidDDL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := tt.alteration.SQL()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SQL() error = %v, want %v", err, tt.err)
+			}
+			if sql != tt.expected {
+				t.Errorf("SQL() = %q, want %q", sql, tt.expected)
+			}
+		})
+	}
+}
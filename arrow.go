@@ -0,0 +1,155 @@
+package saferbq
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/ipc"
+	"google.golang.org/api/iterator"
+)
+
+// ReadArrow translates and runs the query like ReadStorage, and returns the
+// results as Apache Arrow record batches, downloaded through the BigQuery
+// Storage Read API, for consumers that feed dataframes. The Storage Read
+// API is enabled for the whole client, see WithStorageRead. The caller
+// must release the reader when done.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table")
+//	q.SetParams(map[string]any{"$table": "events_" + tenant})
+//	reader, err := q.ReadArrow(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	defer reader.Release()
+//	for reader.Next() {
+//	    record := reader.Record()
+//	    ...
+//	}
+//	err = reader.Err()
+//
+// Returns an error if the query fails, or if its results can't be read
+// through the Storage Read API (small results are sometimes returned with
+// the query instead).
+func (q *Query) ReadArrow(ctx context.Context) (array.RecordReader, error) {
+	it, err := q.ReadStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	arrowIt, err := it.ArrowIterator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read arrow records: %w", err)
+	}
+	return newArrowReader(arrowIt)
+}
+
+// newArrowReader decodes the record batches of the iterator.
+func newArrowReader(it bigquery.ArrowIterator) (array.RecordReader, error) {
+	reader, err := ipc.NewReader(&arrowStream{it: it})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read arrow records: %w", err)
+	}
+	return reader, nil
+}
+
+// arrowStream reads the serialized schema and record batches of an
+// ArrowIterator as an Arrow IPC stream. Unlike
+// bigquery.NewArrowIteratorReader it returns the errors of the iterator.
+type arrowStream struct {
+	it      bigquery.ArrowIterator
+	buf     bytes.Buffer
+	started bool
+}
+
+// Read implements io.Reader.
+func (s *arrowStream) Read(p []byte) (int, error) {
+	if !s.started {
+		s.buf.Write(s.it.SerializedArrowSchema())
+		s.started = true
+	}
+	for s.buf.Len() == 0 {
+		batch, err := s.it.Next()
+		if err == iterator.Done {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		s.buf.Write(batch.Data)
+	}
+	return s.buf.Read(p)
+}
+
+// ArrowSchema converts the schema to the Arrow schema of the records that
+// the Storage Read API returns for it. Nullable and repeated fields become
+// nullable fields and lists, records become structs, NUMERIC and
+// BIGNUMERIC become decimals, and GEOGRAPHY and JSON become strings.
+//
+// Returns an error wrapping ErrInvalidFormat if a field has a type that
+// has no Arrow equivalent, such as RANGE.
+func ArrowSchema(schema bigquery.Schema) (*arrow.Schema, error) {
+	fields, err := arrowFields(schema)
+	if err != nil {
+		return nil, err
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// arrowFields converts the fields of the schema to Arrow fields.
+func arrowFields(schema bigquery.Schema) ([]arrow.Field, error) {
+	fields := make([]arrow.Field, 0, len(schema))
+	for _, field := range schema {
+		dataType, err := arrowType(field)
+		if err != nil {
+			return nil, err
+		}
+		if field.Repeated {
+			dataType = arrow.ListOf(dataType)
+		}
+		fields = append(fields, arrow.Field{Name: field.Name, Type: dataType, Nullable: !field.Required})
+	}
+	return fields, nil
+}
+
+// arrowType returns the Arrow type of the values of the field.
+func arrowType(field *bigquery.FieldSchema) (arrow.DataType, error) {
+	switch field.Type {
+	case bigquery.StringFieldType, bigquery.GeographyFieldType, bigquery.JSONFieldType:
+		return arrow.BinaryTypes.String, nil
+	case bigquery.BytesFieldType:
+		return arrow.BinaryTypes.Binary, nil
+	case bigquery.IntegerFieldType:
+		return arrow.PrimitiveTypes.Int64, nil
+	case bigquery.FloatFieldType:
+		return arrow.PrimitiveTypes.Float64, nil
+	case bigquery.BooleanFieldType:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case bigquery.TimestampFieldType:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, nil
+	case bigquery.DateFieldType:
+		return arrow.FixedWidthTypes.Date32, nil
+	case bigquery.TimeFieldType:
+		return arrow.FixedWidthTypes.Time64us, nil
+	case bigquery.DateTimeFieldType:
+		return &arrow.TimestampType{Unit: arrow.Microsecond}, nil
+	case bigquery.NumericFieldType:
+		return &arrow.Decimal128Type{Precision: 38, Scale: 9}, nil
+	case bigquery.BigNumericFieldType:
+		return &arrow.Decimal256Type{Precision: 76, Scale: 38}, nil
+	case bigquery.IntervalFieldType:
+		return arrow.FixedWidthTypes.MonthDayNanoInterval, nil
+	case bigquery.RecordFieldType:
+		fields, err := arrowFields(field.Schema)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.StructOf(fields...), nil
+	}
+	return nil, fmt.Errorf("%w: %s column %s has no arrow type", ErrInvalidFormat, field.Type, field.Name)
+}
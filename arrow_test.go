@@ -0,0 +1,117 @@
+package saferbq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/ipc"
+	"github.com/apache/arrow/go/v15/arrow/memory"
+	"google.golang.org/api/iterator"
+)
+
+// fakeArrowIterator returns a serialized Arrow stream as its batches.
+type fakeArrowIterator struct {
+	batches [][]byte
+	err     error
+}
+
+func (it *fakeArrowIterator) Next() (*bigquery.ArrowRecordBatch, error) {
+	if len(it.batches) == 0 {
+		if it.err != nil {
+			return nil, it.err
+		}
+		return nil, iterator.Done
+	}
+	batch := &bigquery.ArrowRecordBatch{Data: it.batches[0]}
+	it.batches = it.batches[1:]
+	return batch, nil
+}
+
+func (it *fakeArrowIterator) Schema() bigquery.Schema {
+	return nil
+}
+
+func (it *fakeArrowIterator) SerializedArrowSchema() []byte {
+	return nil
+}
+
+func TestArrowSchema(t *testing.T) {
+	schema := bigquery.Schema{
+		{Name: "id", Type: bigquery.IntegerFieldType, Required: true},
+		{Name: "created_at", Type: bigquery.TimestampFieldType},
+		{Name: "amount", Type: bigquery.NumericFieldType},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "address", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "city", Type: bigquery.StringFieldType},
+		}},
+	}
+	got, err := ArrowSchema(schema)
+	if err != nil {
+		t.Fatalf("ArrowSchema() unexpected error: %v", err)
+	}
+	expected := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "created_at", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: 38, Scale: 9}, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "address", Type: arrow.StructOf(arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true}), Nullable: true},
+	}, nil)
+	if !got.Equal(expected) {
+		t.Errorf("ArrowSchema() = %v, want %v", got, expected)
+	}
+
+	_, err = ArrowSchema(bigquery.Schema{{Name: "period", Type: bigquery.RangeFieldType}})
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ArrowSchema() error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+func TestNewArrowReader(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	if err := writer.Write(record); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	writer.Close()
+
+	reader, err := newArrowReader(&fakeArrowIterator{batches: [][]byte{buf.Bytes()}})
+	if err != nil {
+		t.Fatalf("newArrowReader() unexpected error: %v", err)
+	}
+	defer reader.Release()
+	rows := int64(0)
+	for reader.Next() {
+		rows += reader.Record().NumRows()
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() unexpected error: %v", err)
+	}
+	if rows != 3 || !reader.Schema().Equal(schema) {
+		t.Errorf("read %d rows with schema %v, want 3 rows with schema %v", rows, reader.Schema(), schema)
+	}
+
+	failure := errors.New("stream failed")
+	if _, err := newArrowReader(&fakeArrowIterator{err: failure}); !errors.Is(err, failure) {
+		t.Errorf("newArrowReader() error = %v, want %v", err, failure)
+	}
+}
+
+func TestQueryReadArrowError(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": ""})
+	if _, err := q.ReadArrow(context.Background()); !errors.Is(err, ErrIdentifierEmpty) {
+		t.Errorf("ReadArrow() error = %v, want %v", err, ErrIdentifierEmpty)
+	}
+}
@@ -0,0 +1,55 @@
+package saferbq
+
+import (
+	"time"
+)
+
+// systemTimeLayout is the layout of the timestamp literal of AsOf
+const systemTimeLayout = "2006-01-02 15:04:05.999999-07:00"
+
+// AsOf returns a $identifier value that reads the table as it was at the
+// given time, using BigQuery's time travel. The table is validated and
+// quoted like any other $identifier value (it may also be a value of this
+// package, such as TableID) and followed by a FOR SYSTEM_TIME AS OF clause
+// with the time formatted as a UTC timestamp literal.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table WHERE id = @id")
+//	q.SetParams(map[string]any{
+//		"$table": saferbq.AsOf("analytics.events", time.Now().Add(-time.Hour)),
+//		"@id":    42,
+//	})
+//	// SELECT * FROM `analytics`.`events` FOR SYSTEM_TIME AS OF
+//	//   TIMESTAMP '2024-01-02 03:04:05.123456+00:00' WHERE id = @id
+func AsOf(table any, at time.Time) AsOfTable {
+	return AsOfTable{table: table, at: at}
+}
+
+// AsOfTable is the $identifier value of a time travel read, see AsOf.
+type AsOfTable struct {
+	table any
+	at    time.Time
+}
+
+// render quotes the table per part and appends the FOR SYSTEM_TIME AS OF
+// clause.
+func (v AsOfTable) render(name string) (string, error) {
+	return v.renderStyle(name, QuotePerPart)
+}
+
+// renderStyle quotes the table in the quote style of the query and appends
+// the FOR SYSTEM_TIME AS OF clause.
+func (v AsOfTable) renderStyle(name string, style QuoteStyle) (string, error) {
+	var quoted string
+	var err error
+	if table, ok := v.table.(identifierValue); ok {
+		quoted, err = table.render(name)
+	} else {
+		quoted, err = quotePath(name, v.table, style)
+	}
+	if err != nil {
+		return "", err
+	}
+	return quoted + " FOR SYSTEM_TIME AS OF TIMESTAMP '" + v.at.UTC().Format(systemTimeLayout) + "'", nil
+}
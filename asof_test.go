@@ -0,0 +1,38 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestAsOf(t *testing.T) {
+	at := time.Date(2024, 1, 2, 4, 4, 5, 123456789, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name   string
+		value  any
+		sqlOut string
+		err    error
+	}{
+		{"table", AsOf("events", at), "SELECT * FROM `events` FOR SYSTEM_TIME AS OF TIMESTAMP '2024-01-02 03:04:05.123456+00:00' WHERE id = 1", nil},
+		{"table path", AsOf("analytics.events", at), "SELECT * FROM `analytics`.`events` FOR SYSTEM_TIME AS OF TIMESTAMP '2024-01-02 03:04:05.123456+00:00' WHERE id = 1", nil},
+		{"whole seconds", AsOf("events", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), "SELECT * FROM `events` FOR SYSTEM_TIME AS OF TIMESTAMP '2024-01-02 03:04:05+00:00' WHERE id = 1", nil},
+		{"identifier kind", AsOf(TableID("events"), at), "SELECT * FROM `events` FOR SYSTEM_TIME AS OF TIMESTAMP '2024-01-02 03:04:05.123456+00:00' WHERE id = 1", nil},
+		{"invalid table", AsOf("events; DROP TABLE x", at), "", ErrIdentifierInvalidChars},
+		{"invalid identifier kind", AsOf(DatasetID("my-dataset"), at), "", ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT * FROM $table WHERE id = 1", []bigquery.QueryParameter{{Name: "$table", Value: tt.value}})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("translate() error = %v, want %v", err, tt.err)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
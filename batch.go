@@ -0,0 +1,45 @@
+package saferbq
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// BatchQuery creates a new Query with batch priority in the background lane.
+// Batch queries are queued by BigQuery until idle resources are available,
+// which suits large backfills that are not time critical.
+//
+// Example:
+//
+//	q := client.BatchQuery("INSERT INTO $table SELECT * FROM $staging")
+//	q.SetParams(map[string]any{"$table": "events", "$staging": "events_staging"})
+//	job, err := q.RunAndWait(ctx)
+func (c *Client) BatchQuery(sql string) *Query {
+	return c.Query(sql).AsBatch()
+}
+
+// AsBatch sets batch priority and the background lane on the query and
+// returns the query to allow chaining.
+func (q *Query) AsBatch() *Query {
+	q.Priority = bigquery.BatchPriority
+	q.Lane = LaneBackground
+	return q
+}
+
+// RunAndWait initiates a query job and polls it until it is done. This is
+// mostly useful for batch queries, which may be queued for a long time.
+// The final status of the job is available with job.LastStatus().
+//
+// Returns an error if parameter validation fails, if the query could not be
+// submitted, or an error wrapping ErrJobFailed if the job itself failed.
+func (q *Query) RunAndWait(ctx context.Context) (*bigquery.Job, error) {
+	job, err := q.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := waitJob(ctx, job); err != nil {
+		return job, err
+	}
+	return job, nil
+}
@@ -0,0 +1,46 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestBatchQuery(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+
+	q := client.BatchQuery("INSERT INTO $table SELECT * FROM $staging")
+	q.SetParams(map[string]any{"$table": "events", "$staging": "events_staging"})
+	if q.Priority != bigquery.BatchPriority || q.Lane != LaneBackground {
+		t.Errorf("BatchQuery() priority = %q, lane = %v, want batch priority in background lane", q.Priority, q.Lane)
+	}
+	job, err := q.RunAndWait(context.Background())
+	if err != nil {
+		t.Fatalf("RunAndWait() unexpected error: %v", err)
+	}
+	if status := job.LastStatus(); status == nil || !status.Done() {
+		t.Errorf("RunAndWait() status = %v, want done", status)
+	}
+	config := fake.jobs[job.ID()]["query"].(map[string]any)
+	if config["priority"] != "BATCH" {
+		t.Errorf("submitted priority = %v, want BATCH", config["priority"])
+	}
+	if config["query"] != "INSERT INTO `events` SELECT * FROM `events_staging`" {
+		t.Errorf("submitted query = %v", config["query"])
+	}
+}
+
+func TestRunAndWaitFailed(t *testing.T) {
+	fake := &fakeBigQuery{errorResult: "invalidQuery"}
+	client := newFakeClient(t, fake)
+	job, err := client.BatchQuery("SELECT 1").RunAndWait(context.Background())
+	if !errors.Is(err, ErrJobFailed) {
+		t.Errorf("RunAndWait() error = %v, want ErrJobFailed", err)
+	}
+	if job == nil {
+		t.Error("RunAndWait() job = nil, want the failed job")
+	}
+}
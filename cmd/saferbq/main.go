@@ -0,0 +1,342 @@
+// Command saferbq validates query templates offline and prints the
+// translated SQL, so templates can be checked in CI before deploy.
+//
+// Usage:
+//
+//	saferbq [-params params.json] [-dry-run -project my-project] query.sql
+//
+// The params file is a JSON object with $identifier and @parameter names as
+// keys. Positional parameters are given as an array under the "?" key:
+//
+//	{"$table": "users", "@status": "active"}
+//	{"$table": "users", "?": [42]}
+//
+// With -dry-run the query is also validated by BigQuery and the estimated
+// number of bytes processed is printed.
+//
+// The manifest subcommand prints a JSON description of the templates in
+// the given files and directories (all *.sql files), see
+// saferbq.Client.TemplateManifest:
+//
+//	saferbq manifest queries/
+//
+// The lint subcommand checks the templates in the given files and
+// directories. Each template is parsed and, when a params file with the same
+// base name exists next to it (query.sql and query.json), translated with
+// those parameters, so declared parameters must match the placeholders of
+// the SQL. With -project every template that translates is also dry-run.
+// Every problem is printed as a finding and the exit code is 1 when there
+// are findings:
+//
+//	saferbq lint [-project my-project] queries/
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/mevdschee/saferbq"
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "manifest":
+			return runManifest(args[1:], stdout, stderr)
+		case "lint":
+			return runLint(ctx, args[1:], stdout, stderr)
+		}
+	}
+	flags := flag.NewFlagSet("saferbq", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	paramsFile := flags.String("params", "", "JSON file with the parameters")
+	dryRun := flags.Bool("dry-run", false, "validate the query with a BigQuery dry run")
+	project := flags.String("project", "", "project ID for the dry run")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: saferbq [-params params.json] [-dry-run -project my-project] query.sql")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || (*dryRun && *project == "") {
+		flags.Usage()
+		return 2
+	}
+	sql, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	named, positional, err := readParams(*paramsFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to read parameters: %v\n", err)
+		return 1
+	}
+	translated, translatedParams, err := saferbq.Translate(string(sql), queryParams(named, positional))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, translated)
+	for _, p := range translatedParams {
+		name := p.Name
+		if name == "" {
+			name = "?"
+		}
+		fmt.Fprintf(stdout, "-- %s = %#v\n", name, p.Value)
+	}
+	if !*dryRun {
+		return 0
+	}
+	client, err := saferbq.NewClient(ctx, *project)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	defer client.Close()
+	q := client.Query(string(sql))
+	q.SetParams(named)
+	q.SetPositionalParams(positional...)
+	stats, err := q.DryRun(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "-- dry run: %d bytes processed\n", stats.TotalBytesProcessed)
+	return 0
+}
+
+// runManifest prints the manifest of the templates in the files and
+// directories and returns the exit code.
+func runManifest(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("saferbq manifest", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: saferbq manifest file.sql|directory ...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	files, err := templateFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	client := (&saferbq.Client{}).Configure(saferbq.WithTemplateManifest())
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		if _, err := client.Prepare(string(sql)); err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", file, err)
+			return 1
+		}
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(client.TemplateManifest()); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runLint prints the findings of the templates in the files and
+// directories and returns the exit code.
+func runLint(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("saferbq lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	project := flags.String("project", "", "project ID to dry-run the templates in")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: saferbq lint [-project my-project] file.sql|directory ...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	files, err := templateFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	var client *saferbq.Client
+	if *project != "" {
+		client, err = saferbq.NewClient(ctx, *project)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		defer client.Close()
+	}
+	findings := 0
+	for _, file := range files {
+		err := lintTemplate(ctx, client, file)
+		if err == nil {
+			continue
+		}
+		// Joined errors are reported one problem per line
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(stdout, "%s: %s\n", file, line)
+			findings++
+		}
+	}
+	if findings > 0 {
+		fmt.Fprintf(stderr, "%d finding(s) in %d template(s)\n", findings, len(files))
+		return 1
+	}
+	return 0
+}
+
+// lintTemplate checks the template file and returns its problems. The
+// template is translated with the parameters of the params file next to it,
+// and it is dry-run when the client is not nil.
+func lintTemplate(ctx context.Context, client *saferbq.Client, file string) error {
+	sql, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	info, err := saferbq.Describe(string(sql))
+	if err != nil {
+		return err
+	}
+	paramsFile := strings.TrimSuffix(file, filepath.Ext(file)) + ".json"
+	named, positional := map[string]any{}, []any(nil)
+	if _, err := os.Stat(paramsFile); err == nil {
+		named, positional, err = readParams(paramsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read parameters: %w", err)
+		}
+	} else if len(info.Identifiers) > 0 || len(info.Parameters) > 0 || info.PositionalParameters > 0 {
+		return fmt.Errorf("placeholders are not declared in %s", filepath.Base(paramsFile))
+	}
+	if _, _, err := saferbq.Translate(string(sql), queryParams(named, positional)); err != nil {
+		return err
+	}
+	if client == nil {
+		return nil
+	}
+	q := client.Query(string(sql))
+	q.SetParams(named)
+	q.SetPositionalParams(positional...)
+	_, err = q.DryRun(ctx)
+	return err
+}
+
+// templateFiles returns the files and the *.sql files in the directories,
+// in lexical order per directory.
+func templateFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && filepath.Ext(file) == ".sql" {
+				files = append(files, file)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// queryParams returns the named parameters, sorted by name so the output is
+// deterministic, followed by the positional parameters.
+func queryParams(named map[string]any, positional []any) []bigquery.QueryParameter {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := []bigquery.QueryParameter{}
+	for _, name := range names {
+		params = append(params, bigquery.QueryParameter{Name: name, Value: named[name]})
+	}
+	for _, value := range positional {
+		params = append(params, bigquery.QueryParameter{Value: value})
+	}
+	return params
+}
+
+// readParams reads the named and positional parameters from the JSON file.
+// Whole numbers are returned as int64, other numbers as float64.
+func readParams(filename string) (map[string]any, []any, error) {
+	named := map[string]any{}
+	if filename == "" {
+		return named, nil, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return nil, nil, err
+	}
+	var positional []any
+	for name, value := range values {
+		if name != "?" {
+			named[name] = convertNumber(value)
+			continue
+		}
+		list, ok := value.([]any)
+		if !ok {
+			return nil, nil, errors.New(`"?" must be an array of positional parameters`)
+		}
+		for _, v := range list {
+			positional = append(positional, convertNumber(v))
+		}
+	}
+	return named, positional, nil
+}
+
+// convertNumber converts a JSON number to int64 or float64.
+func convertNumber(value any) any {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+	f, _ := number.Float64()
+	return f
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mevdschee/saferbq"
+)
+
+// writeFile writes the content to a file in a temporary directory and
+// returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeTree writes the files, by relative path, to a temporary directory and
+// returns the directory.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		params string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "named parameters",
+			sql:    "SELECT * FROM $table WHERE status = @status AND age > @age",
+			params: `{"$table": "users", "@status": "active", "@age": 18}`,
+			stdout: "SELECT * FROM `users` WHERE status = @status AND age > @age\n-- age = 18\n-- status = \"active\"\n",
+		},
+		{
+			name:   "positional parameters",
+			sql:    "SELECT * FROM $table WHERE score > ?",
+			params: `{"$table": "users", "?": [1.5]}`,
+			stdout: "SELECT * FROM `users` WHERE score > ?\n-- ? = 1.5\n",
+		},
+		{
+			name:   "invalid identifier",
+			sql:    "SELECT * FROM $table",
+			params: `{"$table": "users; DROP TABLE x"}`,
+			code:   1,
+			stderr: "identifier contains invalid characters",
+		},
+		{
+			name:   "missing identifier",
+			sql:    "SELECT * FROM $table",
+			params: `{}`,
+			code:   1,
+			stderr: "identifier not provided",
+		},
+		{
+			name:   "invalid positional",
+			sql:    "SELECT ?",
+			params: `{"?": 1}`,
+			code:   1,
+			stderr: "must be an array",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := []string{"-params", writeFile(t, "params.json", tt.params), writeFile(t, "query.sql", tt.sql)}
+			code := run(context.Background(), args, &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("run() = %d, want %d (stderr: %s)", code, tt.code, stderr.String())
+			}
+			if stdout.String() != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.stdout)
+			}
+			if tt.stderr != "" && !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"-dry-run", "query.sql"}, &stdout, &stderr); code != 2 {
+		t.Errorf("run() = %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "usage:") {
+		t.Errorf("stderr = %q, want usage", stderr.String())
+	}
+}
+
+func TestRunManifest(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"users.sql":        "SELECT * FROM $dataset.users WHERE id = @id",
+		"orders/daily.sql": "DELETE FROM orders WHERE day < ?",
+		"README.md":        "not a template",
+	})
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"manifest", dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run() = %d, want 0 (stderr: %s)", code, stderr.String())
+	}
+	var manifest saferbq.Manifest
+	if err := json.Unmarshal(stdout.Bytes(), &manifest); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+	}
+	if len(manifest.Templates) != 2 {
+		t.Fatalf("templates = %d, want 2", len(manifest.Templates))
+	}
+	if got := manifest.Templates[1].Tables; len(got) != 1 || got[0] != "$dataset.users" {
+		t.Errorf("tables = %v, want [$dataset.users]", got)
+	}
+
+	stderr.Reset()
+	path := writeFile(t, "empty.sql", "")
+	if code := run(context.Background(), []string{"manifest", path}, &stdout, &stderr); code != 1 {
+		t.Errorf("run() = %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "empty.sql: failed to prepare query: query SQL cannot be empty") {
+		t.Errorf("stderr = %q, want the file and error", stderr.String())
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"ok.sql":             "SELECT * FROM $table WHERE id = @id",
+		"ok.json":            `{"$table": "users", "@id": 1}`,
+		"static.sql":         "SELECT 1",
+		"bad/missing.sql":    "SELECT * FROM $table WHERE id = @id AND status = @status",
+		"bad/missing.json":   `{"$table": "users", "@id": 1}`,
+		"bad/invalid.sql":    "SELECT * FROM $table",
+		"bad/invalid.json":   `{"$table": "users; DROP TABLE x", "@unused": 1}`,
+		"bad/undeclared.sql": "SELECT * FROM $table",
+	})
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"lint", dir}, &stdout, &stderr); code != 1 {
+		t.Fatalf("run() = %d, want 1 (stderr: %s)", code, stderr.String())
+	}
+	for _, want := range []string{
+		filepath.Join(dir, "bad/missing.sql") + ": parameter not provided in parameters",
+		filepath.Join(dir, "bad/invalid.sql") + ": identifier contains invalid characters",
+		filepath.Join(dir, "bad/invalid.sql") + ": parameter not found in query",
+		filepath.Join(dir, "bad/undeclared.sql") + ": placeholders are not declared in undeclared.json",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout = %q, want it to contain %q", stdout.String(), want)
+		}
+	}
+	if strings.Contains(stdout.String(), "ok.sql") || strings.Contains(stdout.String(), "static.sql") {
+		t.Errorf("stdout = %q, want no findings for valid templates", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "4 finding(s) in 5 template(s)") {
+		t.Errorf("stderr = %q, want the number of findings", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := run(context.Background(), []string{"lint", filepath.Join(dir, "ok.sql")}, &stdout, &stderr); code != 0 {
+		t.Errorf("run() = %d, want 0 (stdout: %s)", code, stdout.String())
+	}
+}
@@ -0,0 +1,93 @@
+package saferbq
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// TableRef is a fully qualified reference to a table.
+type TableRef struct {
+	// ProjectID is the project of the table (the default is the project
+	// of the client)
+	ProjectID string
+	// DatasetID is the dataset of the table
+	DatasetID string
+	// TableID is the name of the table
+	TableID string
+}
+
+// String returns the table reference as project.dataset.table, or as
+// dataset.table when the project is not set.
+func (r TableRef) String() string {
+	if r.ProjectID == "" {
+		return r.DatasetID + "." + r.TableID
+	}
+	return r.ProjectID + "." + r.DatasetID + "." + r.TableID
+}
+
+// table validates the IDs of the reference (with the rules of ProjectID,
+// DatasetID and TableID) and returns a handle to the table. The name is
+// used in error messages.
+func (c *Client) table(name string, r TableRef) (*bigquery.Table, error) {
+	project := r.ProjectID
+	if project == "" {
+		project = c.Project()
+	} else if _, err := ProjectID(project).render(name + " project"); err != nil {
+		return nil, err
+	}
+	if _, err := DatasetID(r.DatasetID).render(name + " dataset"); err != nil {
+		return nil, err
+	}
+	if _, err := TableID(r.TableID).render(name + " table"); err != nil {
+		return nil, err
+	}
+	return c.DatasetInProject(project, r.DatasetID).Table(r.TableID), nil
+}
+
+// CopyConfig configures a copy job of SafeCopy.
+type CopyConfig struct {
+	// WriteDisposition specifies what happens when the destination has data
+	WriteDisposition bigquery.TableWriteDisposition
+	// CreateDisposition specifies whether the destination is created
+	CreateDisposition bigquery.TableCreateDisposition
+	// Labels are the labels of the copy job
+	Labels map[string]string
+}
+
+// SafeCopy returns a copier that copies the source table to the destination
+// table, after validating the project, dataset and table IDs of both
+// references (with the rules of ProjectID, DatasetID and TableID).
+//
+// Example:
+//
+//	copier, err := client.SafeCopy(
+//	    saferbq.TableRef{DatasetID: "staging", TableID: "events_" + day},
+//	    saferbq.TableRef{ProjectID: "analytics-prod", DatasetID: "warehouse", TableID: "events_" + day},
+//	    saferbq.CopyConfig{WriteDisposition: bigquery.WriteTruncate})
+//	if err != nil {
+//	    return err
+//	}
+//	job, err := copier.Run(ctx)
+//
+// Returns an error wrapping one of the identifier errors (such as
+// ErrIdentifierInvalidChars) if a reference is not valid, or
+// ErrInvalidLabel if a label is not valid.
+func (c *Client) SafeCopy(src, dst TableRef, cfg CopyConfig) (*bigquery.Copier, error) {
+	srcTable, err := c.table("source", src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create copier: %w", err)
+	}
+	dstTable, err := c.table("destination", dst)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create copier: %w", err)
+	}
+	if err := validateLabels(cfg.Labels); err != nil {
+		return nil, fmt.Errorf("failed to create copier: %w", err)
+	}
+	copier := dstTable.CopierFrom(srcTable)
+	copier.WriteDisposition = cfg.WriteDisposition
+	copier.CreateDisposition = cfg.CreateDisposition
+	copier.Labels = cfg.Labels
+	return copier, nil
+}
@@ -0,0 +1,79 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestSafeCopy(t *testing.T) {
+	client := newFakeClient(t, &fakeBigQuery{})
+	copier, err := client.SafeCopy(
+		TableRef{DatasetID: "staging", TableID: "events"},
+		TableRef{ProjectID: "analytics-prod", DatasetID: "warehouse", TableID: "events"},
+		CopyConfig{
+			WriteDisposition:  bigquery.WriteTruncate,
+			CreateDisposition: bigquery.CreateNever,
+			Labels:            map[string]string{"pipeline": "promote"},
+		})
+	if err != nil {
+		t.Fatalf("SafeCopy() unexpected error: %v", err)
+	}
+	if len(copier.Srcs) != 1 {
+		t.Fatalf("SafeCopy() sources = %v, want 1 source", copier.Srcs)
+	}
+	src := copier.Srcs[0]
+	if src.ProjectID != "test-project" || src.DatasetID != "staging" || src.TableID != "events" {
+		t.Errorf("SafeCopy() source = %s.%s.%s, want test-project.staging.events", src.ProjectID, src.DatasetID, src.TableID)
+	}
+	if copier.Dst.ProjectID != "analytics-prod" || copier.Dst.DatasetID != "warehouse" || copier.Dst.TableID != "events" {
+		t.Errorf("SafeCopy() destination = %s.%s.%s, want analytics-prod.warehouse.events", copier.Dst.ProjectID, copier.Dst.DatasetID, copier.Dst.TableID)
+	}
+	if copier.WriteDisposition != bigquery.WriteTruncate || copier.CreateDisposition != bigquery.CreateNever {
+		t.Errorf("SafeCopy() dispositions = %s, %s", copier.WriteDisposition, copier.CreateDisposition)
+	}
+	if copier.Labels["pipeline"] != "promote" {
+		t.Errorf("SafeCopy() labels = %v", copier.Labels)
+	}
+}
+
+func TestSafeCopyValidation(t *testing.T) {
+	valid := TableRef{DatasetID: "staging", TableID: "events"}
+	tests := []struct {
+		name    string
+		src     TableRef
+		dst     TableRef
+		err     error
+		message string
+	}{
+		{"invalid source project", TableRef{ProjectID: "Project", DatasetID: "staging", TableID: "events"}, valid, ErrIdentifierInvalidChars, "failed to create copier: identifier contains invalid characters: source project must be a valid project ID"},
+		{"invalid source dataset", TableRef{DatasetID: "my-dataset", TableID: "events"}, valid, ErrIdentifierInvalidChars, "failed to create copier: identifier contains invalid characters: source dataset must be a valid dataset ID"},
+		{"empty destination table", valid, TableRef{DatasetID: "staging"}, ErrIdentifierEmpty, "failed to create copier: identifier is empty: destination table"},
+		{"destination table path", valid, TableRef{DatasetID: "staging", TableID: "other.events"}, ErrIdentifierInvalidChars, "failed to create copier: identifier contains invalid characters: destination table must be a valid table ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copier, err := (&Client{}).SafeCopy(tt.src, tt.dst, CopyConfig{})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SafeCopy() error = %v, want %v", err, tt.err)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("SafeCopy() error = %q, want %q", err.Error(), tt.message)
+			}
+			if copier != nil {
+				t.Errorf("SafeCopy() copier = %v, want nil", copier)
+			}
+		})
+	}
+}
+
+func TestTableRefString(t *testing.T) {
+	if got := (TableRef{DatasetID: "d", TableID: "t"}).String(); got != "d.t" {
+		t.Errorf("String() = %q, want %q", got, "d.t")
+	}
+	if got := (TableRef{ProjectID: "p", DatasetID: "d", TableID: "t"}).String(); got != "p.d.t" {
+		t.Errorf("String() = %q, want %q", got, "p.d.t")
+	}
+}
@@ -0,0 +1,85 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// locationRegex matches BigQuery locations, such as US, EU or europe-west4
+var locationRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*[A-Za-z0-9]$`)
+
+// DatasetOptions configures the CREATE SCHEMA statement of CreateDatasetDDL.
+type DatasetOptions struct {
+	// Location is the location of the dataset, such as US or europe-west4
+	Location string
+	// DefaultTableExpiration is the lifetime of new tables in the dataset,
+	// at least one hour
+	DefaultTableExpiration time.Duration
+	// Description is the description of the dataset
+	Description string
+	// Labels are the labels of the dataset
+	Labels map[string]string
+}
+
+// CreateDatasetDDL creates the dataset, if it does not exist, by running a
+// CREATE SCHEMA IF NOT EXISTS statement. The dataset ID is validated (with
+// the rules of DatasetID) and quoted, the location is validated and the
+// description and labels are escaped as string literals.
+//
+// Example:
+//
+//	err := client.CreateDatasetDDL(ctx, "tenant_"+tenant, saferbq.DatasetOptions{
+//	    Location:               "EU",
+//	    DefaultTableExpiration: 30 * 24 * time.Hour,
+//	    Labels:                 map[string]string{"tenant": tenant},
+//	})
+//	// CREATE SCHEMA IF NOT EXISTS `tenant_acme` OPTIONS (location = 'EU',
+//	//   default_table_expiration_days = 30, labels = [('tenant', 'acme')])
+//
+// Returns an error wrapping ErrInvalidDDL if the options are not valid, an
+// error if the dataset ID is not valid, or an error if the statement fails.
+func (c *Client) CreateDatasetDDL(ctx context.Context, dataset string, opts DatasetOptions) error {
+	sql, err := createDatasetSQL(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create dataset: %w", err)
+	}
+	q := c.Query(sql)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset)})
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create dataset: %w", err)
+	}
+	return nil
+}
+
+// createDatasetSQL renders the CREATE SCHEMA statement, with $dataset as the
+// name of the dataset.
+func createDatasetSQL(opts DatasetOptions) (string, error) {
+	var options []string
+	if opts.Location != "" {
+		if !locationRegex.MatchString(opts.Location) {
+			return "", fmt.Errorf("%w: %q is not a valid location", ErrInvalidDDL, opts.Location)
+		}
+		options = append(options, "location = "+EscapeStringLiteral(opts.Location))
+	}
+	if opts.DefaultTableExpiration != 0 {
+		if opts.DefaultTableExpiration < time.Hour {
+			return "", fmt.Errorf("%w: DefaultTableExpiration must be at least one hour", ErrInvalidDDL)
+		}
+		days := opts.DefaultTableExpiration.Hours() / 24
+		options = append(options, "default_table_expiration_days = "+strconv.FormatFloat(days, 'f', -1, 64))
+	}
+	rest, err := tableOptions{description: opts.Description, labels: opts.Labels}.options()
+	if err != nil {
+		return "", err
+	}
+	options = append(options, rest...)
+	sql := "CREATE SCHEMA IF NOT EXISTS $dataset"
+	if len(options) > 0 {
+		sql += " OPTIONS (" + strings.Join(options, ", ") + ")"
+	}
+	return sql, nil
+}
@@ -0,0 +1,68 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClientCreateDatasetDDL(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	err := client.CreateDatasetDDL(ctx, "tenant_acme", DatasetOptions{
+		Location:               "europe-west4",
+		DefaultTableExpiration: 36 * time.Hour,
+		Description:            "acme's data",
+		Labels:                 map[string]string{"tenant": "acme"},
+	})
+	if err != nil {
+		t.Fatalf("CreateDatasetDDL() unexpected error: %v", err)
+	}
+	if err := client.CreateDatasetDDL(ctx, "scratch", DatasetOptions{}); err != nil {
+		t.Fatalf("CreateDatasetDDL() unexpected error: %v", err)
+	}
+	expected := []string{
+		"CREATE SCHEMA IF NOT EXISTS `tenant_acme` OPTIONS (location = 'europe-west4', default_table_expiration_days = 1.5, " +
+			"description = 'acme\\'s data', labels = [('tenant', 'acme')])",
+		"CREATE SCHEMA IF NOT EXISTS `scratch`",
+	}
+	queries := fake.executedQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("queries = %q, want %q", queries, expected)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], expected[i])
+		}
+	}
+}
+
+func TestClientCreateDatasetDDLValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataset string
+		opts    DatasetOptions
+		err     error
+	}{
+		{"invalid dataset", "tenant-acme", DatasetOptions{}, ErrIdentifierInvalidChars},
+		{"empty dataset", "", DatasetOptions{}, ErrIdentifierEmpty},
+		{"invalid location", "tenant_acme", DatasetOptions{Location: "EU', labels = [('a', 'b')"}, ErrInvalidDDL},
+		{"short expiration", "tenant_acme", DatasetOptions{DefaultTableExpiration: time.Minute}, ErrInvalidDDL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBigQuery{}
+			client := newFakeClient(t, fake)
+			err := client.CreateDatasetDDL(context.Background(), tt.dataset, tt.opts)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("CreateDatasetDDL() error = %v, want %v", err, tt.err)
+			}
+			if queries := fake.executedQueries(); len(queries) != 0 {
+				t.Errorf("CreateDatasetDDL() queries = %q, want none", queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// bigQueryRoles are the predefined IAM roles that can be granted on
+// datasets, tables and views
+var bigQueryRoles = []string{
+	"roles/bigquery.admin",
+	"roles/bigquery.connectionAdmin",
+	"roles/bigquery.connectionUser",
+	"roles/bigquery.dataEditor",
+	"roles/bigquery.dataOwner",
+	"roles/bigquery.dataViewer",
+	"roles/bigquery.filteredDataViewer",
+	"roles/bigquery.jobUser",
+	"roles/bigquery.metadataViewer",
+	"roles/bigquery.readSessionUser",
+	"roles/bigquery.resourceAdmin",
+	"roles/bigquery.resourceEditor",
+	"roles/bigquery.resourceViewer",
+	"roles/bigquery.user",
+}
+
+// customRoleRegex matches custom roles of a project or an organization
+var customRoleRegex = regexp.MustCompile(`^(projects/[a-z][a-z0-9-]{4,28}[a-z0-9]|organizations/[0-9]+)/roles/[A-Za-z0-9_.]{3,64}$`)
+
+// Privilege builds a GRANT or REVOKE statement. Use Grant or Revoke to
+// create one, On to select the dataset or table, and To or From to add the
+// principals. Invalid roles, resources or principals are reported by SQL
+// and Exec.
+type Privilege struct {
+	revoke     bool
+	role       string
+	resource   TableRef
+	principals []string
+}
+
+// Grant returns a builder for a GRANT statement of the role. The role must
+// be a predefined BigQuery role, such as roles/bigquery.dataViewer, or a
+// custom role, such as projects/my-project/roles/reader.
+//
+// Example:
+//
+//	err := saferbq.Grant("roles/bigquery.dataViewer").
+//	    On(saferbq.TableRef{DatasetID: "sales", TableID: "orders_" + tenant}).
+//	    To("user:" + email).
+//	    Exec(ctx, client)
+//	// GRANT `roles/bigquery.dataViewer` ON TABLE `sales`.`orders_acme` TO 'user:alice@example.com'
+func Grant(role string) *Privilege {
+	return &Privilege{role: role}
+}
+
+// Revoke returns a builder for a REVOKE statement of the role, see Grant.
+//
+// Example:
+//
+//	err := saferbq.Revoke("roles/bigquery.dataViewer").
+//	    On(saferbq.TableRef{DatasetID: "sales"}).
+//	    From("group:contractors@example.com").
+//	    Exec(ctx, client)
+//	// REVOKE `roles/bigquery.dataViewer` ON SCHEMA `sales` FROM 'group:contractors@example.com'
+func Revoke(role string) *Privilege {
+	return &Privilege{revoke: true, role: role}
+}
+
+// On sets the resource of the privilege: a table (or view), or a dataset
+// when the TableID of the reference is empty. The IDs are validated with
+// the rules of ProjectID, DatasetID and TableID.
+func (p *Privilege) On(resource TableRef) *Privilege {
+	p.resource = resource
+	return p
+}
+
+// To adds the principals that the role is granted to. Principals must be
+// IAM members, such as user:alice@example.com, group:sales@example.com,
+// serviceAccount:etl@my-project.iam.gserviceaccount.com or
+// domain:example.com.
+func (p *Privilege) To(principals ...string) *Privilege {
+	p.principals = append(p.principals, principals...)
+	return p
+}
+
+// From adds the principals that the role is revoked from, see To.
+func (p *Privilege) From(principals ...string) *Privilege {
+	return p.To(principals...)
+}
+
+// statement renders the statement with $ placeholders for the role and the
+// resource, and returns the values of the placeholders. When redact is set,
+// invalid principals are left out of the error.
+func (p *Privilege) statement(redact bool) (string, []bigquery.QueryParameter, error) {
+	if !slices.Contains(bigQueryRoles, p.role) && !customRoleRegex.MatchString(p.role) {
+		return "", nil, fmt.Errorf("%w: %q is not a BigQuery role", ErrIdentifierNotAllowed, p.role)
+	}
+	if len(p.principals) == 0 {
+		return "", nil, fmt.Errorf("%w: no principals", ErrInvalidDDL)
+	}
+	principals := make([]string, len(p.principals))
+	for i, principal := range p.principals {
+		if err := validatePrincipal(principal, redact); err != nil {
+			return "", nil, err
+		}
+		principals[i] = EscapeStringLiteral(principal)
+	}
+	params := []bigquery.QueryParameter{{Name: "$role", Value: p.role}}
+	resource := "$dataset"
+	params = append(params, bigquery.QueryParameter{Name: "$dataset", Value: DatasetID(p.resource.DatasetID)})
+	if p.resource.ProjectID != "" {
+		resource = "$project." + resource
+		params = append(params, bigquery.QueryParameter{Name: "$project", Value: ProjectID(p.resource.ProjectID)})
+	}
+	if p.resource.TableID != "" {
+		resource = "TABLE " + resource + ".$table"
+		params = append(params, bigquery.QueryParameter{Name: "$table", Value: TableID(p.resource.TableID)})
+	} else {
+		resource = "SCHEMA " + resource
+	}
+	sql := "GRANT $role ON " + resource + " TO " + strings.Join(principals, ", ")
+	if p.revoke {
+		sql = "REVOKE $role ON " + resource + " FROM " + strings.Join(principals, ", ")
+	}
+	return sql, params, nil
+}
+
+// SQL returns the translated statement without executing it.
+//
+// Returns an error wrapping ErrIdentifierNotAllowed if the role is not a
+// BigQuery role, an error wrapping ErrInvalidPrincipal if a principal is
+// not valid, or an error if the resource is not valid.
+func (p *Privilege) SQL() (string, error) {
+	sql, params, err := p.statement(false)
+	if err == nil {
+		sql, _, err = translate(sql, params)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to %s: %w", p.verb(), err)
+	}
+	return sql, nil
+}
+
+// Exec runs the statement with the client.
+//
+// Returns the errors of SQL, or an error if the statement fails.
+func (p *Privilege) Exec(ctx context.Context, client *Client) error {
+	sql, params, err := p.statement(client.redactValues)
+	if err == nil {
+		q := client.Query(sql)
+		q.Parameters = params
+		_, err = q.Exec(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", p.verb(), err)
+	}
+	return nil
+}
+
+// verb returns the name of the statement for error messages.
+func (p *Privilege) verb() string {
+	if p.revoke {
+		return "revoke"
+	}
+	return "grant"
+}
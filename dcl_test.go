@@ -0,0 +1,74 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPrivilegeExec(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	err := Grant("roles/bigquery.dataViewer").
+		On(TableRef{ProjectID: "analytics-prod", DatasetID: "sales", TableID: "orders"}).
+		To("user:alice@example.com", "group:sales@example.com").
+		Exec(ctx, client)
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	err = Revoke("projects/analytics-prod/roles/reader").
+		On(TableRef{DatasetID: "sales"}).
+		From("domain:example.com").
+		Exec(ctx, client)
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	expected := []string{
+		"GRANT `roles/bigquery.dataViewer` ON TABLE `analytics-prod`.`sales`.`orders` TO 'user:alice@example.com', 'group:sales@example.com'",
+		"REVOKE `projects/analytics-prod/roles/reader` ON SCHEMA `sales` FROM 'domain:example.com'",
+	}
+	queries := fake.executedQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("queries = %q, want %q", queries, expected)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], expected[i])
+		}
+	}
+}
+
+func TestPrivilegeSQL(t *testing.T) {
+	orders := TableRef{DatasetID: "sales", TableID: "orders"}
+	tests := []struct {
+		name      string
+		privilege *Privilege
+		expected  string
+		err       error
+	}{
+		{"grant on table", Grant("roles/bigquery.dataEditor").On(orders).To("serviceAccount:etl@my-project.iam.gserviceaccount.com"),
+			"GRANT `roles/bigquery.dataEditor` ON TABLE `sales`.`orders` TO 'serviceAccount:etl@my-project.iam.gserviceaccount.com'", nil},
+		{"revoke on dataset", Revoke("roles/bigquery.dataViewer").On(TableRef{DatasetID: "sales"}).From("allAuthenticatedUsers"),
+			"REVOKE `roles/bigquery.dataViewer` ON SCHEMA `sales` FROM 'allAuthenticatedUsers'", nil},
+		{"unknown role", Grant("roles/owner").On(orders).To("user:alice@example.com"), "", ErrIdentifierNotAllowed},
+		{"injected role", Grant("roles/bigquery.dataViewer` ON SCHEMA x TO 'allUsers' --").On(orders).To("user:alice@example.com"), "", ErrIdentifierNotAllowed},
+		{"invalid principal", Grant("roles/bigquery.dataViewer").On(orders).To("user:alice@example.com' --"), "", ErrInvalidPrincipal},
+		{"no principals", Grant("roles/bigquery.dataViewer").On(orders), "", ErrInvalidDDL},
+		{"no resource", Grant("roles/bigquery.dataViewer").To("allUsers"), "", ErrIdentifierEmpty},
+		{"invalid dataset", Grant("roles/bigquery.dataViewer").On(TableRef{DatasetID: "sales.x"}).To("allUsers"), "", ErrIdentifierInvalidChars},
+		{"invalid project", Grant("roles/bigquery.dataViewer").On(TableRef{ProjectID: "P", DatasetID: "sales"}).To("allUsers"), "", ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := tt.privilege.SQL()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SQL() error = %v, want %v", err, tt.err)
+			}
+			if sql != tt.expected {
+				t.Errorf("SQL() = %q, want %q", sql, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// sessionIDProperty is the connection property that selects a session
+const sessionIDProperty = "session_id"
+
+// DMLThenSelect runs the DML query, waits for it to complete and then runs
+// the select query in the same location (and session, if the DML query ran
+// in one). This encapsulates the ordering and error handling of write-then-
+// read flows, so the select always observes the effects of the DML query.
+//
+// Example:
+//
+//	dml := client.Query("UPDATE $table SET status = @status WHERE id = @id")
+//	dml.SetParams(map[string]any{"$table": "users", "@status": "active", "@id": 1})
+//	sel := client.Query("SELECT * FROM $table WHERE id = @id")
+//	sel.SetParams(map[string]any{"$table": "users", "@id": 1})
+//	affected, it, err := client.DMLThenSelect(ctx, dml, sel)
+//
+// Returns the number of rows affected by the DML query and the results of
+// the select query, or an error if either query fails.
+func (c *Client) DMLThenSelect(ctx context.Context, dml *Query, sel *Query) (int64, *bigquery.RowIterator, error) {
+	job, err := dml.Run(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to run DML query: %w", err)
+	}
+	status, err := waitJob(ctx, job)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to run DML query: %w", err)
+	}
+	affected := newDMLResult(status).RowsAffected
+	// Run the select in the same location and session as the DML query
+	if sel.Location == "" {
+		sel.Location = job.Location()
+	}
+	if stats := status.Statistics; stats != nil && stats.SessionInfo != nil && !hasConnectionProperty(sel, sessionIDProperty) {
+		sel.ConnectionProperties = append(sel.ConnectionProperties, &bigquery.ConnectionProperty{
+			Key:   sessionIDProperty,
+			Value: stats.SessionInfo.SessionID,
+		})
+	}
+	selJob, err := sel.Run(ctx)
+	if err != nil {
+		return affected, nil, fmt.Errorf("failed to run select query: %w", err)
+	}
+	it, err := selJob.Read(ctx)
+	if err != nil {
+		return affected, nil, fmt.Errorf("failed to run select query: %w", err)
+	}
+	return affected, it, nil
+}
+
+// DMLResult is the result of a statement that was run with Exec.
+type DMLResult struct {
+	// JobID is the ID of the job that ran the statement
+	JobID string
+	// RowsAffected is the number of rows affected by a DML statement
+	RowsAffected int64
+	// RowsInserted is the number of rows inserted by a DML statement
+	RowsInserted int64
+	// RowsUpdated is the number of rows updated by a DML statement
+	RowsUpdated int64
+	// RowsDeleted is the number of rows deleted by a DML statement
+	RowsDeleted int64
+}
+
+// Exec runs a DML or DDL statement, waits for it to complete and returns the
+// numbers of inserted, updated and deleted rows from the job statistics (all
+// 0 for DDL), mirroring database/sql's Exec. It removes the Run/Wait/Status
+// boilerplate from write paths.
+//
+// Example:
+//
+//	q := client.Query("DELETE FROM $table WHERE created_at < @cutoff")
+//	q.SetParams(map[string]any{"$table": "events", "@cutoff": cutoff})
+//	result, err := q.Exec(ctx)
+//	if err == nil {
+//	    log.Printf("deleted %d rows", result.RowsDeleted)
+//	}
+//
+// Failed jobs are resubmitted when the error is retryable, see WithRetry.
+//
+// Returns an error if parameter validation fails, if the query could not be
+// submitted, or an error wrapping ErrJobFailed if the job itself failed.
+func (q *Query) Exec(ctx context.Context) (*DMLResult, error) {
+	var job *bigquery.Job
+	var status *bigquery.JobStatus
+	err := q.retry(ctx, func() (err error) {
+		job, err = q.Run(ctx)
+		if err != nil {
+			// Run already retried the submission
+			return permanentError{err}
+		}
+		status, err = waitJob(ctx, job)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := newDMLResult(status)
+	result.JobID = job.ID()
+	return result, nil
+}
+
+// waitJob waits for the job to complete and returns its final status.
+// Returns an error if waiting fails, or an error wrapping ErrJobFailed
+// if the job failed.
+func waitJob(ctx context.Context, job *bigquery.Job) (*bigquery.JobStatus, error) {
+	status, err := job.Wait(ctx)
+	if err != nil {
+		// BigQuery reports the error of a failed query job when waiting
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) {
+			return nil, fmt.Errorf("%w: %w", ErrJobFailed, err)
+		}
+		return nil, err
+	}
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJobFailed, err)
+	}
+	return status, nil
+}
+
+// newDMLResult returns the row counts of a DML job, or a result with all
+// counts 0 for other jobs.
+func newDMLResult(status *bigquery.JobStatus) *DMLResult {
+	result := &DMLResult{}
+	if status.Statistics == nil {
+		return result
+	}
+	details, ok := status.Statistics.Details.(*bigquery.QueryStatistics)
+	if !ok {
+		return result
+	}
+	result.RowsAffected = details.NumDMLAffectedRows
+	if stats := details.DMLStats; stats != nil {
+		result.RowsInserted = stats.InsertedRowCount
+		result.RowsUpdated = stats.UpdatedRowCount
+		result.RowsDeleted = stats.DeletedRowCount
+	}
+	return result
+}
+
+// hasConnectionProperty checks whether the query has the connection property set.
+func hasConnectionProperty(q *Query, key string) bool {
+	for _, p := range q.ConnectionProperties {
+		if p != nil && p.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+func TestClientDMLThenSelect(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:     []map[string]any{{"name": "id", "type": "INTEGER"}},
+		rows:       [][]any{{"1"}},
+		statistics: map[string]any{"numDmlAffectedRows": "3"},
+		sessionID:  "session-1",
+	}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	dml := client.Query("UPDATE $table SET status = @status WHERE id = @id")
+	dml.SetParams(map[string]any{"$table": "users", "@status": "active", "@id": 1})
+	sel := client.Query("SELECT id FROM $table WHERE id = @id")
+	sel.SetParams(map[string]any{"$table": "users", "@id": 1})
+
+	affected, it, err := client.DMLThenSelect(ctx, dml, sel)
+	if err != nil {
+		t.Fatalf("DMLThenSelect() unexpected error: %v", err)
+	}
+	if affected != 3 {
+		t.Errorf("DMLThenSelect() affected = %d, want 3", affected)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	if len(row) != 1 || row[0] != int64(1) {
+		t.Errorf("Next() row = %v, want [1]", row)
+	}
+	if err := it.Next(&row); err != iterator.Done {
+		t.Errorf("Next() error = %v, want iterator.Done", err)
+	}
+
+	queries := fake.executedQueries()
+	expected := []string{
+		"UPDATE `users` SET status = @status WHERE id = @id",
+		"SELECT id FROM `users` WHERE id = @id",
+	}
+	if len(queries) != 2 || queries[0] != expected[0] || queries[1] != expected[1] {
+		t.Errorf("executed queries = %q, want %q", queries, expected)
+	}
+	if sel.Location != "US" {
+		t.Errorf("select Location = %q, want US", sel.Location)
+	}
+	if !hasConnectionProperty(sel, "session_id") {
+		t.Error("select should run in the session of the DML query")
+	}
+}
+
+func TestClientDMLThenSelectWithoutStatistics(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:       []map[string]any{{"name": "id", "type": "INTEGER"}},
+		rows:         [][]any{{"1"}},
+		noStatistics: true,
+	}
+	client := newFakeClient(t, fake)
+
+	dml := client.Query("DELETE FROM $table WHERE true")
+	dml.SetParams(map[string]any{"$table": "users"})
+	sel := client.Query("SELECT id FROM $table")
+	sel.SetParams(map[string]any{"$table": "users"})
+	affected, it, err := client.DMLThenSelect(context.Background(), dml, sel)
+	if err != nil {
+		t.Fatalf("DMLThenSelect() unexpected error: %v", err)
+	}
+	if affected != 0 || it == nil {
+		t.Errorf("DMLThenSelect() affected = %d, iterator = %v, want 0 and an iterator", affected, it)
+	}
+	if hasConnectionProperty(sel, "session_id") {
+		t.Error("select should not get a session without statistics")
+	}
+}
+
+func TestClientDMLThenSelectError(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	dml := client.Query("DELETE FROM $table WHERE true")
+	sel := client.Query("SELECT * FROM $table")
+	_, _, err := client.DMLThenSelect(ctx, dml, sel)
+	if !errors.Is(err, ErrIdentifierNotProvided) {
+		t.Errorf("DMLThenSelect() error = %v, want ErrIdentifierNotProvided", err)
+	}
+
+	dml = client.Query("DELETE FROM $table WHERE true")
+	dml.SetParams(map[string]any{"$table": "users"})
+	sel = client.Query("SELECT * FROM $table")
+	affected, _, err := client.DMLThenSelect(ctx, dml, sel)
+	if !errors.Is(err, ErrIdentifierNotProvided) {
+		t.Errorf("DMLThenSelect() error = %v, want ErrIdentifierNotProvided", err)
+	}
+	if affected != 0 {
+		t.Errorf("DMLThenSelect() affected = %d, want 0", affected)
+	}
+
+	fake.errorResult = "invalidQuery"
+	dml = client.Query("DELETE FROM $table WHERE true")
+	dml.SetParams(map[string]any{"$table": "users"})
+	_, _, err = client.DMLThenSelect(ctx, dml, sel)
+	if !errors.Is(err, ErrJobFailed) {
+		t.Errorf("DMLThenSelect() error = %v, want ErrJobFailed", err)
+	}
+}
+
+func TestQueryExec(t *testing.T) {
+	fake := &fakeBigQuery{statistics: map[string]any{
+		"numDmlAffectedRows": "7",
+		"dmlStats":           map[string]any{"insertedRowCount": "1", "updatedRowCount": "2", "deletedRowCount": "4"},
+	}}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("MERGE $table USING $staging ON FALSE WHEN NOT MATCHED THEN INSERT ROW")
+	q.SetParams(map[string]any{"$table": "events", "$staging": "staging"})
+	result, err := q.Exec(ctx)
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if result.JobID == "" {
+		t.Error("Exec() result has no job ID")
+	}
+	result.JobID = ""
+	expected := DMLResult{RowsAffected: 7, RowsInserted: 1, RowsUpdated: 2, RowsDeleted: 4}
+	if *result != expected {
+		t.Errorf("Exec() = %+v, want %+v", *result, expected)
+	}
+
+	// DDL statements affect no rows
+	fake.statistics = nil
+	q = client.Query("DROP TABLE $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	result, err = q.Exec(ctx)
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if result.RowsAffected != 0 || result.RowsInserted != 0 || result.RowsUpdated != 0 || result.RowsDeleted != 0 {
+		t.Errorf("Exec() = %+v, want no rows", *result)
+	}
+}
+
+func TestQueryExecError(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("DROP TABLE $table")
+	q.SetParams(map[string]any{"$table": "events;"})
+	_, err := q.Exec(ctx)
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("Exec() error = %v, want ErrIdentifierInvalidChars", err)
+	}
+
+	fake.errorResult = "invalidQuery"
+	q = client.Query("DROP TABLE $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	_, err = q.Exec(ctx)
+	if !errors.Is(err, ErrJobFailed) {
+		t.Errorf("Exec() error = %v, want ErrJobFailed", err)
+	}
+}
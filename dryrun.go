@@ -0,0 +1,200 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// DryRun validates the query with BigQuery without executing it.
+// It translates all $identifier parameters and submits a copy of the query
+// with DryRun enabled, so the Query itself can still be run afterwards.
+//
+// The returned statistics contain the estimated bytes processed
+// (TotalBytesProcessed) and the result schema in the QueryStatistics
+// details, so expensive dynamic queries can be gated before execution.
+//
+// Example:
+//
+//	stats, err := q.DryRun(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if stats.TotalBytesProcessed > 10<<30 {
+//	    log.Fatal("query would scan more than 10 GiB")
+//	}
+//
+// Returns an error if parameter validation fails or if BigQuery
+// rejects the query.
+func (q *Query) DryRun(ctx context.Context) (*bigquery.JobStatistics, error) {
+	// Apply translation
+	if err := q.translate(); err != nil {
+		return nil, err
+	}
+	// Run a copy of the query as dry run
+	dryRun := q.Query
+	dryRun.DryRun = true
+	job, err := dryRun.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := job.LastStatus()
+	if status == nil || status.Statistics == nil {
+		return nil, ErrDryRunFailed
+	}
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDryRunFailed, err)
+	}
+	return status.Statistics, nil
+}
+
+// WithMaxScanBytes refuses to run queries that are estimated to process
+// more than n bytes. Before every Run and Read a dry run is performed and
+// ErrQueryTooExpensive is returned when the estimate exceeds the limit.
+// A limit of zero (or less) disables the guard.
+//
+// Dynamic table names make cost surprises more likely, as a single
+// misbound $table may point to a much larger table than intended.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithMaxScanBytes(100 << 30)) // 100 GiB
+func WithMaxScanBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxScanBytes = n
+	}
+}
+
+// NoMaxBytesBilled is the MaxBytesBilled of a query that removes the limit
+// of WithMaxBytesBilled, so only the project default applies.
+const NoMaxBytesBilled int64 = -1
+
+// WithMaxBytesBilled sets the maximum bytes billed (MaxBytesBilled) of
+// every query of the client that doesn't set its own, so a single misbound
+// $table can't accidentally scan a huge table: BigQuery fails the job
+// instead of billing more. The limit is applied when the query is submitted
+// and its MaxBytesBilled is zero. A query can override the limit by setting
+// its MaxBytesBilled, or remove it by setting NoMaxBytesBilled. A limit of
+// zero (or less) leaves the project default.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithMaxBytesBilled(1 << 40)) // 1 TiB
+//
+//	q := client.Query("SELECT * FROM $table")
+//	q.MaxBytesBilled = 10 << 40 // this query may scan more
+//	q.MaxBytesBilled = saferbq.NoMaxBytesBilled // or as much as the project allows
+func WithMaxBytesBilled(n int64) Option {
+	return func(c *Client) {
+		c.maxBytesBilled = max(n, 0)
+	}
+}
+
+// applyMaxBytesBilled sets the maximum bytes billed of the client when the
+// query doesn't set its own, and clears NoMaxBytesBilled (which BigQuery
+// would reject). It returns the function that restores the limit of the
+// query again.
+func (q *Query) applyMaxBytesBilled() func() {
+	limit := q.MaxBytesBilled
+	switch {
+	case limit < 0:
+		q.MaxBytesBilled = 0
+	case limit == 0 && q.client != nil:
+		q.MaxBytesBilled = q.client.maxBytesBilled
+	}
+	return func() {
+		q.MaxBytesBilled = limit
+	}
+}
+
+// checkScanBytes performs a dry run when the client has a maximum scan size
+// configured and returns ErrQueryTooExpensive when the estimate exceeds it.
+func (q *Query) checkScanBytes(ctx context.Context) error {
+	if q.client == nil || q.client.maxScanBytes <= 0 || q.QueryConfig.DryRun {
+		return nil
+	}
+	stats, err := q.DryRun(ctx)
+	if err != nil {
+		return err
+	}
+	if stats.TotalBytesProcessed > q.client.maxScanBytes {
+		return fmt.Errorf("%w: estimated %d bytes, maximum is %d bytes", ErrQueryTooExpensive, stats.TotalBytesProcessed, q.client.maxScanBytes)
+	}
+	return nil
+}
+
+// Schema returns the schema of the query results without executing the
+// query. It performs a dry run, so API layers can publish the response
+// contract (for example an OpenAPI or Arrow schema) before streaming rows.
+//
+// Example:
+//
+//	schema, err := q.Schema(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, field := range schema {
+//	    fmt.Println(field.Name, field.Type)
+//	}
+//
+// Returns an error if parameter validation fails or if BigQuery
+// rejects the query.
+func (q *Query) Schema(ctx context.Context) (bigquery.Schema, error) {
+	stats, err := q.DryRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details, ok := stats.Details.(*bigquery.QueryStatistics)
+	if !ok {
+		return nil, fmt.Errorf("%w: no query statistics returned", ErrDryRunFailed)
+	}
+	return details.Schema, nil
+}
+
+// Validation is the result of validating a query with Validate.
+type Validation struct {
+	// SQL is the translated SQL that was validated
+	SQL string
+	// Schema is the schema of the query results
+	Schema bigquery.Schema
+	// ReferencedTables are the tables that the query reads
+	ReferencedTables []*bigquery.Table
+	// TotalBytesProcessed is the estimated number of bytes the query scans
+	TotalBytesProcessed int64
+}
+
+// Validate translates the query and validates it with a dry run, without
+// executing it, and returns the translated SQL, the result schema and the
+// referenced tables. It lets deploy-time checks verify dynamic queries
+// (the tables their identifiers resolve to and the columns they return)
+// before their first execution.
+//
+// Example:
+//
+//	v, err := q.Validate(ctx)
+//	if err != nil {
+//	    log.Fatalf("invalid report query: %v", err)
+//	}
+//	for _, table := range v.ReferencedTables {
+//	    fmt.Println(table.FullyQualifiedName())
+//	}
+//
+// Returns an error if parameter validation fails or if BigQuery
+// rejects the query.
+func (q *Query) Validate(ctx context.Context) (*Validation, error) {
+	stats, err := q.DryRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details, ok := stats.Details.(*bigquery.QueryStatistics)
+	if !ok {
+		return nil, fmt.Errorf("%w: no query statistics returned", ErrDryRunFailed)
+	}
+	return &Validation{
+		SQL:                 q.QueryConfig.Q,
+		Schema:              details.Schema,
+		ReferencedTables:    details.ReferencedTables,
+		TotalBytesProcessed: stats.TotalBytesProcessed,
+	}, nil
+}
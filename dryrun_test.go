@@ -0,0 +1,216 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestQueryDryRun(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:     []map[string]any{{"name": "id", "type": "INTEGER"}},
+		statistics: map[string]any{"totalBytesProcessed": "1234"},
+	}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("SELECT id FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}}
+
+	stats, err := q.DryRun(ctx)
+	if err != nil {
+		t.Fatalf("DryRun() unexpected error: %v", err)
+	}
+	if stats.TotalBytesProcessed != 1234 {
+		t.Errorf("DryRun() TotalBytesProcessed = %d, want 1234", stats.TotalBytesProcessed)
+	}
+	details, ok := stats.Details.(*bigquery.QueryStatistics)
+	if !ok || len(details.Schema) != 1 || details.Schema[0].Name != "id" {
+		t.Errorf("DryRun() Details = %v, want schema with id field", stats.Details)
+	}
+	if q.QueryConfig.DryRun {
+		t.Error("DryRun() should not enable DryRun on the query itself")
+	}
+
+	// The query can still be run after a dry run
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	queries := fake.executedQueries()
+	if len(queries) != 2 || queries[0] != "SELECT id FROM `mytable`" || queries[1] != queries[0] {
+		t.Errorf("executed queries = %q, want translated query twice", queries)
+	}
+}
+
+func TestQueryDryRunError(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("SELECT id FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "my;table"}}
+	_, err := q.DryRun(ctx)
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("DryRun() error = %v, want ErrIdentifierInvalidChars", err)
+	}
+
+	fake.errorResult = "invalidQuery"
+	q = client.Query("SELECT id FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}}
+	_, err = q.DryRun(ctx)
+	if !errors.Is(err, ErrDryRunFailed) {
+		t.Errorf("DryRun() error = %v, want ErrDryRunFailed", err)
+	}
+	if len(fake.executedQueries()) != 1 {
+		t.Errorf("executed queries = %q, want 1 query", fake.executedQueries())
+	}
+}
+
+func TestQuerySchema(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{
+			{"name": "id", "type": "INTEGER"},
+			{"name": "name", "type": "STRING"},
+		},
+	}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("SELECT id, name FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}}
+	schema, err := q.Schema(ctx)
+	if err != nil {
+		t.Fatalf("Schema() unexpected error: %v", err)
+	}
+	if len(schema) != 2 || schema[0].Name != "id" || schema[0].Type != bigquery.IntegerFieldType ||
+		schema[1].Name != "name" || schema[1].Type != bigquery.StringFieldType {
+		t.Errorf("Schema() = %v, want id INTEGER, name STRING", schema)
+	}
+	if q.QueryConfig.DryRun {
+		t.Error("Schema() should not enable DryRun on the query itself")
+	}
+
+	q = client.Query("SELECT id FROM $table")
+	if _, err := q.Schema(ctx); !errors.Is(err, ErrIdentifierNotProvided) {
+		t.Errorf("Schema() error = %v, want ErrIdentifierNotProvided", err)
+	}
+}
+
+func TestWithMaxScanBytes(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:     []map[string]any{{"name": "id", "type": "INTEGER"}},
+		statistics: map[string]any{"totalBytesProcessed": "2000"},
+	}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	client.Configure(WithMaxScanBytes(1000))
+	q := client.Query("SELECT id FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}}
+	_, err := q.Run(ctx)
+	if !errors.Is(err, ErrQueryTooExpensive) {
+		t.Errorf("Run() error = %v, want ErrQueryTooExpensive", err)
+	}
+	_, err = q.Read(ctx)
+	if !errors.Is(err, ErrQueryTooExpensive) {
+		t.Errorf("Read() error = %v, want ErrQueryTooExpensive", err)
+	}
+	if len(fake.executedQueries()) != 2 {
+		t.Errorf("executed queries = %q, want only the 2 dry runs", fake.executedQueries())
+	}
+
+	client.Configure(WithMaxScanBytes(5000))
+	if _, err := q.Read(ctx); err != nil {
+		t.Errorf("Read() unexpected error: %v", err)
+	}
+	if len(fake.executedQueries()) != 4 {
+		t.Errorf("executed queries = %q, want a dry run and the query", fake.executedQueries())
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{{"name": "id", "type": "INTEGER"}},
+		statistics: map[string]any{
+			"totalBytesProcessed": "2048",
+			"referencedTables": []map[string]any{
+				{"projectId": "test-project", "datasetId": "analytics", "tableId": "events"},
+			},
+		},
+	}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("SELECT id FROM $table")
+	q.SetParams(map[string]any{"$table": "analytics.events"})
+	v, err := q.Validate(ctx)
+	if err != nil {
+		t.Fatalf("Validate() unexpected error: %v", err)
+	}
+	if v.SQL != "SELECT id FROM `analytics`.`events`" {
+		t.Errorf("Validate() SQL = %q", v.SQL)
+	}
+	if len(v.Schema) != 1 || v.Schema[0].Name != "id" {
+		t.Errorf("Validate() schema = %v, want id", v.Schema)
+	}
+	if len(v.ReferencedTables) != 1 || v.ReferencedTables[0].TableID != "events" || v.ReferencedTables[0].DatasetID != "analytics" {
+		t.Errorf("Validate() referenced tables = %v, want analytics.events", v.ReferencedTables)
+	}
+	if v.TotalBytesProcessed != 2048 {
+		t.Errorf("Validate() bytes = %d, want 2048", v.TotalBytesProcessed)
+	}
+
+	q = client.Query("SELECT id FROM $table")
+	if _, err := q.Validate(ctx); !errors.Is(err, ErrIdentifierNotProvided) {
+		t.Errorf("Validate() error = %v, want ErrIdentifierNotProvided", err)
+	}
+}
+
+func TestWithMaxBytesBilled(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	client.Configure(WithMaxBytesBilled(1 << 30))
+	ctx := context.Background()
+
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	job, err := q.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	config := fake.jobs[job.ID()]["query"].(map[string]any)
+	if config["maximumBytesBilled"] != "1073741824" {
+		t.Errorf("maximumBytesBilled = %v, want 1073741824", config["maximumBytesBilled"])
+	}
+
+	// The limit can be overridden per query
+	q = client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	q.MaxBytesBilled = 2 << 30
+	job, err = q.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	config = fake.jobs[job.ID()]["query"].(map[string]any)
+	if config["maximumBytesBilled"] != "2147483648" {
+		t.Errorf("maximumBytesBilled = %v, want 2147483648", config["maximumBytesBilled"])
+	}
+
+	// The limit can be removed per query
+	q = client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	q.MaxBytesBilled = NoMaxBytesBilled
+	job, err = q.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	config = fake.jobs[job.ID()]["query"].(map[string]any)
+	if limit, ok := config["maximumBytesBilled"]; ok {
+		t.Errorf("maximumBytesBilled = %v, want no limit", limit)
+	}
+	if q.MaxBytesBilled != NoMaxBytesBilled {
+		t.Errorf("MaxBytesBilled = %d after Run, want %d", q.MaxBytesBilled, NoMaxBytesBilled)
+	}
+}
@@ -0,0 +1,85 @@
+package saferbq
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// IdentifierEnum is a $identifier that may only resolve to one of a fixed set
+// of values. The values are validated once, when the enum is declared.
+//
+// Use Enum() to declare a new IdentifierEnum.
+type IdentifierEnum struct {
+	name    string
+	values  []string
+	allowed map[string]bool
+}
+
+// Enum declares a $identifier that may only resolve to one of the given
+// values. It is intended to be declared once, as a package level variable,
+// for queries where the table or column is chosen from a small known set.
+//
+// Enum panics if the name is not a valid $identifier name, if no values are
+// given or if any of the values is not a valid identifier, similar to
+// regexp.MustCompile.
+//
+// Example:
+//
+//	var metric = saferbq.Enum("$metric", "revenue", "orders", "sessions")
+//
+//	q := client.Query("SELECT day, $metric FROM daily_stats")
+//	q.Parameters = []bigquery.QueryParameter{metric.Param(userChoice)}
+func Enum(name string, values ...string) *IdentifierEnum {
+	if identifierParamRegex.FindString(name) != name {
+		panic(fmt.Sprintf("saferbq: Enum(%q): %v: must start with $", name, ErrInvalidParameterName))
+	}
+	if len(values) == 0 {
+		panic(fmt.Sprintf("saferbq: Enum(%q): no values", name))
+	}
+	allowed := make(map[string]bool, len(values))
+	for _, value := range values {
+		if _, err := quoteIdentifierValue(name, value); err != nil {
+			panic(fmt.Sprintf("saferbq: Enum(%q): %v", name, err))
+		}
+		allowed[value] = true
+	}
+	return &IdentifierEnum{name: name, values: values, allowed: allowed}
+}
+
+// Name returns the $identifier name of the enum.
+func (e *IdentifierEnum) Name() string {
+	return e.name
+}
+
+// Values returns the allowed values of the enum.
+func (e *IdentifierEnum) Values() []string {
+	return append([]string(nil), e.values...)
+}
+
+// Param returns the query parameter that binds the value to the enum's
+// $identifier. Translation fails with ErrIdentifierNotAllowed when the
+// value is not one of the allowed values.
+func (e *IdentifierEnum) Param(value string) bigquery.QueryParameter {
+	return bigquery.QueryParameter{Name: e.name, Value: enumValue{enum: e, value: value}}
+}
+
+// enumValue is the value of an enum parameter.
+type enumValue struct {
+	enum  *IdentifierEnum
+	value string
+}
+
+// render checks the value against the allowed values and quotes it.
+func (v enumValue) render(name string) (string, error) {
+	if !v.enum.allowed[v.value] {
+		return "", fmt.Errorf("%w: %s must be one of %s", ErrIdentifierNotAllowed, name, strings.Join(v.enum.values, ", "))
+	}
+	return quoteIdentifierValue(name, v.value)
+}
+
+// String returns the value of the enum parameter.
+func (v enumValue) String() string {
+	return v.value
+}
@@ -0,0 +1,85 @@
+package saferbq
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestEnum(t *testing.T) {
+	metric := Enum("$metric", "revenue", "orders", "sessions")
+
+	if metric.Name() != "$metric" {
+		t.Errorf("Name() = %q, want %q", metric.Name(), "$metric")
+	}
+	if len(metric.Values()) != 3 {
+		t.Errorf("Values() = %v, want 3 values", metric.Values())
+	}
+
+	tests := []struct {
+		name         string
+		value        string
+		sqlOut       string
+		errorMessage string
+	}{
+		{
+			name:   "allowed value",
+			value:  "orders",
+			sqlOut: "SELECT day, `orders` FROM daily_stats",
+		},
+		{
+			name:         "value not allowed",
+			value:        "users",
+			errorMessage: "identifier is not allowed: $metric must be one of revenue, orders, sessions",
+		},
+		{
+			name:         "injection attempt",
+			value:        "orders` FROM passwords --",
+			errorMessage: "identifier is not allowed: $metric must be one of revenue, orders, sessions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT day, $metric FROM daily_stats", []bigquery.QueryParameter{metric.Param(tt.value)})
+			if err != nil {
+				if tt.errorMessage == "" {
+					t.Fatalf("translate() unexpected error: %v", err)
+				}
+				if err.Error() != tt.errorMessage {
+					t.Fatalf("translate() error = %q, want %q", err.Error(), tt.errorMessage)
+				}
+			} else if tt.errorMessage != "" {
+				t.Fatalf("translate() expected error %q but got none", tt.errorMessage)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestEnumPanics(t *testing.T) {
+	tests := []struct {
+		name       string
+		enumName   string
+		enumValues []string
+	}{
+		{"invalid name", "metric", []string{"revenue"}},
+		{"name with suffix", "$metric;", []string{"revenue"}},
+		{"no values", "$metric", nil},
+		{"invalid value", "$metric", []string{"revenue", "orders;"}},
+		{"empty value", "$metric", []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Enum(%q, %v) expected panic", tt.enumName, tt.enumValues)
+				}
+			}()
+			Enum(tt.enumName, tt.enumValues...)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package saferbq
+
+// ParameterKind is the kind of a query parameter.
+type ParameterKind string
+
+const (
+	// IdentifierParameter is a $identifier parameter
+	IdentifierParameter ParameterKind = "identifier"
+	// NamedParameter is an @parameter
+	NamedParameter ParameterKind = "named"
+	// PositionalParameter is a ? positional parameter
+	PositionalParameter ParameterKind = "positional"
+)
+
+// TranslationError describes a problem with a parameter that was found
+// while translating a query. Every problem in the (joined) error returned
+// by the translation is a TranslationError, use errors.As to retrieve it:
+//
+//	var te *saferbq.TranslationError
+//	if errors.As(err, &te) {
+//	    log.Printf("%s parameter %s at offset %d", te.Kind, te.ParamName, te.Offset)
+//	}
+//
+// The sentinel error of the problem is available with errors.Is.
+type TranslationError struct {
+	// ParamName is the name of the parameter, including its prefix
+	// (empty for positional parameters)
+	ParamName string
+	// Kind is the kind of the parameter (empty when the name has no valid prefix)
+	Kind ParameterKind
+	// OriginalSQL is the SQL of the query before translation
+	OriginalSQL string
+	// Offset is the byte offset of the parameter in OriginalSQL, or -1 when
+	// the parameter does not occur in the SQL
+	Offset int
+	// Err is the underlying error, which wraps a sentinel error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TranslationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TranslationError) Unwrap() error {
+	return e.Err
+}
+
+// parameterKind returns the kind of the parameter by its name, or "" when
+// the name has no valid prefix.
+func parameterKind(name string) ParameterKind {
+	switch {
+	case name == "":
+		return PositionalParameter
+	case name[0] == dollarSign:
+		return IdentifierParameter
+	case name[0] == atSign:
+		return NamedParameter
+	}
+	return ""
+}
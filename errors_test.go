@@ -0,0 +1,55 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestTranslationError(t *testing.T) {
+	sql := "SELECT * FROM $table WHERE a = ? AND b = ?"
+	_, _, err := translate(sql, []bigquery.QueryParameter{
+		{Name: "$table", Value: "a;b"},
+		{Name: "$unused", Value: "x"},
+		{Name: "status", Value: "x"},
+		{Value: 1},
+	})
+	want := []TranslationError{
+		{ParamName: "status", Kind: "", Offset: -1},
+		{ParamName: "$unused", Kind: IdentifierParameter, Offset: -1},
+		{ParamName: "", Kind: PositionalParameter, Offset: 41},
+		{ParamName: "$table", Kind: IdentifierParameter, Offset: 14},
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("translate() error = %v, want joined error", err)
+	}
+	errs := joined.Unwrap()
+	if len(errs) != len(want) {
+		t.Fatalf("translate() returned %d errors, want %d: %v", len(errs), len(want), err)
+	}
+	for i, e := range errs {
+		var te *TranslationError
+		if !errors.As(e, &te) {
+			t.Fatalf("error %d = %v, want *TranslationError", i, e)
+		}
+		if te.ParamName != want[i].ParamName || te.Kind != want[i].Kind || te.Offset != want[i].Offset || te.OriginalSQL != sql {
+			t.Errorf("error %d = %+v, want %+v", i, *te, want[i])
+		}
+	}
+	if !errors.Is(err, ErrNotEnoughPositionalParams) || !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("translate() error = %v, want it to match the sentinel errors", err)
+	}
+}
+
+func TestTranslationErrorMixed(t *testing.T) {
+	_, _, err := translate("SELECT @a, ?", nil)
+	var te *TranslationError
+	if !errors.As(err, &te) || te.Kind != PositionalParameter || te.Offset != 11 {
+		t.Fatalf("translate() error = %#v, want positional TranslationError at offset 11", err)
+	}
+	if !errors.Is(err, ErrMixedParameterTypes) || err.Error() != ErrMixedParameterTypes.Error() {
+		t.Errorf("translate() error = %v, want %v", err, ErrMixedParameterTypes)
+	}
+}
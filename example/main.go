@@ -172,21 +172,10 @@ func ddlOperations(ctx context.Context, client *saferbq.Client) {
 	}
 
 	// Resulting SQL: CREATE TABLE IF NOT EXISTS `mydataset`.`mynew_table` (...)
-	job, err := q.Run(ctx)
-	if err != nil {
+	if _, err := q.Exec(ctx); err != nil {
 		log.Printf("DDL error: %v", err)
 		return
 	}
-
-	status, err := job.Wait(ctx)
-	if err != nil {
-		log.Printf("Job wait error: %v", err)
-		return
-	}
-	if err := status.Err(); err != nil {
-		log.Printf("Job status error: %v", err)
-		return
-	}
 	fmt.Println("Table created successfully")
 }
 
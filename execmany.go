@@ -0,0 +1,131 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// ExecManyOption configures how Stmt.ExecMany runs the parameter sets.
+type ExecManyOption func(*execManyOptions)
+
+// execManyOptions are the options of ExecMany.
+type execManyOptions struct {
+	concurrency int
+	script      bool
+}
+
+// Concurrently runs up to n parameter sets of ExecMany at the same time
+// (the default is 1, one after the other). The lane limits of the client
+// still apply.
+func Concurrently(n int) ExecManyOption {
+	return func(o *execManyOptions) {
+		o.concurrency = n
+	}
+}
+
+// AsScript runs all parameter sets of ExecMany as the statements of a
+// single BigQuery script, in one job. Named parameters that are used by
+// more than one set are renamed per set (by appending _1, _2, ...).
+func AsScript() ExecManyOption {
+	return func(o *execManyOptions) {
+		o.script = true
+	}
+}
+
+// ExecMany runs the prepared statement once for each parameter set, like
+// Exec, and returns the result of every set in the order of the sets. By
+// default the sets run one after the other, see Concurrently and AsScript
+// for the alternatives. A failing set does not stop the others.
+//
+// In a script all sets run in one job, so their results all have the job
+// ID of the script and no row counts.
+//
+// Example:
+//
+//	stmt, err := client.Prepare("DELETE FROM $table WHERE day < @cutoff")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	sets := [][]bigquery.QueryParameter{}
+//	for _, tenant := range tenants {
+//	    sets = append(sets, []bigquery.QueryParameter{
+//	        {Name: "$table", Value: "events_" + tenant},
+//	        {Name: "@cutoff", Value: cutoff},
+//	    })
+//	}
+//	results, err := stmt.ExecMany(ctx, sets, saferbq.Concurrently(4))
+//
+// Returns the results, with nil for the sets that failed, and an error
+// that joins the errors of the failed sets (prefixed with "parameter set
+// <index>"). A failing script fails all sets.
+func (s *Stmt) ExecMany(ctx context.Context, paramSets [][]bigquery.QueryParameter, opts ...ExecManyOption) ([]*DMLResult, error) {
+	o := execManyOptions{concurrency: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	results := make([]*DMLResult, len(paramSets))
+	if len(paramSets) == 0 {
+		return results, nil
+	}
+	if o.script {
+		return s.execScript(ctx, paramSets)
+	}
+	errs := make([]error, len(paramSets))
+	slots := make(chan struct{}, max(o.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, params := range paramSets {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			result, err := s.Query(params...).Exec(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("parameter set %d: %w", i, err)
+				return
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+// execScript translates the statement for every parameter set and runs
+// the statements as a single script.
+func (s *Stmt) execScript(ctx context.Context, paramSets [][]bigquery.QueryParameter) ([]*DMLResult, error) {
+	var original, translated strings.Builder
+	parameters := []bigquery.QueryParameter{}
+	taken := map[string]bool{}
+	for i, params := range paramSets {
+		q := s.Query(params...)
+		if err := q.translate(); err != nil {
+			return nil, fmt.Errorf("parameter set %d: %w", i, err)
+		}
+		sql, params := renameParameters(q.QueryConfig.Q, q.Parameters, taken)
+		parameters = append(parameters, params...)
+		original.WriteString(strings.TrimSpace(statementBody(s.template.source)) + ";\n")
+		translated.WriteString(strings.TrimSpace(statementBody(sql)) + ";\n")
+	}
+	// The statements are translated already, so the script is not translated again
+	script := s.client.Query(original.String())
+	script.QueryConfig.Q = translated.String()
+	script.Parameters = parameters
+	script.translated = true
+	result, err := script.Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("script failed: %w", err)
+	}
+	results := make([]*DMLResult, len(paramSets))
+	for i := range results {
+		results[i] = &DMLResult{JobID: result.JobID}
+	}
+	return results, nil
+}
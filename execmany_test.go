@@ -0,0 +1,115 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// execManySets returns parameter sets for the tables with the same @day.
+func execManySets(tables ...string) [][]bigquery.QueryParameter {
+	sets := [][]bigquery.QueryParameter{}
+	for _, table := range tables {
+		sets = append(sets, []bigquery.QueryParameter{
+			{Name: "$table", Value: table},
+			{Name: "@day", Value: "2024-01-01"},
+		})
+	}
+	return sets
+}
+
+func TestStmtExecMany(t *testing.T) {
+	for _, opts := range [][]ExecManyOption{nil, {Concurrently(2)}} {
+		fake := &fakeBigQuery{statistics: map[string]any{"numDmlAffectedRows": "3"}}
+		client := newFakeClient(t, fake)
+		stmt, err := client.Prepare("DELETE FROM $table WHERE day < @day")
+		if err != nil {
+			t.Fatalf("Prepare() unexpected error: %v", err)
+		}
+		results, err := stmt.ExecMany(context.Background(), execManySets("a", "b", "c"), opts...)
+		if err != nil {
+			t.Fatalf("ExecMany() unexpected error: %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("ExecMany() returned %d results, want 3", len(results))
+		}
+		for i, result := range results {
+			if result == nil || result.RowsAffected != 3 {
+				t.Errorf("ExecMany() result %d = %+v, want 3 affected rows", i, result)
+			}
+		}
+		queries := fake.executedQueries()
+		slices.Sort(queries)
+		expected := []string{
+			"DELETE FROM `a` WHERE day < @day",
+			"DELETE FROM `b` WHERE day < @day",
+			"DELETE FROM `c` WHERE day < @day",
+		}
+		if !slices.Equal(queries, expected) {
+			t.Errorf("ExecMany() queries = %q, want %q", queries, expected)
+		}
+	}
+}
+
+func TestStmtExecManyPartialFailure(t *testing.T) {
+	client := newFakeClient(t, &fakeBigQuery{})
+	stmt, err := client.Prepare("DELETE FROM $table WHERE day < @day")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+	results, err := stmt.ExecMany(context.Background(), execManySets("a", "b;c", "d"))
+	if !errors.Is(err, ErrIdentifierInvalidChars) || !strings.Contains(err.Error(), "parameter set 1") {
+		t.Errorf("ExecMany() error = %v, want %v for parameter set 1", err, ErrIdentifierInvalidChars)
+	}
+	if len(results) != 3 || results[0] == nil || results[1] != nil || results[2] == nil {
+		t.Errorf("ExecMany() results = %v, want a nil result for the failed set only", results)
+	}
+}
+
+func TestStmtExecManyScript(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	stmt, err := client.Prepare("DELETE FROM $table WHERE day < @day;")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+	results, err := stmt.ExecMany(context.Background(), execManySets("a", "b"), AsScript())
+	if err != nil {
+		t.Fatalf("ExecMany() unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].JobID == "" || results[0].JobID != results[1].JobID {
+		t.Errorf("ExecMany() results = %v, want 2 results of the same job", results)
+	}
+	expected := "DELETE FROM `a` WHERE day < @day;\nDELETE FROM `b` WHERE day < @day_1;\n"
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != expected {
+		t.Errorf("ExecMany() queries = %q, want %q", queries, expected)
+	}
+	config := fake.jobs[results[0].JobID]["query"].(map[string]any)
+	if params, _ := config["queryParameters"].([]any); len(params) != 2 {
+		t.Errorf("submitted parameters = %v, want 2 parameters", config["queryParameters"])
+	}
+
+	if _, err := stmt.ExecMany(context.Background(), execManySets("a", ""), AsScript()); !errors.Is(err, ErrIdentifierEmpty) {
+		t.Errorf("ExecMany() error = %v, want %v", err, ErrIdentifierEmpty)
+	}
+}
+
+func TestStmtExecManyScriptTrailingComment(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	stmt, err := client.Prepare("DELETE FROM $table WHERE day < @day -- expired;")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+	if _, err := stmt.ExecMany(context.Background(), execManySets("a", "b"), AsScript()); err != nil {
+		t.Fatalf("ExecMany() unexpected error: %v", err)
+	}
+	expected := "DELETE FROM `a` WHERE day < @day;\nDELETE FROM `b` WHERE day < @day_1;\n"
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != expected {
+		t.Errorf("ExecMany() queries = %q, want %q", queries, expected)
+	}
+}
@@ -0,0 +1,121 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// QueryPlan is the execution plan of a completed query job, see Explain.
+type QueryPlan struct {
+	// JobID is the ID of the job that ran the query
+	JobID string
+	// Stages are the stages of the plan, in the order BigQuery reports them
+	Stages []PlanStage
+}
+
+// PlanStage is a stage of a query plan.
+type PlanStage struct {
+	// ID is the ID of the stage within the plan
+	ID int64
+	// Name is the human readable name of the stage, such as "S00: Input"
+	Name string
+	// Status is the status of the stage, such as COMPLETE
+	Status string
+	// InputStages are the IDs of the stages that are inputs of this stage
+	InputStages []int64
+	// Steps are the operations of the stage, such as "READ: $1, $2"
+	Steps []string
+	// RecordsRead is the number of records read by the stage
+	RecordsRead int64
+	// RecordsWritten is the number of records written by the stage
+	RecordsWritten int64
+	// ShuffleOutputBytes is the number of bytes written to shuffle
+	ShuffleOutputBytes int64
+	// ShuffleOutputBytesSpilled is the number of shuffle bytes spilled to disk
+	ShuffleOutputBytesSpilled int64
+	// ParallelInputs is the number of parallel input segments of the stage
+	ParallelInputs int64
+	// Duration is the time between the start and the end of the stage
+	Duration time.Duration
+}
+
+// Explain runs the query, waits for it to complete and returns its
+// execution plan, with the records and shuffle bytes of every stage, so
+// dynamically generated queries can be profiled without the raw SDK types.
+// The results of the query are not read.
+//
+// Example:
+//
+//	plan, err := q.Explain(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Print(plan)
+//
+// Returns an error if parameter validation fails, if the query could not be
+// submitted, or an error wrapping ErrJobFailed if the job itself failed.
+func (q *Query) Explain(ctx context.Context) (*QueryPlan, error) {
+	job, err := q.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := waitJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	plan := &QueryPlan{JobID: job.ID(), Stages: []PlanStage{}}
+	if status.Statistics == nil {
+		return plan, nil
+	}
+	details, ok := status.Statistics.Details.(*bigquery.QueryStatistics)
+	if !ok {
+		return plan, nil
+	}
+	for _, stage := range details.QueryPlan {
+		plan.Stages = append(plan.Stages, newPlanStage(stage))
+	}
+	return plan, nil
+}
+
+// newPlanStage converts a stage of the SDK query plan.
+func newPlanStage(stage *bigquery.ExplainQueryStage) PlanStage {
+	steps := make([]string, 0, len(stage.Steps))
+	for _, step := range stage.Steps {
+		steps = append(steps, step.Kind+": "+strings.Join(step.Substeps, ", "))
+	}
+	s := PlanStage{
+		ID:                        stage.ID,
+		Name:                      stage.Name,
+		Status:                    stage.Status,
+		InputStages:               stage.InputStages,
+		Steps:                     steps,
+		RecordsRead:               stage.RecordsRead,
+		RecordsWritten:            stage.RecordsWritten,
+		ShuffleOutputBytes:        stage.ShuffleOutputBytes,
+		ShuffleOutputBytesSpilled: stage.ShuffleOutputBytesSpilled,
+		ParallelInputs:            stage.ParallelInputs,
+	}
+	if stage.EndTime.After(stage.StartTime) {
+		s.Duration = stage.EndTime.Sub(stage.StartTime)
+	}
+	return s
+}
+
+// String returns the plan with one line per stage and one indented line
+// per step.
+func (p *QueryPlan) String() string {
+	var b strings.Builder
+	for _, stage := range p.Stages {
+		fmt.Fprintf(&b, "%s [%s] %s: %d records read, %d written, %d shuffle bytes (%d spilled)\n",
+			stage.Name, stage.Status, stage.Duration, stage.RecordsRead, stage.RecordsWritten,
+			stage.ShuffleOutputBytes, stage.ShuffleOutputBytesSpilled)
+		for _, step := range stage.Steps {
+			fmt.Fprintf(&b, "  %s\n", step)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryExplain(t *testing.T) {
+	fake := &fakeBigQuery{statistics: map[string]any{
+		"queryPlan": []map[string]any{
+			{
+				"id": "0", "name": "S00: Input", "status": "COMPLETE",
+				"recordsRead": "100", "recordsWritten": "10",
+				"shuffleOutputBytes": "2048", "shuffleOutputBytesSpilled": "0",
+				"parallelInputs": "4", "startMs": "1000", "endMs": "1500",
+				"steps": []map[string]any{{"kind": "READ", "substeps": []string{"$1:id", "FROM events"}}},
+			},
+			{
+				"id": "1", "name": "S01: Output", "status": "COMPLETE", "inputStages": []string{"0"},
+				"recordsRead": "10", "recordsWritten": "1",
+			},
+		},
+	}}
+	client := newFakeClient(t, fake)
+	q := client.Query("SELECT COUNT(id) FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	plan, err := q.Explain(context.Background())
+	if err != nil {
+		t.Fatalf("Explain() unexpected error: %v", err)
+	}
+	if plan.JobID == "" || len(plan.Stages) != 2 {
+		t.Fatalf("Explain() = %+v, want a job ID and 2 stages", plan)
+	}
+	expected := PlanStage{
+		ID: 0, Name: "S00: Input", Status: "COMPLETE", Steps: []string{"READ: $1:id, FROM events"},
+		RecordsRead: 100, RecordsWritten: 10, ShuffleOutputBytes: 2048, ParallelInputs: 4,
+		Duration: 500 * time.Millisecond,
+	}
+	if !reflect.DeepEqual(plan.Stages[0], expected) {
+		t.Errorf("Explain() stage 0 = %+v, want %+v", plan.Stages[0], expected)
+	}
+	if !reflect.DeepEqual(plan.Stages[1].InputStages, []int64{0}) {
+		t.Errorf("Explain() stage 1 inputs = %v, want [0]", plan.Stages[1].InputStages)
+	}
+	want := "S00: Input [COMPLETE] 500ms: 100 records read, 10 written, 2048 shuffle bytes (0 spilled)\n" +
+		"  READ: $1:id, FROM events\n" +
+		"S01: Output [COMPLETE] 0s: 10 records read, 1 written, 0 shuffle bytes (0 spilled)\n"
+	if plan.String() != want {
+		t.Errorf("String() =\n%s\nwant\n%s", plan.String(), want)
+	}
+}
+
+func TestQueryExplainError(t *testing.T) {
+	fake := &fakeBigQuery{errorResult: "invalidQuery"}
+	client := newFakeClient(t, fake)
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	if _, err := q.Explain(context.Background()); !errors.Is(err, ErrJobFailed) {
+		t.Errorf("Explain() error = %v, want %v", err, ErrJobFailed)
+	}
+}
@@ -0,0 +1,247 @@
+package saferbq
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"google.golang.org/api/iterator"
+)
+
+// WriteJSONL translates and runs the query like Read, and writes the result
+// rows to w as JSON Lines: one JSON object per row, with the columns in the
+// order of the result schema. Nested records are objects and repeated
+// columns are arrays. TIMESTAMP values are written in RFC 3339 format (in
+// UTC), DATE, TIME and DATETIME values in their SQL format, NUMERIC and
+// BIGNUMERIC values as exact decimal strings, BYTES as base64 strings and
+// JSON columns as embedded JSON. Non-finite floats are written as the
+// strings "NaN", "Infinity" and "-Infinity".
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table WHERE day = @day")
+//	q.SetParams(map[string]any{"$table": "events_" + tenant, "@day": day})
+//	w.Header().Set("Content-Type", "application/x-ndjson")
+//	err := q.WriteJSONL(ctx, w)
+//
+// Returns an error if the query fails, a *RowError if a row could not be
+// read, or the error of writing to w.
+func (q *Query) WriteJSONL(ctx context.Context, w io.Writer) error {
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for {
+		var values []bigquery.Value
+		err := it.Next(&values)
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line := appendJSONRecord(nil, values, it.Schema)
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteCSV translates and runs the query like Read, and writes the result
+// rows to w as CSV, with a header row of the column names. Values are
+// formatted like in WriteJSONL, NULL values are empty and nested records
+// and repeated columns are written as JSON.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table WHERE day = @day")
+//	q.SetParams(map[string]any{"$table": "events_" + tenant, "@day": day})
+//	w.Header().Set("Content-Type", "text/csv")
+//	err := q.WriteCSV(ctx, w)
+//
+// Returns an error if the query fails, a *RowError if a row could not be
+// read, or the error of writing to w.
+func (q *Query) WriteCSV(ctx context.Context, w io.Writer) error {
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	writer := csv.NewWriter(w)
+	header := false
+	for {
+		var values []bigquery.Value
+		err := it.Next(&values)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !header {
+			if err := writer.Write(columnNames(it.Schema)); err != nil {
+				return err
+			}
+			header = true
+		}
+		record := make([]string, len(values))
+		for i, value := range values {
+			if i < len(it.Schema) {
+				record[i] = csvValue(value, it.Schema[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	if !header {
+		// The schema is known once the first page was fetched, also without rows
+		if err := writer.Write(columnNames(it.Schema)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// columnNames returns the names of the fields of the schema.
+func columnNames(schema bigquery.Schema) []string {
+	names := make([]string, len(schema))
+	for i, field := range schema {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// csvValue formats the value of the field as a CSV cell.
+func csvValue(value bigquery.Value, field *bigquery.FieldSchema) string {
+	if value == nil {
+		return ""
+	}
+	if field.Repeated || field.Schema != nil {
+		return string(appendJSONValue(nil, value, field))
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatFloat(v)
+	}
+	return scalarString(value, field)
+}
+
+// appendJSONRecord appends the values of a row (or record) as a JSON
+// object with the fields of the schema in order.
+func appendJSONRecord(buf []byte, values []bigquery.Value, schema bigquery.Schema) []byte {
+	buf = append(buf, '{')
+	for i, field := range schema {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		name, _ := json.Marshal(field.Name)
+		buf = append(append(buf, name...), ':')
+		var value bigquery.Value
+		if i < len(values) {
+			value = values[i]
+		}
+		buf = appendJSONValue(buf, value, field)
+	}
+	return append(buf, '}')
+}
+
+// appendJSONValue appends the value of the field as JSON.
+func appendJSONValue(buf []byte, value bigquery.Value, field *bigquery.FieldSchema) []byte {
+	if value == nil {
+		return append(buf, "null"...)
+	}
+	if field.Repeated {
+		element := *field
+		element.Repeated = false
+		repeated, _ := value.([]bigquery.Value)
+		buf = append(buf, '[')
+		for i, v := range repeated {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONValue(buf, v, &element)
+		}
+		return append(buf, ']')
+	}
+	if record, ok := value.([]bigquery.Value); ok && field.Schema != nil {
+		return appendJSONRecord(buf, record, field.Schema)
+	}
+	switch v := value.(type) {
+	case bool:
+		return strconv.AppendBool(buf, v)
+	case int64:
+		return strconv.AppendInt(buf, v, 10)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.AppendQuote(buf, formatFloat(v))
+		}
+		return strconv.AppendFloat(buf, v, 'g', -1, 64)
+	case string:
+		if field.Type == bigquery.JSONFieldType && json.Valid([]byte(v)) {
+			return append(buf, v...)
+		}
+	}
+	encoded, _ := json.Marshal(scalarString(value, field))
+	return append(buf, encoded...)
+}
+
+// scalarString formats a scalar value of the field as a string.
+func scalarString(value bigquery.Value, field *bigquery.FieldSchema) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case civil.Date:
+		return v.String()
+	case civil.Time:
+		return bigquery.CivilTimeString(v)
+	case civil.DateTime:
+		return bigquery.CivilDateTimeString(v)
+	case *big.Rat:
+		if field.Type == bigquery.BigNumericFieldType {
+			return bigquery.BigNumericString(v)
+		}
+		return bigquery.NumericString(v)
+	case float64:
+		return formatFloat(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatFloat formats a float with the shortest exact representation, and
+// non-finite floats as "NaN", "Infinity" and "-Infinity".
+func formatFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,94 @@
+package saferbq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// newExportFake returns a fake with columns of various types and two rows.
+func newExportFake() *fakeBigQuery {
+	return &fakeBigQuery{
+		schema: []map[string]any{
+			{"name": "id", "type": "INTEGER"},
+			{"name": "name", "type": "STRING"},
+			{"name": "created_at", "type": "TIMESTAMP"},
+			{"name": "day", "type": "DATE"},
+			{"name": "amount", "type": "NUMERIC"},
+			{"name": "score", "type": "FLOAT"},
+			{"name": "payload", "type": "JSON"},
+			{"name": "tags", "type": "STRING", "mode": "REPEATED"},
+			{"name": "address", "type": "RECORD", "fields": []map[string]any{
+				{"name": "city", "type": "STRING"},
+			}},
+		},
+		rows: [][]any{
+			{"1", "a \"quoted\", name", "1704067200500000", "2024-01-01", "12.50", "0.25", `{"k":1}`,
+				[]map[string]any{{"v": "x"}, {"v": "y"}}, map[string]any{"f": []map[string]any{{"v": "Paris"}}}},
+			{"2", nil, nil, nil, nil, "NaN", nil, []map[string]any{}, nil},
+		},
+	}
+}
+
+func TestQueryWriteJSONL(t *testing.T) {
+	client := newFakeClient(t, newExportFake())
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	var buf bytes.Buffer
+	if err := q.WriteJSONL(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteJSONL() unexpected error: %v", err)
+	}
+	expected := `{"id":1,"name":"a \"quoted\", name","created_at":"2024-01-01T00:00:00.5Z","day":"2024-01-01","amount":"12.500000000","score":0.25,"payload":{"k":1},"tags":["x","y"],"address":{"city":"Paris"}}` + "\n" +
+		`{"id":2,"name":null,"created_at":null,"day":null,"amount":null,"score":"NaN","payload":null,"tags":[],"address":null}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("WriteJSONL() =\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestQueryWriteCSV(t *testing.T) {
+	client := newFakeClient(t, newExportFake())
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	var buf bytes.Buffer
+	if err := q.WriteCSV(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteCSV() unexpected error: %v", err)
+	}
+	expected := "id,name,created_at,day,amount,score,payload,tags,address\n" +
+		`1,"a ""quoted"", name",2024-01-01T00:00:00.5Z,2024-01-01,12.500000000,0.25,"{""k"":1}","[""x"",""y""]","{""city"":""Paris""}"` + "\n" +
+		"2,,,,,NaN,,[],\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestQueryWriteCSVNoRows(t *testing.T) {
+	fake := newReadFake()
+	fake.rows = nil
+	client := newFakeClient(t, fake)
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	var buf bytes.Buffer
+	if err := q.WriteCSV(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteCSV() unexpected error: %v", err)
+	}
+	if buf.String() != "id,name\n" {
+		t.Errorf("WriteCSV() = %q, want the header only", buf.String())
+	}
+}
+
+func TestQueryWriteError(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	var buf bytes.Buffer
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": ""})
+	if err := q.WriteJSONL(context.Background(), &buf); !errors.Is(err, ErrIdentifierEmpty) {
+		t.Errorf("WriteJSONL() error = %v, want %v", err, ErrIdentifierEmpty)
+	}
+	if err := q.WriteCSV(context.Background(), &buf); !errors.Is(err, ErrIdentifierEmpty) {
+		t.Errorf("WriteCSV() error = %v, want %v", err, ErrIdentifierEmpty)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("written output = %q, want none", buf.String())
+	}
+}
@@ -0,0 +1,107 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// ExternalTable configures the CREATE EXTERNAL TABLE statement of
+// CreateExternalTable.
+type ExternalTable struct {
+	// OrReplace replaces the table when it exists
+	OrReplace bool
+	// IfNotExists only creates the table when it does not exist
+	IfNotExists bool
+	// Connection is the connection that reads the files (optional), see
+	// ConnectionID for the accepted forms
+	Connection string
+	// Format is the format of the files
+	Format bigquery.DataFormat
+	// URIs are the Cloud Storage URIs of the files, each may contain one *
+	// wildcard in the object name
+	URIs []string
+	// Schema is the schema of the files (optional, detected when empty)
+	Schema bigquery.Schema
+	// SkipLeadingRows is the number of header rows of CSV files
+	SkipLeadingRows int64
+	// FieldDelimiter separates the fields of CSV files (the default is ",")
+	FieldDelimiter string
+}
+
+// CreateExternalTable creates an external table over files in Cloud
+// Storage by running a CREATE EXTERNAL TABLE statement. The dataset and
+// table IDs are validated (with the rules of DatasetID and TableID) and
+// quoted, the connection is validated with the rules of ConnectionID, the
+// URIs are validated like in SafeLoad and escaped as string literals, and
+// the format must be one that external tables read.
+//
+// Example:
+//
+//	err := client.CreateExternalTable(ctx, "lake", "events_"+tenant, saferbq.ExternalTable{
+//	    OrReplace:  true,
+//	    Connection: "projects/my-project/locations/us/connections/lake",
+//	    Format:     bigquery.Parquet,
+//	    URIs:       []string{"gs://lake-" + tenant + "/events/*.parquet"},
+//	})
+//	// CREATE OR REPLACE EXTERNAL TABLE `lake`.`events_acme`
+//	//   WITH CONNECTION `my-project.us.lake`
+//	//   OPTIONS (format = 'PARQUET', uris = ['gs://lake-acme/events/*.parquet'])
+//
+// Returns an error wrapping ErrInvalidFormat if the format is not
+// supported, ErrInvalidURI if a URI is not valid, ErrInvalidDDL if the
+// definition is not valid, or an error if a column or identifier is not
+// valid or the statement fails.
+func (c *Client) CreateExternalTable(ctx context.Context, dataset, table string, def ExternalTable) error {
+	sql, err := createExternalTableSQL(def)
+	if err != nil {
+		return fmt.Errorf("failed to create external table: %w", err)
+	}
+	params := map[string]any{"$dataset": DatasetID(dataset), "$table": TableID(table)}
+	if def.Connection != "" {
+		params["$connection"] = ConnectionID(def.Connection)
+	}
+	q := c.Query(sql)
+	q.SetParams(params)
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create external table: %w", err)
+	}
+	return nil
+}
+
+// createExternalTableSQL renders the CREATE EXTERNAL TABLE statement, with
+// $dataset.$table as the name of the table and $connection as the
+// connection.
+func createExternalTableSQL(def ExternalTable) (string, error) {
+	if def.OrReplace && def.IfNotExists {
+		return "", fmt.Errorf("%w: IfNotExists and OrReplace can't be combined", ErrInvalidDDL)
+	}
+	options, err := fileOptions(def.Format, def.URIs, def.SkipLeadingRows, def.FieldDelimiter)
+	if err != nil {
+		return "", err
+	}
+	var sql strings.Builder
+	sql.WriteString("CREATE ")
+	if def.OrReplace {
+		sql.WriteString("OR REPLACE ")
+	}
+	sql.WriteString("EXTERNAL TABLE ")
+	if def.IfNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString("$dataset.$table")
+	if len(def.Schema) > 0 {
+		columns, err := columnDefinitions(def.Schema)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(" (" + columns + ")")
+	}
+	if def.Connection != "" {
+		sql.WriteString(" WITH CONNECTION $connection")
+	}
+	sql.WriteString(" OPTIONS (" + strings.Join(options, ", ") + ")")
+	return sql.String(), nil
+}
@@ -0,0 +1,85 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestClientCreateExternalTable(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	err := client.CreateExternalTable(ctx, "lake", "events_acme", ExternalTable{
+		OrReplace:  true,
+		Connection: "projects/my-project/locations/us/connections/lake",
+		Format:     bigquery.Parquet,
+		URIs:       []string{"gs://lake-acme/events/*.parquet"},
+	})
+	if err != nil {
+		t.Fatalf("CreateExternalTable() unexpected error: %v", err)
+	}
+	err = client.CreateExternalTable(ctx, "lake", "imports", ExternalTable{
+		IfNotExists:     true,
+		Format:          bigquery.CSV,
+		URIs:            []string{"gs://imports/a.csv", "gs://imports/b.csv"},
+		Schema:          bigquery.Schema{{Name: "id", Type: bigquery.IntegerFieldType}},
+		SkipLeadingRows: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateExternalTable() unexpected error: %v", err)
+	}
+	expected := []string{
+		"CREATE OR REPLACE EXTERNAL TABLE `lake`.`events_acme` WITH CONNECTION `my-project.us.lake` " +
+			"OPTIONS (format = 'PARQUET', uris = ['gs://lake-acme/events/*.parquet'])",
+		"CREATE EXTERNAL TABLE IF NOT EXISTS `lake`.`imports` (`id` INT64) " +
+			"OPTIONS (format = 'CSV', uris = ['gs://imports/a.csv', 'gs://imports/b.csv'], skip_leading_rows = 1)",
+	}
+	queries := fake.executedQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("queries = %q, want %q", queries, expected)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], expected[i])
+		}
+	}
+}
+
+func TestClientCreateExternalTableValidation(t *testing.T) {
+	valid := ExternalTable{Format: bigquery.Parquet, URIs: []string{"gs://lake/events.parquet"}}
+	withConnection := valid
+	withConnection.Connection = "my-project.us.lake`; DROP TABLE x; --"
+	noURIs := valid
+	noURIs.URIs = nil
+	both := valid
+	both.OrReplace, both.IfNotExists = true, true
+	tests := []struct {
+		name  string
+		table string
+		def   ExternalTable
+		err   error
+	}{
+		{"invalid connection", "events", withConnection, ErrIdentifierInvalidChars},
+		{"no format", "events", ExternalTable{URIs: valid.URIs}, ErrInvalidFormat},
+		{"no uris", "events", noURIs, ErrInvalidURI},
+		{"if not exists and or replace", "events", both, ErrInvalidDDL},
+		{"invalid table", "lake.events", valid, ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBigQuery{}
+			client := newFakeClient(t, fake)
+			err := client.CreateExternalTable(context.Background(), "lake", tt.table, tt.def)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("CreateExternalTable() error = %v, want %v", err, tt.err)
+			}
+			if queries := fake.executedQueries(); len(queries) != 0 {
+				t.Errorf("CreateExternalTable() queries = %q, want none", queries)
+			}
+		})
+	}
+}
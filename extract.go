@@ -0,0 +1,79 @@
+package saferbq
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// extractFormats are the data formats that tables can be exported to
+var extractFormats = map[bigquery.DataFormat]bool{
+	bigquery.CSV:     true,
+	bigquery.JSON:    true,
+	bigquery.Avro:    true,
+	bigquery.Parquet: true,
+}
+
+// ExtractConfig configures an extract job of SafeExtract.
+type ExtractConfig struct {
+	// Format is the format of the exported files: CSV (the default), JSON,
+	// Avro or Parquet
+	Format bigquery.DataFormat
+	// Compression is the compression of the exported files (optional)
+	Compression bigquery.Compression
+	// FieldDelimiter separates the fields of CSV files (the default is ",")
+	FieldDelimiter string
+	// DisableHeader omits the header row of CSV files
+	DisableHeader bool
+	// UseAvroLogicalTypes exports timestamps and dates as Avro logical types
+	UseAvroLogicalTypes bool
+	// Labels are the labels of the extract job
+	Labels map[string]string
+}
+
+// SafeExtract returns an extractor that exports the table to files at the
+// Cloud Storage URI, after validating the dataset and table IDs (with the
+// rules of DatasetID and TableID), the URI, that must have the form
+// gs://bucket/object and may contain one * wildcard in the object name,
+// and the format.
+//
+// Example:
+//
+//	extractor, err := client.SafeExtract("reports", "daily_"+day, "gs://my-bucket/reports/"+day+"-*.parquet",
+//	    saferbq.ExtractConfig{Format: bigquery.Parquet})
+//	if err != nil {
+//	    return err
+//	}
+//	job, err := extractor.Run(ctx)
+//
+// Returns an error wrapping ErrInvalidURI if the URI is not valid,
+// ErrInvalidFormat if the format is not supported, ErrInvalidLabel if a
+// label is not valid, or one of the identifier errors if the dataset or
+// table ID is not valid.
+func (c *Client) SafeExtract(dataset, table, gcsURI string, cfg ExtractConfig) (*bigquery.Extractor, error) {
+	if err := validateGCSURI(gcsURI); err != nil {
+		return nil, fmt.Errorf("failed to create extractor: %w", err)
+	}
+	if cfg.Format == "" {
+		cfg.Format = bigquery.CSV
+	}
+	if !extractFormats[cfg.Format] {
+		return nil, fmt.Errorf("failed to create extractor: %w: %s", ErrInvalidFormat, cfg.Format)
+	}
+	t, err := c.safeTable(dataset, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create extractor: %w", err)
+	}
+	if err := validateLabels(cfg.Labels); err != nil {
+		return nil, fmt.Errorf("failed to create extractor: %w", err)
+	}
+	ref := bigquery.NewGCSReference(gcsURI)
+	ref.DestinationFormat = cfg.Format
+	ref.Compression = cfg.Compression
+	ref.FieldDelimiter = cfg.FieldDelimiter
+	extractor := t.ExtractorTo(ref)
+	extractor.DisableHeader = cfg.DisableHeader
+	extractor.UseAvroLogicalTypes = cfg.UseAvroLogicalTypes
+	extractor.Labels = cfg.Labels
+	return extractor, nil
+}
@@ -0,0 +1,74 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestSafeExtract(t *testing.T) {
+	tests := []struct {
+		name   string
+		format bigquery.DataFormat
+		want   bigquery.DataFormat
+	}{
+		{"default", "", bigquery.CSV},
+		{"json", bigquery.JSON, bigquery.JSON},
+		{"avro", bigquery.Avro, bigquery.Avro},
+		{"parquet", bigquery.Parquet, bigquery.Parquet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := (&Client{}).SafeExtract("reports", "daily", "gs://my-bucket/reports/daily-*", ExtractConfig{
+				Format:      tt.format,
+				Compression: bigquery.Gzip,
+				Labels:      map[string]string{"pipeline": "export"},
+			})
+			if err != nil {
+				t.Fatalf("SafeExtract() unexpected error: %v", err)
+			}
+			if extractor.Src.DatasetID != "reports" || extractor.Src.TableID != "daily" {
+				t.Errorf("SafeExtract() source = %s.%s, want reports.daily", extractor.Src.DatasetID, extractor.Src.TableID)
+			}
+			if extractor.Dst.DestinationFormat != tt.want {
+				t.Errorf("SafeExtract() format = %s, want %s", extractor.Dst.DestinationFormat, tt.want)
+			}
+			if extractor.Dst.Compression != bigquery.Gzip {
+				t.Errorf("SafeExtract() compression = %s, want %s", extractor.Dst.Compression, bigquery.Gzip)
+			}
+			if extractor.Labels["pipeline"] != "export" {
+				t.Errorf("SafeExtract() labels = %v", extractor.Labels)
+			}
+		})
+	}
+}
+
+func TestSafeExtractValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataset string
+		table   string
+		uri     string
+		format  bigquery.DataFormat
+		err     error
+	}{
+		{"invalid uri", "reports", "daily", "gs://my-bucket", "", ErrInvalidURI},
+		{"unsupported format", "reports", "daily", "gs://my-bucket/daily.orc", bigquery.ORC, ErrInvalidFormat},
+		{"invalid dataset", "my reports", "daily", "gs://my-bucket/daily.csv", "", ErrIdentifierInvalidChars},
+		{"invalid table", "reports", "daily`", "gs://my-bucket/daily.csv", "", ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := (&Client{}).SafeExtract(tt.dataset, tt.table, tt.uri, ExtractConfig{Format: tt.format})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SafeExtract() error = %v, want %v", err, tt.err)
+			}
+			if extractor != nil {
+				t.Errorf("SafeExtract() extractor = %v, want nil", extractor)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package saferbq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+)
+
+// fakeBigQuery is a minimal fake of the BigQuery REST API that answers
+// jobs.insert, jobs.get, jobs.list, jobs.query, jobs.getQueryResults,
+// tables.list and tables.get requests.
+type fakeBigQuery struct {
+	mu sync.Mutex
+	// schema is the result schema as BigQuery JSON fields
+	schema []map[string]any
+	// rows are the result rows, each value is a string or nil
+	rows [][]any
+	// pageSize returns the rows in pages of pageSize rows when set, the
+	// page token is the index of the first row of the page
+	pageSize int
+	// failPages makes every request for a page after the first fail
+	failPages bool
+	// failJobGets makes the next failJobGets jobs.get requests fail
+	failJobGets int
+	// statistics are merged into the query statistics of every job
+	statistics map[string]any
+	// errorResult makes every job fail with the given reason when set
+	errorResult string
+	// sessionID is reported as the session of every job when set
+	sessionID string
+	// noStatistics omits the statistics of every job when set
+	noStatistics bool
+	// queries records the SQL of all submitted queries
+	queries []string
+	// jobs records all submitted job configurations by job ID
+	jobs map[string]map[string]any
+	// tables are the table resources returned by tables.list and tables.get
+	tables []map[string]any
+	// tableGets counts the tables.get requests
+	tableGets int
+	// running keeps every job running until it is cancelled
+	running bool
+	// cancelled records the IDs of the cancelled jobs
+	cancelled []string
+	// children are the job resources returned by jobs.list
+	children []map[string]any
+	// parentJobIDs records the parent job IDs of the jobs.list requests
+	parentJobIDs []string
+}
+
+// newFakeClient starts a fake BigQuery server and returns a client that is
+// connected to it. The server is closed when the test finishes.
+func newFakeClient(t *testing.T, fake *fakeBigQuery) *Client {
+	t.Helper()
+	fake.jobs = map[string]map[string]any{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, err := NewClient(context.Background(), "test-project",
+		option.WithEndpoint(server.URL+"/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// ServeHTTP implements http.Handler.
+func (f *fakeBigQuery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "jobs":
+		var job map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		config := job["configuration"].(map[string]any)
+		jobID := job["jobReference"].(map[string]any)["jobId"].(string)
+		if _, ok := f.jobs[jobID]; ok {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]any{
+				"error": map[string]any{"code": http.StatusConflict, "message": "Already Exists: Job " + jobID},
+			})
+			return
+		}
+		f.queries = append(f.queries, config["query"].(map[string]any)["query"].(string))
+		f.jobs[jobID] = config
+		json.NewEncoder(w).Encode(f.job(jobID))
+	case r.Method == http.MethodPost && len(parts) == 5 && parts[2] == "jobs" && parts[4] == "cancel":
+		f.cancelled = append(f.cancelled, parts[3])
+		json.NewEncoder(w).Encode(map[string]any{"job": f.job(parts[3])})
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "jobs":
+		f.parentJobIDs = append(f.parentJobIDs, r.URL.Query().Get("parentJobId"))
+		json.NewEncoder(w).Encode(map[string]any{"jobs": f.children})
+	case r.Method == http.MethodGet && len(parts) == 4 && parts[2] == "jobs":
+		if f.failJobGets > 0 {
+			f.failJobGets--
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{
+				"error": map[string]any{"code": http.StatusBadRequest, "message": "fake get error"},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(f.job(parts[3]))
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "queries":
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobID := "fast-path-job"
+		f.queries = append(f.queries, request["query"].(string))
+		f.jobs[jobID] = map[string]any{"query": request}
+		if f.errorResult != "" {
+			f.writeError(w)
+			return
+		}
+		json.NewEncoder(w).Encode(f.results(jobID, ""))
+	case r.Method == http.MethodGet && len(parts) == 4 && parts[2] == "queries":
+		pageToken := r.URL.Query().Get("pageToken")
+		if f.failPages && pageToken != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{
+				"error": map[string]any{"code": http.StatusBadRequest, "message": "fake page error"},
+			})
+			return
+		}
+		if f.errorResult != "" {
+			f.writeError(w)
+			return
+		}
+		json.NewEncoder(w).Encode(f.results(parts[3], pageToken))
+	case r.Method == http.MethodGet && len(parts) == 5 && parts[4] == "tables":
+		json.NewEncoder(w).Encode(map[string]any{"tables": f.tables, "totalItems": len(f.tables)})
+	case r.Method == http.MethodGet && len(parts) == 6 && parts[4] == "tables":
+		f.tableGets++
+		for _, table := range f.tables {
+			if table["tableReference"].(map[string]any)["tableId"] == parts[5] {
+				json.NewEncoder(w).Encode(table)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// job returns the job resource for the given job ID.
+func (f *fakeBigQuery) job(jobID string) map[string]any {
+	statistics := map[string]any{}
+	for key, value := range f.statistics {
+		statistics[key] = value
+	}
+	statistics["schema"] = map[string]any{"fields": f.schema}
+	job := map[string]any{
+		"jobReference":  map[string]any{"projectId": "test-project", "jobId": jobID, "location": "US"},
+		"configuration": f.jobs[jobID],
+		"status":        map[string]any{"state": f.state(jobID)},
+		"statistics": map[string]any{
+			"totalBytesProcessed": statistics["totalBytesProcessed"],
+			"query":               statistics,
+		},
+	}
+	if f.sessionID != "" {
+		job["statistics"].(map[string]any)["sessionInfo"] = map[string]any{"sessionId": f.sessionID}
+	}
+	if f.noStatistics {
+		delete(job, "statistics")
+	}
+	if f.errorResult != "" {
+		job["status"] = map[string]any{
+			"state":       "DONE",
+			"errorResult": map[string]any{"reason": f.errorResult, "message": "fake error"},
+		}
+	}
+	return job
+}
+
+// results returns the page of query results for the given job ID.
+func (f *fakeBigQuery) results(jobID, pageToken string) map[string]any {
+	start, _ := strconv.Atoi(pageToken)
+	end := len(f.rows)
+	nextToken := ""
+	if f.pageSize > 0 && start+f.pageSize < end {
+		end = start + f.pageSize
+		nextToken = strconv.Itoa(end)
+	}
+	rows := []map[string]any{}
+	for _, row := range f.rows[start:end] {
+		cells := []map[string]any{}
+		for _, value := range row {
+			cells = append(cells, map[string]any{"v": value})
+		}
+		rows = append(rows, map[string]any{"f": cells})
+	}
+	return map[string]any{
+		"jobReference": map[string]any{"projectId": "test-project", "jobId": jobID, "location": "US"},
+		"jobComplete":  f.state(jobID) == "DONE",
+		"schema":       map[string]any{"fields": f.schema},
+		"rows":         rows,
+		"totalRows":    strconv.Itoa(len(f.rows)),
+		"pageToken":    nextToken,
+	}
+}
+
+// state returns the state of the job with the given ID.
+func (f *fakeBigQuery) state(jobID string) string {
+	if f.running && !slices.Contains(f.cancelled, jobID) {
+		return "RUNNING"
+	}
+	return "DONE"
+}
+
+// cancelledJobs returns the IDs of the cancelled jobs.
+func (f *fakeBigQuery) cancelledJobs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.cancelled...)
+}
+
+// writeError writes a BigQuery error response.
+func (f *fakeBigQuery) writeError(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    http.StatusBadRequest,
+			"message": "fake error",
+			"errors":  []map[string]any{{"reason": f.errorResult, "message": "fake error"}},
+		},
+	})
+}
+
+// table returns a table resource in the dataset with the given creation
+// time and labels.
+func table(dataset, tableID string, created time.Time, labels map[string]string) map[string]any {
+	return map[string]any{
+		"tableReference": map[string]any{"projectId": "test-project", "datasetId": dataset, "tableId": tableID},
+		"type":           "TABLE",
+		"creationTime":   strconv.FormatInt(created.UnixMilli(), 10),
+		"labels":         labels,
+	}
+}
+
+// executedQueries returns the SQL of all submitted queries.
+func (f *fakeBigQuery) executedQueries() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.queries...)
+}
+
+// childJob returns a job resource of a script statement for jobs.list, that
+// was created at the given time.
+func childJob(jobID, statement string, created time.Time) map[string]any {
+	return map[string]any{
+		"id":            "test-project:US." + jobID,
+		"jobReference":  map[string]any{"projectId": "test-project", "jobId": jobID, "location": "US"},
+		"state":         "DONE",
+		"status":        map[string]any{"state": "DONE"},
+		"configuration": map[string]any{"query": map[string]any{"query": statement}},
+		"statistics": map[string]any{
+			"creationTime": strconv.FormatInt(created.UnixMilli(), 10),
+			"scriptStatistics": map[string]any{
+				"evaluationKind": "STATEMENT",
+				"stackFrames":    []map[string]any{{"startLine": 1, "startColumn": 1, "text": statement}},
+			},
+			"query": map[string]any{"statementType": "SELECT"},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package saferbq
+
+import "fmt"
+
+// ExternalQuery returns a $identifier value that reads the result of the
+// inner SQL from an external database, such as Cloud SQL or Spanner, with
+// BigQuery's EXTERNAL_QUERY function. The connection is validated with the
+// rules of ConnectionID, and the inner SQL is escaped as a string literal,
+// so it is sent to the external database exactly as given. The inner SQL
+// runs in the external database, so it can't use the parameters of the
+// outer query.
+//
+// Example:
+//
+//	q := client.Query("SELECT c.id, o.total FROM $customers AS c JOIN $orders AS o ON o.customer_id = c.id WHERE o.total > @min")
+//	q.SetParams(map[string]any{
+//		"$customers": saferbq.ExternalQuery("my-project.us.crm", "SELECT id, name FROM customers"),
+//		"$orders":    "sales.orders",
+//		"@min":       100,
+//	})
+//	// SELECT c.id, o.total FROM EXTERNAL_QUERY('my-project.us.crm', 'SELECT id, name FROM customers') AS c
+//	//   JOIN `sales`.`orders` AS o ON o.customer_id = c.id WHERE o.total > @min
+func ExternalQuery(connectionID, innerSQL string) any {
+	return externalQueryValue{connection: connectionID, sql: innerSQL}
+}
+
+// externalQueryValue is the value of an EXTERNAL_QUERY parameter.
+type externalQueryValue struct {
+	connection string
+	sql        string
+}
+
+// render validates the connection and renders the EXTERNAL_QUERY call.
+func (v externalQueryValue) render(name string) (string, error) {
+	path, err := connectionPath(v.connection)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s must be a valid %s", err, name, connectionIdent)
+	}
+	if v.sql == "" {
+		return "", fmt.Errorf("%w: %s has no inner SQL", ErrEmptySQL, name)
+	}
+	return "EXTERNAL_QUERY(" + EscapeStringLiteral(path) + ", " + EscapeStringLiteral(v.sql) + ")", nil
+}
+
+// FederatedQuery returns a query that selects all rows of the inner SQL,
+// run in an external database through the connection, see ExternalQuery.
+// To filter or join the result in BigQuery, use ExternalQuery as the value
+// of a $identifier in a query instead.
+//
+// Example:
+//
+//	it, err := client.FederatedQuery("projects/my-project/locations/us/connections/crm",
+//	    "SELECT id, name FROM customers WHERE active").Read(ctx)
+//	// SELECT * FROM EXTERNAL_QUERY('my-project.us.crm', 'SELECT id, name FROM customers WHERE active')
+//
+// The connection and inner SQL are validated when the query is executed.
+func (c *Client) FederatedQuery(connectionID, innerSQL string) *Query {
+	q := c.Query("SELECT * FROM $external")
+	q.SetParams(map[string]any{"$external": ExternalQuery(connectionID, innerSQL)})
+	return q
+}
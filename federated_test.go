@@ -0,0 +1,58 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestExternalQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection string
+		innerSQL   string
+		sqlOut     string
+		err        error
+	}{
+		{"path", "my-project.us.crm", "SELECT id, name FROM customers",
+			"SELECT * FROM EXTERNAL_QUERY('my-project.us.crm', 'SELECT id, name FROM customers') WHERE id > @min", nil},
+		{"resource name", "projects/my-project/locations/eu/connections/crm", "SELECT * FROM t WHERE name = 'o''reilly'\n",
+			"SELECT * FROM EXTERNAL_QUERY('my-project.eu.crm', 'SELECT * FROM t WHERE name = \\'o\\'\\'reilly\\'\\n') WHERE id > @min", nil},
+		{"inner parameters are kept", "my-project.us.crm", "SELECT * FROM t WHERE id = $1",
+			"SELECT * FROM EXTERNAL_QUERY('my-project.us.crm', 'SELECT * FROM t WHERE id = $1') WHERE id > @min", nil},
+		{"invalid connection", "my-project.us.crm', 'DROP TABLE t') --", "SELECT 1", "", ErrIdentifierInvalidChars},
+		{"empty inner SQL", "my-project.us.crm", "", "", ErrEmptySQL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, params, err := translate("SELECT * FROM $ext WHERE id > @min", []bigquery.QueryParameter{
+				{Name: "$ext", Value: ExternalQuery(tt.connection, tt.innerSQL)},
+				{Name: "@min", Value: 10},
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("translate() error = %v, want %v", err, tt.err)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+			if err == nil && (len(params) != 1 || params[0].Name != "min") {
+				t.Errorf("translate() params = %v, want [min]", params)
+			}
+		})
+	}
+}
+
+func TestClientFederatedQuery(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	if _, err := client.FederatedQuery("my-project.us.crm", "SELECT id FROM customers").Read(context.Background()); err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	expected := "SELECT * FROM EXTERNAL_QUERY('my-project.us.crm', 'SELECT id FROM customers')"
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Read() queries = %q, want %q", queries, expected)
+	}
+}
@@ -0,0 +1,113 @@
+package saferbq
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Fragment is a piece of SQL that was built from validated input, such as
+// an ORDER BY clause. It is used as the value of a $identifier and injected
+// as is, so large queries can be assembled from safe parts. A Fragment can
+// only be created by the builders of this package, such as NewFragment and
+// OrderBy.
+type Fragment struct {
+	sql string
+}
+
+// NewFragment translates the SQL template with the $identifier values in
+// params into a Fragment. The values are validated and quoted as in a
+// query and may be fragments themselves. Fragments can't contain @named or
+// ? positional parameters, as those are bound by the query they are
+// embedded in.
+//
+// Example:
+//
+//	filter, err := saferbq.NewFragment("WHERE $column IS NOT NULL", map[string]any{
+//	    "$column": userColumn,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	q := client.Query("SELECT * FROM $table $filter")
+//	q.SetParams(map[string]any{"$table": "events", "$filter": filter})
+//
+// Returns an error if the template is empty, contains @named or ?
+// positional parameters, or if the values don't match the template.
+func NewFragment(sql string, params map[string]any) (Fragment, error) {
+	t, err := parse(sql)
+	if err != nil {
+		return Fragment{}, fmt.Errorf("failed to build fragment: %w", err)
+	}
+	if len(t.parameters) > 0 || len(t.positionals) > 0 {
+		return Fragment{}, fmt.Errorf("failed to build fragment: %w: fragments can only contain $ identifiers", ErrInvalidParameterName)
+	}
+	parameters := make([]bigquery.QueryParameter, 0, len(params))
+	for _, name := range sortedKeys(params) {
+		parameters = append(parameters, bigquery.QueryParameter{Name: name, Value: params[name]})
+	}
+	translated, _, err := t.bind(parameters, bindOptions{})
+	if err != nil {
+		return Fragment{}, fmt.Errorf("failed to build fragment: %w", err)
+	}
+	return Fragment{sql: translated}, nil
+}
+
+// String returns the SQL of the fragment.
+func (f Fragment) String() string {
+	return f.sql
+}
+
+// render returns the SQL of the fragment.
+func (f Fragment) render(name string) (string, error) {
+	return f.sql, nil
+}
+
+// SortSpec is a requested sort column of OrderBy.
+type SortSpec struct {
+	// Column is the name of the column to sort on
+	Column string
+	// Descending sorts the column in descending order
+	Descending bool
+}
+
+// OrderBy builds an ORDER BY clause for the requested sort columns. Every
+// requested column must be in the allowed columns. The columns are quoted
+// and the clause is empty when no sort columns are requested.
+//
+// Example:
+//
+//	order, err := saferbq.OrderBy([]string{"name", "created_at"},
+//	    saferbq.SortSpec{Column: userColumn, Descending: true})
+//	if err != nil {
+//	    return err
+//	}
+//	q := client.Query("SELECT * FROM events $order LIMIT 100")
+//	q.SetParams(map[string]any{"$order": order})
+//	// SELECT * FROM events ORDER BY `created_at` DESC LIMIT 100
+//
+// Returns an error wrapping ErrIdentifierNotAllowed if a requested column
+// is not allowed. The error doesn't contain the requested column, as it
+// usually is user input.
+func OrderBy(allowed []string, requested ...SortSpec) (Fragment, error) {
+	if len(requested) == 0 {
+		return Fragment{}, nil
+	}
+	columns := make([]string, 0, len(requested))
+	for _, spec := range requested {
+		if !slices.Contains(allowed, spec.Column) {
+			return Fragment{}, redactValue(ErrIdentifierNotAllowed, "sort column")
+		}
+		quoted, err := quotePath("sort column", spec.Column, QuotePerPart)
+		if err != nil {
+			return Fragment{}, err
+		}
+		if spec.Descending {
+			quoted += " DESC"
+		}
+		columns = append(columns, quoted)
+	}
+	return Fragment{sql: "ORDER BY " + strings.Join(columns, ", ")}, nil
+}
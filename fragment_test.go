@@ -0,0 +1,102 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestOrderBy(t *testing.T) {
+	allowed := []string{"name", "created_at", "e.score"}
+	tests := []struct {
+		name      string
+		requested []SortSpec
+		sqlOut    string
+		err       error
+	}{
+		{"no sort", nil, "SELECT * FROM events e  LIMIT 10", nil},
+		{"single column", []SortSpec{{Column: "name"}}, "SELECT * FROM events e ORDER BY `name` LIMIT 10", nil},
+		{"descending", []SortSpec{{Column: "created_at", Descending: true}}, "SELECT * FROM events e ORDER BY `created_at` DESC LIMIT 10", nil},
+		{"multiple columns", []SortSpec{{Column: "e.score", Descending: true}, {Column: "name"}}, "SELECT * FROM events e ORDER BY `e`.`score` DESC, `name` LIMIT 10", nil},
+		{"column not allowed", []SortSpec{{Column: "password"}}, "", ErrIdentifierNotAllowed},
+		{"injection attempt", []SortSpec{{Column: "name; DROP TABLE events"}}, "", ErrIdentifierNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := OrderBy(allowed, tt.requested...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("OrderBy() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			sqlOut, _, err := translate("SELECT * FROM events e $order LIMIT 10", []bigquery.QueryParameter{{Name: "$order", Value: order}})
+			if err != nil {
+				t.Fatalf("translate() unexpected error: %v", err)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestOrderByInvalidAllowedColumn(t *testing.T) {
+	_, err := OrderBy([]string{"a;b"}, SortSpec{Column: "a;b"})
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("OrderBy() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+}
+
+func TestNewFragment(t *testing.T) {
+	order, err := OrderBy([]string{"name"}, SortSpec{Column: "name"})
+	if err != nil {
+		t.Fatalf("OrderBy() unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		sql     string
+		params  map[string]any
+		fragOut string
+		err     error
+	}{
+		{"identifier", "WHERE $column IS NOT NULL", map[string]any{"$column": "status"}, "WHERE `status` IS NOT NULL", nil},
+		{"no parameters", "WHERE deleted IS NULL", nil, "WHERE deleted IS NULL", nil},
+		{"nested fragment", "$filter $order", map[string]any{"$filter": Fragment{sql: "WHERE a = 1"}, "$order": order}, "WHERE a = 1 ORDER BY `name`", nil},
+		{"invalid identifier", "WHERE $column IS NULL", map[string]any{"$column": "a;b"}, "", ErrIdentifierInvalidChars},
+		{"missing identifier", "WHERE $column IS NULL", nil, "", ErrIdentifierNotProvided},
+		{"named parameter", "WHERE id = @id", map[string]any{"@id": 1}, "", ErrInvalidParameterName},
+		{"positional parameter", "WHERE id = ?", nil, "", ErrInvalidParameterName},
+		{"empty", "", nil, "", ErrEmptySQL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fragment, err := NewFragment(tt.sql, tt.params)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NewFragment() error = %v, want %v", err, tt.err)
+			}
+			if fragment.String() != tt.fragOut {
+				t.Errorf("NewFragment() = %q, want %q", fragment.String(), tt.fragOut)
+			}
+		})
+	}
+}
+
+func TestFragmentInQuery(t *testing.T) {
+	filter, err := NewFragment("WHERE $column > 0", map[string]any{"$column": "amount"})
+	if err != nil {
+		t.Fatalf("NewFragment() unexpected error: %v", err)
+	}
+	q := (&Client{}).Query("SELECT * FROM $table $filter AND day = @day")
+	q.SetParams(map[string]any{"$table": "orders", "$filter": filter, "@day": "2024-01-01"})
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	want := "SELECT * FROM `orders` WHERE `amount` > 0 AND day = @day"
+	if q.Q != want {
+		t.Errorf("translate() = %q, want %q", q.Q, want)
+	}
+}
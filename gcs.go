@@ -0,0 +1,38 @@
+package saferbq
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// bucketRegex matches Cloud Storage bucket names: 3 to 63 lowercase
+// letters, digits, dashes, underscores and dots, starting and ending with
+// a letter or digit
+var bucketRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,61}[a-z0-9]$`)
+
+// validateGCSURI checks that the URI has the form gs://bucket/object, with
+// a valid bucket name and an object name that contains at most one *
+// wildcard and no control characters.
+func validateGCSURI(uri string) error {
+	rest, ok := strings.CutPrefix(uri, "gs://")
+	if !ok {
+		return fmt.Errorf("%w: %q must start with gs://", ErrInvalidURI, uri)
+	}
+	bucket, object, _ := strings.Cut(rest, "/")
+	if !bucketRegex.MatchString(bucket) || strings.Contains(bucket, "..") {
+		return fmt.Errorf("%w: %q has an invalid bucket name", ErrInvalidURI, uri)
+	}
+	if object == "" {
+		return fmt.Errorf("%w: %q has no object name", ErrInvalidURI, uri)
+	}
+	if strings.Count(object, "*") > 1 {
+		return fmt.Errorf("%w: %q has more than one wildcard", ErrInvalidURI, uri)
+	}
+	for _, r := range object {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("%w: %q contains control characters", ErrInvalidURI, uri)
+		}
+	}
+	return nil
+}
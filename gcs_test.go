@@ -0,0 +1,36 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateGCSURI(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		err  error
+	}{
+		{"object", "gs://my-bucket/path/to/file.csv", nil},
+		{"wildcard", "gs://my-bucket/exports/*.csv", nil},
+		{"bucket with dots", "gs://example.com-data/file.json", nil},
+		{"no scheme", "my-bucket/file.csv", ErrInvalidURI},
+		{"other scheme", "s3://my-bucket/file.csv", ErrInvalidURI},
+		{"no object", "gs://my-bucket", ErrInvalidURI},
+		{"empty object", "gs://my-bucket/", ErrInvalidURI},
+		{"uppercase bucket", "gs://My-Bucket/file.csv", ErrInvalidURI},
+		{"short bucket", "gs://ab/file.csv", ErrInvalidURI},
+		{"bucket with double dot", "gs://a..b/file.csv", ErrInvalidURI},
+		{"bucket ending with dash", "gs://bucket-/file.csv", ErrInvalidURI},
+		{"two wildcards", "gs://my-bucket/*/*.csv", ErrInvalidURI},
+		{"control characters", "gs://my-bucket/file\n.csv", ErrInvalidURI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateGCSURI(tt.uri); !errors.Is(err, tt.err) {
+				t.Errorf("validateGCSURI(%q) error = %v, want %v", tt.uri, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package saferbq
+
+import (
+	"cloud.google.com/go/bigquery"
+)
+
+// Translation is the SQL and the parameters of a query that a
+// TranslationHook inspects or modifies.
+type Translation struct {
+	// SQL is the query text, with $identifier placeholders before the
+	// translation and quoted identifiers after it
+	SQL string
+	// Parameters are the query parameters, including the $identifier
+	// parameters before the translation and only the BigQuery parameters
+	// after it
+	Parameters []bigquery.QueryParameter
+}
+
+// TranslationHook inspects or modifies queries before and after their
+// $identifier parameters are translated, for cross-cutting concerns like
+// appending environment comments, enforcing policies or collecting metrics.
+// An error returned by a hook fails the translation, so the query is not
+// executed.
+//
+// Hooks are called once per query, from the goroutine that runs it.
+type TranslationHook interface {
+	// BeforeTranslate is called with the SQL and parameters as set on the query
+	BeforeTranslate(t *Translation) error
+	// AfterTranslate is called with the translated SQL and parameters
+	AfterTranslate(t *Translation) error
+}
+
+// HookFuncs is a TranslationHook built from functions, either may be nil.
+//
+// Example:
+//
+//	client.Use(saferbq.HookFuncs{
+//	    After: func(t *saferbq.Translation) error {
+//	        t.SQL += " -- env=production"
+//	        return nil
+//	    },
+//	})
+type HookFuncs struct {
+	Before func(t *Translation) error
+	After  func(t *Translation) error
+}
+
+// BeforeTranslate implements TranslationHook.
+func (h HookFuncs) BeforeTranslate(t *Translation) error {
+	if h.Before == nil {
+		return nil
+	}
+	return h.Before(t)
+}
+
+// AfterTranslate implements TranslationHook.
+func (h HookFuncs) AfterTranslate(t *Translation) error {
+	if h.After == nil {
+		return nil
+	}
+	return h.After(t)
+}
+
+// Use adds translation hooks to the client and returns the client to allow
+// chaining. Hooks are called in the order they were added, both before and
+// after the translation. Like options, hooks should be added before the
+// client is used to run queries.
+//
+// Example:
+//
+//	client.Use(policyHook, commentHook)
+func (c *Client) Use(hooks ...TranslationHook) *Client {
+	c.hooks = append(c.hooks, hooks...)
+	return c
+}
+
+// runHooks calls the hooks of the client with the translation, stopping at
+// the first error. The client may be nil.
+func (c *Client) runHooks(t *Translation, after bool) error {
+	if c == nil {
+		return nil
+	}
+	for _, hook := range c.hooks {
+		var err error
+		if after {
+			err = hook.AfterTranslate(t)
+		} else {
+			err = hook.BeforeTranslate(t)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestUse(t *testing.T) {
+	var calls []string
+	client := (&Client{}).Use(
+		HookFuncs{
+			Before: func(tr *Translation) error {
+				calls = append(calls, "before 1: "+tr.SQL)
+				tr.Parameters = append(tr.Parameters, bigquery.QueryParameter{Name: "$table", Value: "events"})
+				return nil
+			},
+			After: func(tr *Translation) error {
+				calls = append(calls, "after 1: "+tr.SQL)
+				tr.SQL += " -- env=test"
+				return nil
+			},
+		},
+		HookFuncs{
+			After: func(tr *Translation) error {
+				calls = append(calls, "after 2: "+tr.SQL)
+				return nil
+			},
+		},
+	)
+
+	q := client.Query("SELECT * FROM $table")
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	if want := "SELECT * FROM `events` -- env=test"; q.Q != want {
+		t.Errorf("SQL = %q, want %q", q.Q, want)
+	}
+	want := []string{
+		"before 1: SELECT * FROM $table",
+		"after 1: SELECT * FROM `events`",
+		"after 2: SELECT * FROM `events` -- env=test",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+
+	// Hooks are called once per query
+	if err := q.translate(); err != nil || len(calls) != 3 {
+		t.Errorf("translate() = %v with %d calls, want no error and 3 calls", err, len(calls))
+	}
+}
+
+func TestUsePreparedStatement(t *testing.T) {
+	client := (&Client{}).Use(HookFuncs{
+		Before: func(tr *Translation) error {
+			tr.SQL = strings.Replace(tr.SQL, "SELECT *", "SELECT id", 1)
+			return nil
+		},
+	})
+	stmt, err := client.Prepare("SELECT * FROM $table")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+	q := stmt.Query()
+	q.SetParams(map[string]any{"$table": "events"})
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	if want := "SELECT id FROM `events`"; q.Q != want {
+		t.Errorf("SQL = %q, want %q", q.Q, want)
+	}
+}
+
+func TestUseRejects(t *testing.T) {
+	errPolicy := errors.New("SELECT * is not allowed")
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake).Use(HookFuncs{
+		Before: func(tr *Translation) error {
+			if strings.Contains(tr.SQL, "SELECT *") {
+				return errPolicy
+			}
+			return nil
+		},
+	})
+
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	if _, err := q.Run(context.Background()); !errors.Is(err, errPolicy) {
+		t.Errorf("Run() error = %v, want %v", err, errPolicy)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("executed queries = %v, want none", queries)
+	}
+}
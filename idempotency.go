@@ -0,0 +1,157 @@
+package saferbq
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// idempotentJobIDPrefix is the prefix of the job IDs derived from an
+// idempotency key
+const idempotentJobIDPrefix = "saferbq_"
+
+// SetIdempotencyKey derives the job ID of the query from a hash of the
+// translated SQL, the parameter values and the key, instead of a random job
+// ID. When Run submits the same query with the same key again, for example
+// after a network error hid the response of the first submission, BigQuery
+// rejects the duplicate job ID and Run returns the existing job, so the
+// query is not run twice. It returns the query to allow chaining.
+//
+// Use a key that identifies the unit of work, such as a request ID or the
+// day of a backfill. A job ID can only be used once per project, so a
+// failed job is not resubmitted with the same key: Run returns an error
+// wrapping ErrJobFailed with the error of the failed job instead.
+//
+// Example:
+//
+//	q := client.Query("INSERT INTO $table SELECT * FROM $staging WHERE day = @day")
+//	q.SetParams(map[string]any{"$table": "events", "$staging": "staging", "@day": day})
+//	job, err := q.SetIdempotencyKey("backfill-" + day).Run(ctx)
+func (q *Query) SetIdempotencyKey(key string) *Query {
+	q.idempotencyKey = key
+	return q
+}
+
+// applyJobID sets the job ID derived from the idempotency key of the
+// translated query, if it has one.
+func (q *Query) applyJobID() {
+	if q.idempotencyKey == "" {
+		return
+	}
+	project := ""
+	if q.client != nil {
+		project = q.client.Project()
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", q.idempotencyKey, project, q.QueryConfig.Q)
+	writeParameters(h, q.Parameters)
+	q.JobID = idempotentJobIDPrefix + hex.EncodeToString(h.Sum(nil))
+	q.AddJobIDSuffix = false
+}
+
+// writeParameters writes the names and the canonical encoding of the
+// values of the parameters, see writeValue.
+func writeParameters(w io.Writer, params []bigquery.QueryParameter) {
+	for _, p := range params {
+		fmt.Fprintf(w, "%s\x00", p.Name)
+		writeValue(w, reflect.ValueOf(p.Value), 0)
+		io.WriteString(w, "\x00")
+	}
+}
+
+// maxValueDepth bounds the nesting of the values that writeValue encodes.
+const maxValueDepth = 32
+
+// writeValue writes a canonical encoding of the value with its type, so
+// equal values are encoded the same: pointers are followed, values that
+// implement encoding.TextMarshaler (such as times, without their monotonic
+// clock reading) are written as text and maps are written in key order.
+func writeValue(w io.Writer, v reflect.Value, depth int) {
+	if !v.IsValid() {
+		io.WriteString(w, "nil")
+		return
+	}
+	if depth > maxValueDepth {
+		io.WriteString(w, "...")
+		return
+	}
+	fmt.Fprintf(w, "%s(", v.Type())
+	defer io.WriteString(w, ")")
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			io.WriteString(w, "nil")
+			return
+		}
+	}
+	if v.CanInterface() {
+		if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+			if text, err := m.MarshalText(); err == nil {
+				w.Write(text)
+				return
+			}
+		}
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		writeValue(w, v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			writeValue(w, v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		entries := make([]string, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			var entry strings.Builder
+			writeValue(&entry, iter.Key(), depth+1)
+			entry.WriteString(":")
+			writeValue(&entry, iter.Value(), depth+1)
+			entries = append(entries, entry.String())
+		}
+		slices.Sort(entries)
+		io.WriteString(w, strings.Join(entries, ","))
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			fmt.Fprintf(w, "%s:", v.Type().Field(i).Name)
+			writeValue(w, v.Field(i), depth+1)
+		}
+	default:
+		fmt.Fprintf(w, "%v", v)
+	}
+}
+
+// existingJob returns the job with the derived job ID of the query when
+// err reports that the job ID is taken, or the error otherwise. When the
+// existing job failed, its error is returned as a permanent error, as
+// retrying would only find the same failed job again.
+func (q *Query) existingJob(ctx context.Context, err error) (*bigquery.Job, error) {
+	var apiErr *googleapi.Error
+	if q.idempotencyKey == "" || q.client == nil || !errors.As(err, &apiErr) || apiErr.Code != http.StatusConflict {
+		return nil, err
+	}
+	job, err := q.client.Client.JobFromIDLocation(ctx, q.JobID, q.Location)
+	if err != nil {
+		return nil, err
+	}
+	if status := job.LastStatus(); status != nil && status.Done() && status.Err() != nil {
+		return nil, permanentError{fmt.Errorf("%w: job %s: %w", ErrJobFailed, job.ID(), status.Err())}
+	}
+	return job, nil
+}
@@ -0,0 +1,131 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+func TestQuerySetIdempotencyKey(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	run := func(key string, day string) string {
+		t.Helper()
+		q := client.Query("DELETE FROM $table WHERE day = @day")
+		q.SetParams(map[string]any{"$table": "events", "@day": day})
+		job, err := q.SetIdempotencyKey(key).Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() unexpected error: %v", err)
+		}
+		return job.ID()
+	}
+
+	first := run("backfill", "2024-01-01")
+	if !strings.HasPrefix(first, "saferbq_") || len(first) != len("saferbq_")+64 {
+		t.Errorf("Run() job ID = %q, want a derived job ID", first)
+	}
+	// A second submission returns the existing job
+	if again := run("backfill", "2024-01-01"); again != first {
+		t.Errorf("Run() job ID = %q, want %q", again, first)
+	}
+	if queries := fake.executedQueries(); len(queries) != 1 {
+		t.Errorf("submitted %d queries, want 1", len(queries))
+	}
+	if other := run("backfill", "2024-01-02"); other == first {
+		t.Error("Run() with other parameters has the same job ID")
+	}
+	if other := run("other", "2024-01-01"); other == first {
+		t.Error("Run() with another key has the same job ID")
+	}
+}
+
+func TestQueryWithoutIdempotencyKey(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ids := map[string]bool{}
+	for range 2 {
+		q := client.Query("DELETE FROM $table WHERE true")
+		q.SetParams(map[string]any{"$table": "events"})
+		job, err := q.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() unexpected error: %v", err)
+		}
+		ids[job.ID()] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("Run() job IDs = %v, want 2 random job IDs", ids)
+	}
+}
+
+func TestQueryIdempotencyKeyCanonicalValues(t *testing.T) {
+	client := (&Client{}).Configure()
+	jobID := func(value any) string {
+		t.Helper()
+		q := client.Query("SELECT * FROM t WHERE v = @v")
+		q.SetParams(map[string]any{"@v": value})
+		if err := q.translate(); err != nil {
+			t.Fatalf("translate() unexpected error: %v", err)
+		}
+		q.SetIdempotencyKey("key").applyJobID()
+		return q.JobID
+	}
+	type filter struct {
+		Day  time.Time
+		Tags map[string]int
+	}
+
+	// Times with a monotonic clock reading hash like the same time without
+	now := time.Now()
+	if jobID(now) != jobID(now.Round(0)) {
+		t.Error("job ID depends on the monotonic clock reading")
+	}
+	// Pointers hash by the value they point to
+	a := &filter{Day: now, Tags: map[string]int{"a": 1, "b": 2}}
+	b := &filter{Day: now.Round(0), Tags: map[string]int{"b": 2, "a": 1}}
+	if jobID(a) != jobID(b) {
+		t.Error("job ID depends on the address of the value")
+	}
+	if jobID(a) == jobID(&filter{Day: now, Tags: map[string]int{"a": 1}}) {
+		t.Error("job ID is the same for different values")
+	}
+	if jobID(int64(1)) == jobID("1") {
+		t.Error("job ID is the same for values of different types")
+	}
+}
+
+func TestQueryIdempotencyKeyFailedJob(t *testing.T) {
+	fake := &fakeBigQuery{errorResult: "backendError"}
+	client := newFakeClient(t, fake).Configure(WithRetry(3, time.Minute))
+	run := func() (*bigquery.Job, error) {
+		q := client.Query("DELETE FROM $table WHERE true")
+		q.SetParams(map[string]any{"$table": "events"})
+		return q.SetIdempotencyKey("cleanup").Run(context.Background())
+	}
+	if _, err := run(); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	// The existing job failed with a transient error, but it is not retried
+	start := time.Now()
+	_, err := run()
+	var bqErr *bigquery.Error
+	if !errors.Is(err, ErrJobFailed) || !errors.As(err, &bqErr) || bqErr.Reason != "backendError" {
+		t.Errorf("Run() error = %v, want ErrJobFailed with the job error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run() took %v, want no retries", elapsed)
+	}
+}
+
+func TestQueryExistingJobWithoutClient(t *testing.T) {
+	q := &Query{idempotencyKey: "key"}
+	conflict := &googleapi.Error{Code: http.StatusConflict}
+	if _, err := q.existingJob(context.Background(), conflict); err != conflict {
+		t.Errorf("existingJob() error = %v, want %v", err, conflict)
+	}
+}
@@ -0,0 +1,307 @@
+package saferbq
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minProjectIDLength is the minimum length of a project ID
+	minProjectIDLength = 6
+	// maxProjectIDLength is the maximum length of a project ID
+	maxProjectIDLength = 30
+	// maxColumnNameLength is the maximum length of a column name in characters
+	maxColumnNameLength = 300
+	// maxRoutineIDLength is the maximum length of a routine ID
+	maxRoutineIDLength = 256
+)
+
+// connectionIDRegex matches the ID of a connection, without its project and
+// location
+var connectionIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// reservedColumnPrefixes are the (case-insensitive) prefixes that BigQuery
+// reserves for pseudo columns.
+var reservedColumnPrefixes = []string{"_table_", "_file_", "_partition", "_row_timestamp", "__root__", "_colidentifier"}
+
+// identKind is the kind of resource an Ident names.
+type identKind int
+
+const (
+	projectIdent identKind = iota
+	datasetIdent
+	tableIdent
+	columnIdent
+	wildcardIdent
+	routineIdent
+	policyIdent
+	connectionIdent
+)
+
+// String returns the name of the kind of resource.
+func (k identKind) String() string {
+	switch k {
+	case projectIdent:
+		return "project ID"
+	case datasetIdent:
+		return "dataset ID"
+	case tableIdent:
+		return "table ID"
+	case wildcardIdent:
+		return "wildcard table"
+	case routineIdent:
+		return "routine ID"
+	case policyIdent:
+		return "row access policy name"
+	case connectionIdent:
+		return "connection"
+	default:
+		return "column name"
+	}
+}
+
+// Ident is a $identifier value that is validated by the naming rules of the
+// kind of resource it names, instead of the generic identifier rules. Use
+// ProjectID, DatasetID, TableID, ColumnName, WildcardTable, RoutineID or
+// ConnectionID to create one.
+//
+// Example:
+//
+//	q := client.Query("SELECT $column FROM $project.$dataset.$table")
+//	q.SetParams(map[string]any{
+//		"$project": saferbq.ProjectID("my-project"),
+//		"$dataset": saferbq.DatasetID("analytics"),
+//		"$table":   saferbq.TableID("events"),
+//		"$column":  saferbq.ColumnName("user_id"),
+//	})
+type Ident struct {
+	kind  identKind
+	value string
+}
+
+// ProjectID returns a $identifier value that names a project. Project IDs
+// are 6 to 30 lowercase letters, digits and hyphens, start with a letter
+// and do not end with a hyphen. Domain-scoped project IDs, such as
+// "example.com:my-project", are also accepted.
+func ProjectID(id string) Ident {
+	return Ident{kind: projectIdent, value: id}
+}
+
+// DatasetID returns a $identifier value that names a dataset. Dataset IDs
+// may only contain letters, digits and underscores and may not exceed 1024
+// characters.
+func DatasetID(id string) Ident {
+	return Ident{kind: datasetIdent, value: id}
+}
+
+// TableID returns a $identifier value that names a table. Table IDs follow
+// the generic identifier rules, but may not contain path separators.
+func TableID(id string) Ident {
+	return Ident{kind: tableIdent, value: id}
+}
+
+// ColumnName returns a $identifier value that names a column. Column names
+// follow the generic identifier rules, may not exceed 300 characters and
+// may not start with a prefix that is reserved for pseudo columns, such as
+// _PARTITION or _TABLE_.
+func ColumnName(name string) Ident {
+	return Ident{kind: columnIdent, value: name}
+}
+
+// RoutineID returns a $identifier value that names a routine, such as a
+// stored procedure or a user-defined function. Routine IDs may only contain
+// letters, digits and underscores and may not exceed 256 characters.
+func RoutineID(id string) Ident {
+	return Ident{kind: routineIdent, value: id}
+}
+
+// ConnectionID returns a $identifier value that names a connection to an
+// external data source, either as a resource name, such as
+// "projects/my-project/locations/us/connections/my-connection", or as
+// "my-project.us.my-connection". The project, location and connection ID
+// are validated, and the value is quoted as a single identifier in the
+// project.location.connection form.
+func ConnectionID(id string) Ident {
+	return Ident{kind: connectionIdent, value: id}
+}
+
+// WildcardTable returns a $identifier value that names the tables of a
+// wildcard table query, such as dataset.events_*. The value must end with
+// a single * (that is allowed nowhere else) after a non-empty table prefix.
+// The path is quoted per part, as in `dataset`.`events_*`.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $events WHERE _TABLE_SUFFIX BETWEEN @from AND @to")
+//	q.SetParams(map[string]any{
+//		"$events": saferbq.WildcardTable("analytics.events_*"),
+//		"@from":   "20240101",
+//		"@to":     "20240131",
+//	})
+func WildcardTable(path string) Ident {
+	return Ident{kind: wildcardIdent, value: path}
+}
+
+// String returns the unquoted value of the identifier.
+func (i Ident) String() string {
+	return i.value
+}
+
+// render validates the value by the rules of its kind and quotes it.
+func (i Ident) render(name string) (string, error) {
+	if i.value == "" {
+		return "", fmt.Errorf("%w: %s", ErrIdentifierEmpty, name)
+	}
+	if i.kind == wildcardIdent {
+		return renderWildcardTable(name, i.value)
+	}
+	if i.kind == connectionIdent {
+		path, err := connectionPath(i.value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a valid %s", err, name, i.kind)
+		}
+		// The validated path only contains characters that are safe in backticks
+		return string(backtick) + path + string(backtick), nil
+	}
+	var err error
+	switch i.kind {
+	case projectIdent:
+		err = validateProjectID(i.value)
+	case datasetIdent:
+		err = validateDatasetID(i.value)
+	case tableIdent:
+		err = validateTableID(i.value)
+	case columnIdent:
+		err = validateColumnName(i.value)
+	case routineIdent, policyIdent:
+		err = validateRoutineID(i.value)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %s must be a valid %s", err, name, i.kind)
+	}
+	return quoteIdentifierValue(name, i.value)
+}
+
+// renderWildcardTable validates the wildcard table path and quotes it per
+// part, with the * inside the backticks of the last part.
+func renderWildcardTable(name, path string) (string, error) {
+	prefix, ok := strings.CutSuffix(path, "*")
+	if !ok || strings.HasSuffix(prefix, ".") || prefix == "" {
+		return "", fmt.Errorf("%w: %s must be a valid wildcard table", ErrIdentifierInvalidChars, name)
+	}
+	quoted, err := QualifiedIdentifier(prefix).render(name)
+	if err != nil {
+		return "", err
+	}
+	return quoted[:len(quoted)-1] + "*" + string(backtick), nil
+}
+
+// validateProjectID checks the id against the project ID naming rules.
+func validateProjectID(id string) error {
+	if domain, project, ok := strings.Cut(id, ":"); ok {
+		if domain == "" {
+			return ErrIdentifierInvalidChars
+		}
+		for _, r := range domain {
+			if !isLowerAlnum(r) && r != '.' && r != '-' {
+				return ErrIdentifierInvalidChars
+			}
+		}
+		id = project
+	}
+	if len(id) > maxProjectIDLength {
+		return ErrIdentifierTooLong
+	}
+	if len(id) < minProjectIDLength || id[0] < 'a' || id[0] > 'z' || strings.HasSuffix(id, "-") {
+		return ErrIdentifierInvalidChars
+	}
+	for _, r := range id {
+		if !isLowerAlnum(r) && r != '-' {
+			return ErrIdentifierInvalidChars
+		}
+	}
+	return nil
+}
+
+// validateDatasetID checks the id against the dataset ID naming rules.
+func validateDatasetID(id string) error {
+	if len(id) > maxIdentifierBytes {
+		return ErrIdentifierTooLong
+	}
+	for _, r := range id {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return ErrIdentifierInvalidChars
+		}
+	}
+	return nil
+}
+
+// validateTableID checks the id against the table ID naming rules.
+func validateTableID(id string) error {
+	for _, r := range id {
+		if !isValidIdentifierChar(r) {
+			return ErrIdentifierInvalidChars
+		}
+	}
+	return nil
+}
+
+// validateColumnName checks the name against the column naming rules.
+func validateColumnName(name string) error {
+	if utf8.RuneCountInString(name) > maxColumnNameLength {
+		return ErrIdentifierTooLong
+	}
+	if err := validateTableID(name); err != nil {
+		return err
+	}
+	lower := strings.ToLower(name)
+	for _, prefix := range reservedColumnPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return ErrIdentifierNotAllowed
+		}
+	}
+	return nil
+}
+
+// validateRoutineID checks the id against the routine ID naming rules.
+func validateRoutineID(id string) error {
+	if len(id) > maxRoutineIDLength {
+		return ErrIdentifierTooLong
+	}
+	return validateDatasetID(id)
+}
+
+// connectionPath validates the connection id and returns it in the
+// project.location.connection form.
+func connectionPath(id string) (string, error) {
+	var project, location, connection string
+	if rest, ok := strings.CutPrefix(id, "projects/"); ok {
+		parts := strings.Split(rest, "/")
+		if len(parts) != 5 || parts[1] != "locations" || parts[3] != "connections" {
+			return "", ErrIdentifierInvalidChars
+		}
+		project, location, connection = parts[0], parts[2], parts[4]
+	} else {
+		parts := strings.Split(id, ".")
+		if len(parts) < 3 {
+			return "", ErrIdentifierInvalidChars
+		}
+		n := len(parts)
+		project, location, connection = strings.Join(parts[:n-2], "."), parts[n-2], parts[n-1]
+	}
+	if err := validateProjectID(project); err != nil {
+		return "", err
+	}
+	if !locationRegex.MatchString(location) || !connectionIDRegex.MatchString(connection) {
+		return "", ErrIdentifierInvalidChars
+	}
+	return project + "." + location + "." + connection, nil
+}
+
+// isLowerAlnum checks if a rune is a lowercase ASCII letter or a digit.
+func isLowerAlnum(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
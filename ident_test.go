@@ -0,0 +1,82 @@
+package saferbq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestIdent(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  Ident
+		sqlOut string
+		err    error
+	}{
+		{"project", ProjectID("my-project-123"), "SELECT * FROM `my-project-123`", nil},
+		{"domain-scoped project", ProjectID("example.com:my-project"), "SELECT * FROM `example.com:my-project`", nil},
+		{"project empty domain", ProjectID(":my-project"), "", ErrIdentifierInvalidChars},
+		{"project too short", ProjectID("proj"), "", ErrIdentifierInvalidChars},
+		{"project too long", ProjectID(strings.Repeat("a", 31)), "", ErrIdentifierTooLong},
+		{"project uppercase", ProjectID("My-Project"), "", ErrIdentifierInvalidChars},
+		{"project starts with digit", ProjectID("1project"), "", ErrIdentifierInvalidChars},
+		{"project ends with hyphen", ProjectID("project-"), "", ErrIdentifierInvalidChars},
+		{"project with space", ProjectID("my project"), "", ErrIdentifierInvalidChars},
+		{"dataset", DatasetID("analytics_2024"), "SELECT * FROM `analytics_2024`", nil},
+		{"dataset with dash", DatasetID("my-dataset"), "", ErrIdentifierInvalidChars},
+		{"dataset with space", DatasetID("my dataset"), "", ErrIdentifierInvalidChars},
+		{"dataset with unicode", DatasetID("données"), "", ErrIdentifierInvalidChars},
+		{"dataset too long", DatasetID(strings.Repeat("a", 1025)), "", ErrIdentifierTooLong},
+		{"table", TableID("my table-2024"), "SELECT * FROM `my table-2024`", nil},
+		{"table with path", TableID("dataset.table"), "", ErrIdentifierInvalidChars},
+		{"column", ColumnName("user_id"), "SELECT * FROM `user_id`", nil},
+		{"column too long", ColumnName(strings.Repeat("é", 301)), "", ErrIdentifierTooLong},
+		{"column at limit", ColumnName(strings.Repeat("a", 300)), "SELECT * FROM `" + strings.Repeat("a", 300) + "`", nil},
+		{"column with reserved prefix", ColumnName("_PARTITIONTIME"), "", ErrIdentifierNotAllowed},
+		{"column with path", ColumnName("a.b"), "", ErrIdentifierInvalidChars},
+		{"routine", RoutineID("refresh_daily"), "SELECT * FROM `refresh_daily`", nil},
+		{"routine with dash", RoutineID("refresh-daily"), "", ErrIdentifierInvalidChars},
+		{"routine too long", RoutineID(strings.Repeat("a", 257)), "", ErrIdentifierTooLong},
+		{"connection resource", ConnectionID("projects/my-project/locations/us/connections/my_conn"), "SELECT * FROM `my-project.us.my_conn`", nil},
+		{"connection path", ConnectionID("my-project.europe-west4.my-conn"), "SELECT * FROM `my-project.europe-west4.my-conn`", nil},
+		{"connection domain project", ConnectionID("example.com:my-project.us.conn"), "SELECT * FROM `example.com:my-project.us.conn`", nil},
+		{"connection empty domain", ConnectionID(":my-project.us.conn"), "", ErrIdentifierInvalidChars},
+		{"connection without location", ConnectionID("my-project.my-conn"), "", ErrIdentifierInvalidChars},
+		{"connection bad resource", ConnectionID("projects/my-project/connections/my-conn"), "", ErrIdentifierInvalidChars},
+		{"connection bad project", ConnectionID("projects/My-Project/locations/us/connections/c"), "", ErrIdentifierInvalidChars},
+		{"connection backtick", ConnectionID("my-project.us.c`"), "", ErrIdentifierInvalidChars},
+		{"wildcard table", WildcardTable("analytics.events_*"), "SELECT * FROM `analytics`.`events_*`", nil},
+		{"wildcard table with project", WildcardTable("my-project.analytics.events_2024*"), "SELECT * FROM `my-project`.`analytics`.`events_2024*`", nil},
+		{"wildcard without star", WildcardTable("analytics.events_"), "", ErrIdentifierInvalidChars},
+		{"wildcard star in the middle", WildcardTable("analytics.ev*ents_*"), "", ErrIdentifierInvalidChars},
+		{"wildcard double star", WildcardTable("analytics.events_**"), "", ErrIdentifierInvalidChars},
+		{"wildcard without prefix", WildcardTable("analytics.*"), "", ErrIdentifierInvalidChars},
+		{"wildcard star only", WildcardTable("*"), "", ErrIdentifierInvalidChars},
+		{"wildcard empty dataset", WildcardTable(".events_*"), "", ErrIdentifierEmpty},
+		{"wildcard injection attempt", WildcardTable("events`; DROP TABLE x; --*"), "", ErrIdentifierInvalidChars},
+		{"empty", TableID(""), "", ErrIdentifierEmpty},
+		{"injection attempt", TableID("t` WHERE 1=1 --"), "", ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT * FROM $name", []bigquery.QueryParameter{{Name: "$name", Value: tt.value}})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("translate() error = %v, want %v", err, tt.err)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestIdentErrorMessage(t *testing.T) {
+	_, _, err := translate("SELECT * FROM $dataset.events", []bigquery.QueryParameter{{Name: "$dataset", Value: DatasetID("my-dataset")}})
+	want := "identifier contains invalid characters: $dataset must be a valid dataset ID"
+	if err == nil || err.Error() != want {
+		t.Errorf("translate() error = %v, want %q", err, want)
+	}
+}
@@ -104,3 +104,76 @@ func QuoteIdentifier(identifier any) (string, string) {
 	}
 	return string(backtick) + result + string(backtick), replaced
 }
+
+// QuoteQualifiedIdentifier safely quotes a qualified identifier, such as
+// a project.dataset.table path, by splitting it on dots and quoting every
+// part with backticks separately. The dots in a domain-scoped project,
+// such as example.com:project, are kept.
+//
+// Invalid characters are replaced with underscores and returned in the
+// second return value, as in QuoteIdentifier.
+//
+// Example:
+//
+//	quoted, replaced := QuoteQualifiedIdentifier("my-project.my-dataset.my-table")
+//	// quoted = "`my-project`.`my-dataset`.`my-table`", replaced = ""
+//
+// Returns the quoted identifier and a string containing all replaced characters.
+func QuoteQualifiedIdentifier(path string) (string, string) {
+	parts := splitQualifiedPath(path)
+	var result, replaced strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			result.WriteRune('.')
+		}
+		quoted, partReplaced := QuoteIdentifier(part)
+		result.WriteString(quoted)
+		for _, r := range partReplaced {
+			if !strings.ContainsRune(replaced.String(), r) {
+				replaced.WriteRune(r)
+			}
+		}
+	}
+	return result.String(), replaced.String()
+}
+
+// QualifiedIdentifier is a $identifier value that holds a qualified path,
+// such as project.dataset.table. The path is split on dots and every part
+// is validated and quoted separately, see QuoteQualifiedIdentifier.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table")
+//	q.SetParams(map[string]any{"$table": saferbq.QualifiedIdentifier("my-project.my-dataset.my-table")})
+//	// SELECT * FROM `my-project`.`my-dataset`.`my-table`
+type QualifiedIdentifier string
+
+// render validates every part of the path and quotes it.
+func (p QualifiedIdentifier) render(name string) (string, error) {
+	if len(p) > maxIdentifierBytes {
+		return "", fmt.Errorf("%w: %s", ErrIdentifierTooLong, name)
+	}
+	parts := splitQualifiedPath(string(p))
+	for i, part := range parts {
+		quoted, err := quoteIdentifierValue(name, part)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = quoted
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// splitQualifiedPath splits the path on dots, keeping a domain-scoped
+// project (the part up to and including the colon) as a single part.
+func splitQualifiedPath(path string) []string {
+	colon := strings.IndexByte(path, ':')
+	if colon < 0 {
+		return strings.Split(path, ".")
+	}
+	dot := strings.IndexByte(path[colon:], '.')
+	if dot < 0 {
+		return []string{path}
+	}
+	return append([]string{path[:colon+dot]}, strings.Split(path[colon+dot+1:], ".")...)
+}
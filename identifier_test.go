@@ -1,7 +1,10 @@
 package saferbq
 
 import (
+	"strings"
 	"testing"
+
+	"cloud.google.com/go/bigquery"
 )
 
 func TestQuoteIdentifier(t *testing.T) {
@@ -128,6 +131,68 @@ func TestQuoteIdentifier(t *testing.T) {
 	}
 }
 
+func TestQuoteQualifiedIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		pathIn   string
+		pathOut  string
+		replaced string
+	}{
+		{"single part", "mytable", "`mytable`", ""},
+		{"three parts", "my-project.my-dataset.my-table", "`my-project`.`my-dataset`.`my-table`", ""},
+		{"domain-scoped project", "example.com:project.dataset.table", "`example.com:project`.`dataset`.`table`", ""},
+		{"empty part", "dataset..table", "`dataset`.``.`table`", ""},
+		{"invalid characters", "dataset.table`;DROP", "`dataset`.`table__DROP`", "`;"},
+		{"repeated invalid characters", "a;b.c;d", "`a_b`.`c_d`", ";"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathOut, replaced := QuoteQualifiedIdentifier(tt.pathIn)
+			if pathOut != tt.pathOut {
+				t.Errorf("QuoteQualifiedIdentifier(%q) = %q, want %q", tt.pathIn, pathOut, tt.pathOut)
+			}
+			if replaced != tt.replaced {
+				t.Errorf("QuoteQualifiedIdentifier(%q) returned replaced = %q, want %q", tt.pathIn, replaced, tt.replaced)
+			}
+		})
+	}
+}
+
+func TestQualifiedIdentifier(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        QualifiedIdentifier
+		sqlOut       string
+		errorMessage string
+	}{
+		{"table path", "my-project.my-dataset.my-table", "SELECT * FROM `my-project`.`my-dataset`.`my-table`", ""},
+		{"single table", "events", "SELECT * FROM `events`", ""},
+		{"empty part", "dataset..table", "", "identifier is empty: $table"},
+		{"invalid characters", "dataset.table`", "", "identifier contains invalid characters: $table contains `"},
+		{"too long", QualifiedIdentifier(strings.Repeat("a.", 513)), "", "identifier is too long: $table"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT * FROM $table", []bigquery.QueryParameter{{Name: "$table", Value: tt.value}})
+			if err != nil {
+				if tt.errorMessage == "" {
+					t.Fatalf("translate() unexpected error: %v", err)
+				}
+				if err.Error() != tt.errorMessage {
+					t.Fatalf("translate() error = %q, want %q", err.Error(), tt.errorMessage)
+				}
+			} else if tt.errorMessage != "" {
+				t.Fatalf("translate() expected error %q but got none", tt.errorMessage)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
+
 func TestIsValidIdentifierChar(t *testing.T) {
 	tests := []struct {
 		name  string
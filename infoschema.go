@@ -0,0 +1,131 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// listColumnsSQL reads the columns of a table
+	listColumnsSQL = "SELECT column_name, data_type, is_nullable, ordinal_position FROM $dataset.INFORMATION_SCHEMA.COLUMNS WHERE table_name = @table ORDER BY ordinal_position"
+	// tableExistsSQL counts the tables with a name
+	tableExistsSQL = "SELECT COUNT(*) AS n FROM $dataset.INFORMATION_SCHEMA.TABLES WHERE table_name = @table"
+	// listTablesSQL reads the names of the tables with a prefix
+	listTablesSQL = "SELECT table_name FROM $dataset.INFORMATION_SCHEMA.TABLES WHERE STARTS_WITH(table_name, @prefix) ORDER BY table_name"
+)
+
+// ColumnInfo describes a column of a table, as reported by
+// INFORMATION_SCHEMA.COLUMNS.
+type ColumnInfo struct {
+	// Name is the name of the column
+	Name string `bigquery:"column_name"`
+	// DataType is the GoogleSQL data type of the column, such as INT64 or
+	// ARRAY<STRING>
+	DataType string `bigquery:"data_type"`
+	// Nullable is set when the column is nullable ("YES" in the view)
+	Nullable bool `bigquery:"-"`
+	// Position is the 1-based position of the column in the table
+	Position int64 `bigquery:"ordinal_position"`
+}
+
+// ListColumns returns the columns of the table in their order, read from
+// INFORMATION_SCHEMA.COLUMNS of the dataset. The dataset ID is validated
+// (with the rules of DatasetID) and quoted, and the table name is bound as
+// a query parameter. A table that does not exist has no columns.
+//
+// Example:
+//
+//	columns, err := client.ListColumns(ctx, "analytics", "events_"+tenant)
+//
+// Returns an error if the dataset ID is not valid, or if the query fails.
+func (c *Client) ListColumns(ctx context.Context, dataset, table string) ([]ColumnInfo, error) {
+	q := c.Query(listColumnsSQL)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset), "@table": table})
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	defer it.Close()
+	columns := []ColumnInfo{}
+	for {
+		var row struct {
+			ColumnInfo
+			IsNullable string `bigquery:"is_nullable"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list columns: %w", err)
+		}
+		row.Nullable = row.IsNullable == "YES"
+		columns = append(columns, row.ColumnInfo)
+	}
+	return columns, nil
+}
+
+// TableExists reports whether the dataset has a table (or view) with the
+// name, read from INFORMATION_SCHEMA.TABLES of the dataset. The dataset ID
+// is validated (with the rules of DatasetID) and quoted, and the table name
+// is bound as a query parameter.
+//
+// Example:
+//
+//	exists, err := client.TableExists(ctx, "analytics", "events_"+tenant)
+//
+// Returns an error if the dataset ID is not valid, or if the query fails.
+func (c *Client) TableExists(ctx context.Context, dataset, table string) (bool, error) {
+	q := c.Query(tableExistsSQL)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset), "@table": table})
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check table: %w", err)
+	}
+	defer it.Close()
+	var row struct {
+		N int64 `bigquery:"n"`
+	}
+	if err := it.Next(&row); err != nil {
+		return false, fmt.Errorf("failed to check table: %w", err)
+	}
+	return row.N > 0, nil
+}
+
+// ListTables returns the names of the tables (and views) in the dataset
+// that start with the prefix, sorted by name, read from
+// INFORMATION_SCHEMA.TABLES of the dataset. The dataset ID is validated
+// (with the rules of DatasetID) and quoted, and the prefix is bound as a
+// query parameter. An empty prefix lists all tables.
+//
+// Example:
+//
+//	tables, err := client.ListTables(ctx, "analytics", "events_")
+//
+// Returns an error if the dataset ID is not valid, or if the query fails.
+func (c *Client) ListTables(ctx context.Context, dataset, prefix string) ([]string, error) {
+	q := c.Query(listTablesSQL)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset), "@prefix": prefix})
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer it.Close()
+	tables := []string{}
+	for {
+		var row struct {
+			Name string `bigquery:"table_name"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		tables = append(tables, row.Name)
+	}
+	return tables, nil
+}
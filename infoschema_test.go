@@ -0,0 +1,97 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClientListColumns(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{
+			{"name": "column_name", "type": "STRING"},
+			{"name": "data_type", "type": "STRING"},
+			{"name": "is_nullable", "type": "STRING"},
+			{"name": "ordinal_position", "type": "INTEGER"},
+		},
+		rows: [][]any{{"id", "INT64", "NO", "1"}, {"tags", "ARRAY<STRING>", "YES", "2"}},
+	}
+	client := newFakeClient(t, fake)
+	columns, err := client.ListColumns(context.Background(), "analytics", "events")
+	if err != nil {
+		t.Fatalf("ListColumns() unexpected error: %v", err)
+	}
+	expected := []ColumnInfo{
+		{Name: "id", DataType: "INT64", Nullable: false, Position: 1},
+		{Name: "tags", DataType: "ARRAY<STRING>", Nullable: true, Position: 2},
+	}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("ListColumns() = %+v, want %+v", columns, expected)
+	}
+	want := "SELECT column_name, data_type, is_nullable, ordinal_position FROM `analytics`.INFORMATION_SCHEMA.COLUMNS WHERE table_name = @table ORDER BY ordinal_position"
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != want {
+		t.Errorf("ListColumns() queries = %q, want %q", queries, want)
+	}
+}
+
+func TestClientTableExists(t *testing.T) {
+	for _, tt := range []struct {
+		count  string
+		exists bool
+	}{{"1", true}, {"0", false}} {
+		fake := &fakeBigQuery{
+			schema: []map[string]any{{"name": "n", "type": "INTEGER"}},
+			rows:   [][]any{{tt.count}},
+		}
+		client := newFakeClient(t, fake)
+		exists, err := client.TableExists(context.Background(), "analytics", "events")
+		if err != nil {
+			t.Fatalf("TableExists() unexpected error: %v", err)
+		}
+		if exists != tt.exists {
+			t.Errorf("TableExists() = %v, want %v", exists, tt.exists)
+		}
+		want := "SELECT COUNT(*) AS n FROM `analytics`.INFORMATION_SCHEMA.TABLES WHERE table_name = @table"
+		if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != want {
+			t.Errorf("TableExists() queries = %q, want %q", queries, want)
+		}
+	}
+}
+
+func TestClientListTables(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{{"name": "table_name", "type": "STRING"}},
+		rows:   [][]any{{"events_a"}, {"events_b"}},
+	}
+	client := newFakeClient(t, fake)
+	tables, err := client.ListTables(context.Background(), "analytics", "events_")
+	if err != nil {
+		t.Fatalf("ListTables() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tables, []string{"events_a", "events_b"}) {
+		t.Errorf("ListTables() = %v", tables)
+	}
+	want := "SELECT table_name FROM `analytics`.INFORMATION_SCHEMA.TABLES WHERE STARTS_WITH(table_name, @prefix) ORDER BY table_name"
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != want {
+		t.Errorf("ListTables() queries = %q, want %q", queries, want)
+	}
+}
+
+func TestInformationSchemaInvalidDataset(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	if _, err := client.ListColumns(ctx, "analytics.x", "events"); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("ListColumns() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if _, err := client.TableExists(ctx, "analytics`", "events"); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("TableExists() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if _, err := client.ListTables(ctx, "", "events"); !errors.Is(err, ErrIdentifierEmpty) {
+		t.Errorf("ListTables() error = %v, want %v", err, ErrIdentifierEmpty)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("queries = %q, want none", queries)
+	}
+}
@@ -0,0 +1,42 @@
+package saferbq
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// SafeInserter returns the streaming inserter of the table, after
+// validating the dataset and table IDs with the same rules as DatasetID and
+// TableID. This closes the gap for write paths where the destination is
+// determined at runtime.
+//
+// Example:
+//
+//	ins, err := client.SafeInserter(dataset, "events_"+region)
+//	if err != nil {
+//	    return err
+//	}
+//	err = ins.Put(ctx, rows)
+//
+// Returns an error wrapping one of the identifier errors (such as
+// ErrIdentifierInvalidChars) if the dataset or table ID is not valid.
+func (c *Client) SafeInserter(dataset, table string) (*bigquery.Inserter, error) {
+	t, err := c.safeTable(dataset, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inserter: %w", err)
+	}
+	return t.Inserter(), nil
+}
+
+// safeTable validates the dataset and table IDs and returns a handle to
+// the table in the project of the client.
+func (c *Client) safeTable(dataset, table string) (*bigquery.Table, error) {
+	if _, err := DatasetID(dataset).render("dataset"); err != nil {
+		return nil, err
+	}
+	if _, err := TableID(table).render("table"); err != nil {
+		return nil, err
+	}
+	return c.Dataset(dataset).Table(table), nil
+}
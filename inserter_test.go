@@ -0,0 +1,43 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSafeInserter(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataset string
+		table   string
+		err     error
+	}{
+		{"valid", "analytics", "events-2024", nil},
+		{"dataset with dash", "my-dataset", "events", ErrIdentifierInvalidChars},
+		{"table with path", "analytics", "other.events", ErrIdentifierInvalidChars},
+		{"table injection attempt", "analytics", "events`; DROP TABLE x", ErrIdentifierInvalidChars},
+		{"empty dataset", "", "events", ErrIdentifierEmpty},
+		{"empty table", "analytics", "", ErrIdentifierEmpty},
+	}
+
+	client := &Client{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ins, err := client.SafeInserter(tt.dataset, tt.table)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SafeInserter() error = %v, want %v", err, tt.err)
+			}
+			if (ins != nil) != (tt.err == nil) {
+				t.Errorf("SafeInserter() inserter = %v, want inserter only without error", ins)
+			}
+		})
+	}
+}
+
+func TestSafeInserterErrorMessage(t *testing.T) {
+	_, err := (&Client{}).SafeInserter("my-dataset", "events")
+	want := "failed to create inserter: identifier contains invalid characters: dataset must be a valid dataset ID"
+	if err == nil || err.Error() != want {
+		t.Errorf("SafeInserter() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,124 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// RowIterator wraps bigquery.RowIterator and keeps track of the rows that
+// were read. Errors returned by Next are wrapped in a RowError with the
+// position at which they occurred, so failures in long reads can be
+// diagnosed and resumed.
+type RowIterator struct {
+	*bigquery.RowIterator
+	// rowsRead is the number of rows returned by Next
+	rowsRead uint64
+	// pageToken is the token with which the buffered page was fetched
+	pageToken string
+	// cancel releases the deadline of Read once the rows are read (may be nil)
+	cancel context.CancelFunc
+}
+
+// Progress is the progress of reading the results of a query.
+type Progress struct {
+	// RowsRead is the number of rows that were read
+	RowsRead uint64
+	// TotalRows is the total number of result rows, or 0 when not yet known
+	TotalRows uint64
+}
+
+// RowError is returned by RowIterator.Next when reading a row failed. It
+// records the index of the row and the page token with which the read can
+// be resumed.
+//
+// When the row could not be loaded into the destination, the row is
+// skipped and Next can be called again to read the next row. When the page
+// that contains the row could not be fetched or converted, the iterator
+// has failed and returns the same error on every call.
+type RowError struct {
+	// Row is the zero based index of the row that could not be read
+	Row uint64
+	// PageToken is the token with which the page that contains the row is
+	// fetched, empty for the first page. Resuming with it reads the page
+	// again from its first row.
+	PageToken string
+	// JobID is the ID of the job whose results were read (may be empty)
+	JobID string
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RowError) Error() string {
+	return fmt.Sprintf("failed to read row %d of job %q (page token %q): %v", e.Row, e.JobID, e.PageToken, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// newRowIterator wraps the iterator, or returns nil for a nil iterator.
+func newRowIterator(it *bigquery.RowIterator) *RowIterator {
+	if it == nil {
+		return nil
+	}
+	return &RowIterator{RowIterator: it}
+}
+
+// Next loads the next row into dst, see bigquery.RowIterator.Next.
+// It returns iterator.Done when there are no more rows, and a *RowError
+// when the row could not be read.
+func (it *RowIterator) Next(dst interface{}) error {
+	// The next page is fetched with the current token when the buffer is empty
+	nextToken := it.PageInfo().Token
+	buffered := it.PageInfo().Remaining()
+	totalRows := it.TotalRows
+	err := it.RowIterator.Next(dst)
+	// A fetch replaces the token, or sets the total rows of the first page.
+	// Only a single page of one row can't be told apart from a failed fetch,
+	// but then there is nothing left to fetch.
+	fetched := buffered == 0 && (it.PageInfo().Token != nextToken || it.TotalRows != totalRows || it.PageInfo().Remaining() > 0)
+	if fetched {
+		it.pageToken = nextToken
+	}
+	if err == nil {
+		it.rowsRead++
+		return nil
+	}
+	// The rows are read or the page could not be fetched, release the
+	// deadline, but keep it when only the row failed to load
+	if err == iterator.Done {
+		it.Close()
+		return err
+	}
+	pageToken := it.pageToken
+	if buffered == 0 && !fetched {
+		it.Close()
+		pageToken = nextToken
+	}
+	rowErr := &RowError{Row: it.rowsRead, PageToken: pageToken, Err: err}
+	if job := it.SourceJob(); job != nil {
+		rowErr.JobID = job.ID()
+	}
+	return rowErr
+}
+
+// Close releases the deadline of WithQueryTimeout when the rows are not
+// read until iterator.Done or a failed page fetch. Close may be called more
+// than once.
+func (it *RowIterator) Close() {
+	if it.cancel != nil {
+		it.cancel()
+		it.cancel = nil
+	}
+}
+
+// Progress returns the number of rows that were read and the total number
+// of rows, when known.
+func (it *RowIterator) Progress() Progress {
+	return Progress{RowsRead: it.rowsRead, TotalRows: it.TotalRows}
+}
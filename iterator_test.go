@@ -0,0 +1,171 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+func TestRowIteratorProgress(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{{"name": "id", "type": "INTEGER"}},
+		rows:   [][]any{{"1"}, {"2"}, {"3"}},
+	}
+	client := newFakeClient(t, fake)
+
+	it, err := client.Query("SELECT id FROM t").ReadRows(context.Background())
+	if err != nil {
+		t.Fatalf("ReadRows() unexpected error: %v", err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	if got, want := it.Progress(), (Progress{RowsRead: 1, TotalRows: 3}); got != want {
+		t.Errorf("Progress() = %+v, want %+v", got, want)
+	}
+	for {
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %v", err)
+		}
+	}
+	if got, want := it.Progress(), (Progress{RowsRead: 3, TotalRows: 3}); got != want {
+		t.Errorf("Progress() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRowIteratorError(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{{"name": "id", "type": "INTEGER"}},
+		rows:   [][]any{{"1"}, {"not a number"}},
+	}
+	client := newFakeClient(t, fake)
+
+	it, err := client.Query("SELECT id FROM t").ReadRows(context.Background())
+	if err != nil {
+		t.Fatalf("ReadRows() unexpected error: %v", err)
+	}
+	var row []bigquery.Value
+	err = it.Next(&row)
+	for err == nil {
+		err = it.Next(&row)
+	}
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) {
+		t.Fatalf("Next() error = %v, want *RowError", err)
+	}
+	if rowErr.JobID != "fast-path-job" || rowErr.Err == nil {
+		t.Errorf("RowError = %+v, want job fast-path-job with underlying error", rowErr)
+	}
+	if rowErr.Row != it.Progress().RowsRead {
+		t.Errorf("RowError.Row = %d, want %d", rowErr.Row, it.Progress().RowsRead)
+	}
+}
+
+func TestRowErrorUnwrap(t *testing.T) {
+	err := &RowError{Row: 7, PageToken: "token", JobID: "job", Err: ErrJobFailed}
+	if !errors.Is(err, ErrJobFailed) {
+		t.Errorf("errors.Is(%v, ErrJobFailed) = false, want true", err)
+	}
+	want := `failed to read row 7 of job "job" (page token "token"): job failed`
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+// unluckyRow is a row that fails to load the value 13.
+type unluckyRow []bigquery.Value
+
+// Load implements bigquery.ValueLoader.
+func (r *unluckyRow) Load(values []bigquery.Value, _ bigquery.Schema) error {
+	if values[0] == int64(13) {
+		return errors.New("unlucky row")
+	}
+	*r = values
+	return nil
+}
+
+func TestRowIteratorRowErrorContinues(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:   []map[string]any{{"name": "id", "type": "INTEGER"}},
+		rows:     [][]any{{"1"}, {"2"}, {"3"}, {"13"}, {"5"}},
+		pageSize: 2,
+	}
+	client := newFakeClient(t, fake).Configure(WithQueryTimeout(time.Minute))
+
+	it, err := client.Query("SELECT id FROM t").ReadRows(context.Background())
+	if err != nil {
+		t.Fatalf("ReadRows() unexpected error: %v", err)
+	}
+	var ids []bigquery.Value
+	var rowErrs []*RowError
+	for {
+		var row unluckyRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrs = append(rowErrs, rowErr)
+			if len(rowErrs) > 1 {
+				t.Fatalf("Next() errors = %v, want only the unlucky row", rowErrs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %v", err)
+		}
+		ids = append(ids, row[0])
+	}
+	// The unlucky row is skipped, the deadline is kept for the last page
+	if want := []bigquery.Value{int64(1), int64(2), int64(3), int64(5)}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("rows = %v, want %v", ids, want)
+	}
+	if len(rowErrs) != 1 {
+		t.Fatalf("Next() errors = %v, want 1", rowErrs)
+	}
+	// The row is on the second page, which starts at row 2
+	if rowErrs[0].Row != 3 || rowErrs[0].PageToken != "2" {
+		t.Errorf("RowError = %+v, want row 3 with page token 2", rowErrs[0])
+	}
+}
+
+func TestRowIteratorFetchError(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:    []map[string]any{{"name": "id", "type": "INTEGER"}},
+		rows:      [][]any{{"1"}, {"2"}, {"3"}},
+		pageSize:  2,
+		failPages: true,
+	}
+	client := newFakeClient(t, fake).Configure(WithQueryTimeout(time.Minute))
+
+	it, err := client.Query("SELECT id FROM t").ReadRows(context.Background())
+	if err != nil {
+		t.Fatalf("ReadRows() unexpected error: %v", err)
+	}
+	var row []bigquery.Value
+	err = it.Next(&row)
+	for err == nil {
+		err = it.Next(&row)
+	}
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) {
+		t.Fatalf("Next() error = %v, want *RowError", err)
+	}
+	if rowErr.Row != 2 || rowErr.PageToken != "2" {
+		t.Errorf("RowError = %+v, want row 2 with page token 2", rowErr)
+	}
+	if it.cancel != nil {
+		t.Error("deadline not released after a failed fetch")
+	}
+}
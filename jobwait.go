@@ -0,0 +1,142 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// WithCancelOnContextDone cancels the job of every query that the client
+// runs with Run (and so with Exec and RunAndWait) when the context of Run
+// is done before the job is complete, so abandoned jobs stop running and
+// billing.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithCancelOnContextDone())
+func WithCancelOnContextDone() Option {
+	return func(c *Client) {
+		c.cancelOnDone = true
+	}
+}
+
+// WaitJob waits for the job to complete, for at most the timeout (0 is no
+// timeout), and returns its final status. When the context is done or the
+// timeout expires before the job is complete, the job is cancelled.
+//
+// Example:
+//
+//	job, err := q.Run(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	status, err := saferbq.WaitJob(ctx, job, 10*time.Minute)
+//
+// Returns an error wrapping the context error if the job was cancelled
+// (context.DeadlineExceeded when the timeout expired), or an error wrapping
+// ErrJobFailed if the job failed.
+func WaitJob(ctx context.Context, job *bigquery.Job, timeout time.Duration) (*bigquery.JobStatus, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	status, err := waitJob(ctx, job)
+	if err != nil && ctx.Err() != nil {
+		if cancelErr := cancelJob(ctx, job); cancelErr != nil {
+			return nil, fmt.Errorf("failed to cancel job %s: %w (after %w)", job.ID(), cancelErr, ctx.Err())
+		}
+		return nil, fmt.Errorf("job %s cancelled: %w", job.ID(), ctx.Err())
+	}
+	return status, err
+}
+
+// cancelJob requests the cancellation of the job, also when the context is
+// done already.
+func cancelJob(ctx context.Context, job *bigquery.Job) error {
+	return job.Cancel(context.WithoutCancel(ctx))
+}
+
+// watchesJobs reports whether the jobs of Run in the lane are waited for in
+// the background: to hold their slot while a limit applies, to record their
+// bytes billed or to cancel them when their context is done.
+func (c *Client) watchesJobs(lane Lane) bool {
+	return c != nil && (c.limited(lane) || c.metrics != nil || c.cancelOnDone)
+}
+
+const (
+	// watchRetryBackoff is the delay before the first retry of a failed
+	// poll of watchJob, it doubles on every retry
+	watchRetryBackoff = time.Second
+	// maxWatchRetryBackoff is the maximum delay between the retries
+	maxWatchRetryBackoff = time.Minute
+)
+
+// watchJob waits until a poll reports that the job of Run is done and then
+// calls release. It polls its own handle of the job, so the *bigquery.Job
+// of the caller is never touched. Failed polls are retried, as the job may
+// still be running and keeps its slot, until the context of Run is done.
+// It stops when the client is closed.
+func (c *Client) watchJob(ctx context.Context, job *bigquery.Job, release func()) {
+	defer release()
+	waitCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	defer stop()
+	defer context.AfterFunc(c.background(), stop)()
+	var handle *bigquery.Job
+	for backoff := watchRetryBackoff; ; backoff = min(2*backoff, maxWatchRetryBackoff) {
+		var err error
+		if handle == nil {
+			handle, err = c.jobHandle(waitCtx, job)
+			if err == nil && c.cancelOnDone {
+				// Cancel the job when the context is done before the job
+				defer context.AfterFunc(ctx, func() { cancelJob(ctx, handle) })()
+			}
+		}
+		if handle != nil {
+			var status *bigquery.JobStatus
+			status, err = handle.Wait(waitCtx)
+			if err != nil {
+				// A failed query job reports its error when waiting
+				status, err = handle.Status(waitCtx)
+			}
+			if err == nil && status.Done() {
+				if status.Err() == nil {
+					c.metrics.observeStatus(status)
+				}
+				return
+			}
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-waitCtx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// observeCompletedJob records the bytes billed of the completed job of Read
+// in the background, when the client has metrics.
+func (c *Client) observeCompletedJob(ctx context.Context, job *bigquery.Job) {
+	if c == nil || c.metrics == nil || job == nil {
+		return
+	}
+	go func() {
+		handle, err := c.jobHandle(ctx, job)
+		if err == nil {
+			c.metrics.observeStatus(handle.LastStatus())
+		}
+	}()
+}
+
+// jobHandle fetches a new handle of the job, that can be polled without
+// touching the handle of the caller.
+func (c *Client) jobHandle(ctx context.Context, job *bigquery.Job) (*bigquery.Job, error) {
+	return c.Client.JobFromProject(ctx, job.ProjectID(), job.ID(), job.Location())
+}
@@ -0,0 +1,153 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestWaitJob(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	job, err := q.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	status, err := WaitJob(context.Background(), job, time.Minute)
+	if err != nil || status.State != bigquery.Done {
+		t.Errorf("WaitJob() = %v, %v, want a done status", status, err)
+	}
+	if cancelled := fake.cancelledJobs(); len(cancelled) != 0 {
+		t.Errorf("cancelled jobs = %v, want none", cancelled)
+	}
+}
+
+func TestWaitJobTimeout(t *testing.T) {
+	fake := &fakeBigQuery{running: true}
+	client := newFakeClient(t, fake)
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	job, err := q.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	_, err = WaitJob(context.Background(), job, 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitJob() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if cancelled := fake.cancelledJobs(); !slices.Equal(cancelled, []string{job.ID()}) {
+		t.Errorf("cancelled jobs = %v, want %v", cancelled, []string{job.ID()})
+	}
+}
+
+func TestCancelOnContextDone(t *testing.T) {
+	fake := &fakeBigQuery{running: true}
+	client := newFakeClient(t, fake)
+	client.Configure(WithCancelOnContextDone())
+	ctx, cancel := context.WithCancel(context.Background())
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	job, err := q.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(fake.cancelledJobs()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cancelled := fake.cancelledJobs(); !slices.Equal(cancelled, []string{job.ID()}) {
+		t.Errorf("cancelled jobs = %v, want %v", cancelled, []string{job.ID()})
+	}
+}
+
+func TestWatchJobStopsOnClose(t *testing.T) {
+	fake := &fakeBigQuery{running: true}
+	client := newFakeClient(t, fake)
+	client.Configure(WithLaneLimits(1, 0))
+	ctx := context.Background()
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if !client.watchesJobs(LaneInteractive) || client.watchesJobs(LaneBackground) {
+		t.Error("watchesJobs() should only be true for the limited lane")
+	}
+
+	// The running job holds the only slot until the client is closed
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.acquire(timeoutCtx, LaneInteractive); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+	client.Close()
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	release, err := client.acquire(waitCtx, LaneInteractive)
+	if err != nil {
+		t.Fatalf("acquire() after Close unexpected error: %v", err)
+	}
+	release()
+}
+
+func TestWatchJobKeepsSlotOnPollErrors(t *testing.T) {
+	fake := &fakeBigQuery{running: true, failJobGets: 1}
+	client := newFakeClient(t, fake)
+	client.Configure(WithLaneLimits(1, 0))
+	ctx := context.Background()
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	// The failed poll is retried, the running job keeps the only slot
+	timeoutCtx, cancel := context.WithTimeout(ctx, watchRetryBackoff+500*time.Millisecond)
+	defer cancel()
+	if _, err := client.acquire(timeoutCtx, LaneInteractive); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+	fake.mu.Lock()
+	failed := fake.failJobGets == 0
+	fake.running = false
+	fake.mu.Unlock()
+	if !failed {
+		t.Fatal("jobs.get did not fail")
+	}
+	// The slot is freed once a poll reports that the job is done
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	release, err := client.acquire(waitCtx, LaneInteractive)
+	if err != nil {
+		t.Fatalf("acquire() after the job is done unexpected error: %v", err)
+	}
+	release()
+}
+
+func TestWatchJobStopsOnContextDone(t *testing.T) {
+	fake := &fakeBigQuery{running: true, failJobGets: 1000}
+	client := newFakeClient(t, fake)
+	client.Configure(WithLaneLimits(1, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	// Polls keep failing, so the slot is freed when the context is done
+	cancel()
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelWait()
+	release, err := client.acquire(waitCtx, LaneInteractive)
+	if err != nil {
+		t.Fatalf("acquire() after cancel unexpected error: %v", err)
+	}
+	release()
+}
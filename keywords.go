@@ -0,0 +1,70 @@
+package saferbq
+
+import (
+	"strings"
+)
+
+// reservedKeywords are the reserved keywords of GoogleSQL. Reserved keywords
+// can't be used as unquoted identifiers, so changing their case never changes
+// the meaning of a query. See:
+// https://cloud.google.com/bigquery/docs/reference/standard-sql/lexical#reserved_keywords
+var reservedKeywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "ARRAY": true, "AS": true,
+	"ASC": true, "ASSERT_ROWS_MODIFIED": true, "AT": true, "BETWEEN": true,
+	"BY": true, "CASE": true, "CAST": true, "COLLATE": true, "CONTAINS": true,
+	"CREATE": true, "CROSS": true, "CUBE": true, "CURRENT": true,
+	"DEFAULT": true, "DEFINE": true, "DESC": true, "DISTINCT": true,
+	"ELSE": true, "END": true, "ENUM": true, "ESCAPE": true, "EXCEPT": true,
+	"EXCLUDE": true, "EXISTS": true, "EXTRACT": true, "FALSE": true,
+	"FETCH": true, "FOLLOWING": true, "FOR": true, "FROM": true, "FULL": true,
+	"GROUP": true, "GROUPING": true, "GROUPS": true, "HASH": true,
+	"HAVING": true, "IF": true, "IGNORE": true, "IN": true, "INNER": true,
+	"INTERSECT": true, "INTERVAL": true, "INTO": true, "IS": true,
+	"JOIN": true, "LATERAL": true, "LEFT": true, "LIKE": true, "LIMIT": true,
+	"LOOKUP": true, "MERGE": true, "NATURAL": true, "NEW": true, "NO": true,
+	"NOT": true, "NULL": true, "NULLS": true, "OF": true, "ON": true,
+	"OR": true, "ORDER": true, "OUTER": true, "OVER": true,
+	"PARTITION": true, "PRECEDING": true, "PROTO": true, "QUALIFY": true,
+	"RANGE": true, "RECURSIVE": true, "RESPECT": true, "RIGHT": true,
+	"ROLLUP": true, "ROWS": true, "SELECT": true, "SET": true, "SOME": true,
+	"STRUCT": true, "TABLESAMPLE": true, "THEN": true, "TO": true,
+	"TREAT": true, "TRUE": true, "UNBOUNDED": true, "UNION": true,
+	"UNNEST": true, "USING": true, "WHEN": true, "WHERE": true,
+	"WINDOW": true, "WITH": true, "WITHIN": true,
+}
+
+// WithKeywordNormalization uppercases all reserved GoogleSQL keywords in the
+// translated SQL, so the same logical query always produces the same SQL
+// text, which helps query-history search and cache keys. Identifiers,
+// literals and comments are never changed.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithKeywordNormalization())
+//
+//	q := client.Query("select * from $table where id = 1")
+//	// Results: SELECT * FROM `mytable` WHERE id = 1
+func WithKeywordNormalization() Option {
+	return func(c *Client) {
+		c.normalizeKeywords = true
+	}
+}
+
+// normalizeKeywords uppercases the reserved keywords in the SQL. Words that
+// directly follow a dot are part of a path expression and are not changed.
+func normalizeKeywords(sql string) string {
+	var result strings.Builder
+	result.Grow(len(sql))
+	previous := token{}
+	for _, t := range scan(sql) {
+		text := t.text
+		if t.kind == tokenWord && previous.text != "." {
+			if upper := strings.ToUpper(text); reservedKeywords[upper] {
+				text = upper
+			}
+		}
+		result.WriteString(text)
+		previous = t
+	}
+	return result.String()
+}
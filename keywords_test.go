@@ -0,0 +1,59 @@
+package saferbq
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestNormalizeKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		sqlIn  string
+		sqlOut string
+	}{
+		{
+			name:   "reserved keywords",
+			sqlIn:  "select id from `mytable` where status = @status order by id desc",
+			sqlOut: "SELECT id FROM `mytable` WHERE status = @status ORDER BY id DESC",
+		},
+		{
+			name:   "identifiers, literals and comments unchanged",
+			sqlIn:  "select `select`, 'from' as label -- where\nfrom `t` /* and */ where x = \"or\"",
+			sqlOut: "SELECT `select`, 'from' AS label -- where\nFROM `t` /* and */ WHERE x = \"or\"",
+		},
+		{
+			name:   "non-reserved keywords unchanged",
+			sqlIn:  "insert into `t` (date, name) values (1, 'a')",
+			sqlOut: "insert INTO `t` (date, name) values (1, 'a')",
+		},
+		{
+			name:   "path expressions unchanged",
+			sqlIn:  "select t.from, t.select from t",
+			sqlOut: "SELECT t.from, t.select FROM t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeKeywords(tt.sqlIn); got != tt.sqlOut {
+				t.Errorf("normalizeKeywords() = %q, want %q", got, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestWithKeywordNormalization(t *testing.T) {
+	client := (&Client{}).Configure(WithKeywordNormalization())
+	q := &Query{client: client}
+	q.QueryConfig.Q = "select * from $table where id = 1"
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "select"}}
+
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	expectedSQL := "SELECT * FROM `select` WHERE id = 1"
+	if q.QueryConfig.Q != expectedSQL {
+		t.Errorf("translate() SQL = %q, want %q", q.QueryConfig.Q, expectedSQL)
+	}
+}
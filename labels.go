@@ -0,0 +1,157 @@
+package saferbq
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// templateLabel is the job label with the hash of the SQL template
+	templateLabel = "saferbq_template"
+	// callerLabel is the job label with the package that ran the query
+	callerLabel = "saferbq_caller"
+	// maxLabelLength is the maximum length of a BigQuery label value
+	maxLabelLength = 63
+)
+
+// packagePath is the import path of this package, skipped when looking for the caller
+var packagePath = reflect.TypeOf(Client{}).PkgPath()
+
+// WithTemplateLabels labels every submitted job with a stable hash of the
+// SQL template (before translation) and the package that ran the query, so
+// INFORMATION_SCHEMA.JOBS can be grouped by logical query instead of by the
+// fully expanded SQL. Labels that are set on the query are not overwritten.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithTemplateLabels())
+//
+//	// SELECT labels FROM `region-us`.INFORMATION_SCHEMA.JOBS shows
+//	// saferbq_template: 3f2a...  saferbq_caller: example_com_app_reports
+func WithTemplateLabels() Option {
+	return func(c *Client) {
+		c.templateLabels = true
+	}
+}
+
+// applyLabels sets the template and caller labels when the client has
+// template labels enabled.
+func (q *Query) applyLabels() {
+	if q.client == nil || !q.client.templateLabels {
+		return
+	}
+	if q.Labels == nil {
+		q.Labels = map[string]string{}
+	}
+	if _, ok := q.Labels[templateLabel]; !ok {
+		q.Labels[templateLabel] = templateHash(q.originalSQL)
+	}
+	if _, ok := q.Labels[callerLabel]; !ok {
+		q.Labels[callerLabel] = labelValue(callerPackage())
+	}
+}
+
+// templateHash returns a stable label value for the SQL template.
+func templateHash(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:16])
+}
+
+// callerPackage returns the import path of the first package on the call
+// stack that is not this package.
+func callerPackage() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if pkg := functionPackage(frame.Function); pkg != "" && pkg != packagePath {
+			return pkg
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// functionPackage returns the package path of a fully qualified function
+// name, like "github.com/user/repo/pkg.(*Type).Method".
+func functionPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}
+
+// labelValue converts the string into a valid label value: lowercase
+// letters, digits, underscores and dashes, at most 63 characters.
+func labelValue(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(underscore)
+		}
+		if b.Len() == maxLabelLength {
+			break
+		}
+	}
+	return b.String()
+}
+
+// ValidateLabel checks the label against the BigQuery label rules: keys
+// have 1 to 63 characters and start with a lowercase letter, values have
+// at most 63 characters, and both only contain lowercase letters (including
+// international characters), digits, underscores and dashes.
+//
+// Example:
+//
+//	if err := saferbq.ValidateLabel("tenant", tenant); err != nil {
+//	    return err
+//	}
+//
+// Returns an error wrapping ErrInvalidLabel if the label is not valid. The
+// error names the key or the value, but doesn't contain it, as labels often
+// hold user input.
+func ValidateLabel(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key is empty", ErrInvalidLabel)
+	}
+	if first, _ := utf8.DecodeRuneInString(key); !unicode.IsLower(first) {
+		return redactValue(ErrInvalidLabel, "label key")
+	}
+	for i, part := range []string{key, value} {
+		name := "label key"
+		if i == 1 {
+			name = "label value"
+		}
+		if utf8.RuneCountInString(part) > maxLabelLength {
+			return redactValue(ErrInvalidLabel, name)
+		}
+		for _, r := range part {
+			if !unicode.IsLower(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+				return redactValue(ErrInvalidLabel, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateLabels checks all labels with ValidateLabel, in the order of
+// their keys.
+func validateLabels(labels map[string]string) error {
+	for _, key := range sortedKeys(labels) {
+		if err := ValidateLabel(key, labels[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithTemplateLabels(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake).Configure(WithTemplateLabels())
+	ctx := context.Background()
+
+	var labels []map[string]any
+	for _, table := range []string{"orders", "users"} {
+		q := client.Query("SELECT * FROM $table")
+		q.SetParams(map[string]any{"$table": table})
+		job, err := q.Run(ctx)
+		if err != nil {
+			t.Fatalf("Run() unexpected error: %v", err)
+		}
+		labels = append(labels, fake.jobs[job.ID()]["labels"].(map[string]any))
+	}
+	hash := templateHash("SELECT * FROM $table")
+	for _, l := range labels {
+		if l[templateLabel] != hash {
+			t.Errorf("label %s = %v, want %s", templateLabel, l[templateLabel], hash)
+		}
+		if l[callerLabel] != "testing" {
+			t.Errorf("label %s = %v, want testing", callerLabel, l[callerLabel])
+		}
+	}
+
+	// Labels that are set on the query are kept
+	q := client.Query("SELECT 1")
+	q.Labels = map[string]string{templateLabel: "report"}
+	job, err := q.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if got := fake.jobs[job.ID()]["labels"].(map[string]any)[templateLabel]; got != "report" {
+		t.Errorf("label %s = %v, want report", templateLabel, got)
+	}
+}
+
+func TestTemplateHash(t *testing.T) {
+	hash := templateHash("SELECT * FROM $table")
+	if len(hash) != 32 || hash != templateHash("SELECT * FROM $table") {
+		t.Errorf("templateHash() = %q, want stable 32 character hash", hash)
+	}
+	if hash == templateHash("SELECT * FROM $other") {
+		t.Error("templateHash() is equal for different templates")
+	}
+}
+
+func TestFunctionPackage(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/user/repo/pkg.(*Type).Method", "github.com/user/repo/pkg"},
+		{"github.com/user/repo.Func.func1", "github.com/user/repo"},
+		{"main.main", "main"},
+		{"testing.tRunner", "testing"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := functionPackage(tt.function); got != tt.want {
+			t.Errorf("functionPackage(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	if got, want := labelValue("github.com/User/repo"), "github_com_user_repo"; got != want {
+		t.Errorf("labelValue() = %q, want %q", got, want)
+	}
+	if got := labelValue(strings.Repeat("a", 100)); len(got) != maxLabelLength {
+		t.Errorf("labelValue() length = %d, want %d", len(got), maxLabelLength)
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		err   error
+	}{
+		{"team", "data", nil},
+		{"cost-center", "", nil},
+		{"équipe", "données_2024", nil},
+		{strings.Repeat("k", 63), strings.Repeat("v", 63), nil},
+		{"", "data", ErrInvalidLabel},
+		{"Team", "data", ErrInvalidLabel},
+		{"1team", "data", ErrInvalidLabel},
+		{"_team", "data", ErrInvalidLabel},
+		{"team", "Data", ErrInvalidLabel},
+		{"team", "data team", ErrInvalidLabel},
+		{"team", "data'", ErrInvalidLabel},
+		{strings.Repeat("k", 64), "data", ErrInvalidLabel},
+		{"team", strings.Repeat("v", 64), ErrInvalidLabel},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateLabel(tt.key, tt.value); !errors.Is(err, tt.err) {
+			t.Errorf("ValidateLabel(%q, %q) error = %v, want %v", tt.key, tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestJobConfigLabelsValidation(t *testing.T) {
+	client := &Client{}
+	labels := map[string]string{"Pipeline": "ingest"}
+	if _, err := client.SafeLoad("gs://my-bucket/file.csv", "staging", "events", LoadConfig{Labels: labels}); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("SafeLoad() error = %v, want %v", err, ErrInvalidLabel)
+	}
+	if _, err := client.SafeExtract("staging", "events", "gs://my-bucket/file.csv", ExtractConfig{Labels: labels}); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("SafeExtract() error = %v, want %v", err, ErrInvalidLabel)
+	}
+	ref := TableRef{ProjectID: "my-project", DatasetID: "staging", TableID: "events"}
+	if _, err := client.SafeCopy(ref, ref, CopyConfig{Labels: labels}); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("SafeCopy() error = %v, want %v", err, ErrInvalidLabel)
+	}
+}
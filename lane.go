@@ -0,0 +1,130 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+)
+
+// Lane is an execution lane of a Client. Each lane has its own concurrency
+// limit and queue, so background work cannot starve interactive queries.
+type Lane int
+
+const (
+	// LaneInteractive is the lane for user-facing queries (default).
+	LaneInteractive Lane = iota
+	// LaneBackground is the lane for background work like backfills.
+	LaneBackground
+
+	// laneCount is the number of available lanes
+	laneCount = 2
+)
+
+// String returns the name of the lane.
+func (l Lane) String() string {
+	switch l {
+	case LaneInteractive:
+		return "interactive"
+	case LaneBackground:
+		return "background"
+	default:
+		return fmt.Sprintf("lane(%d)", int(l))
+	}
+}
+
+// WithLaneLimits limits the number of concurrently executing queries per
+// lane. Queries that exceed the limit wait in a queue until a slot is free
+// or their context is done. A limit of zero (or less) means unlimited.
+//
+// A query submitted with Run occupies its slot until the job is done, a
+// query submitted with Read until the results are available.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithLaneLimits(20, 2))
+//
+//	q := client.Query("INSERT INTO $table SELECT * FROM $staging")
+//	q.Lane = saferbq.LaneBackground
+func WithLaneLimits(interactive, background int) Option {
+	return func(c *Client) {
+		c.lanes[LaneInteractive] = newSemaphore(interactive)
+		c.lanes[LaneBackground] = newSemaphore(background)
+	}
+}
+
+// WithMaxConcurrentQueries limits the number of concurrently executing
+// queries of the client over all lanes, to stay inside the concurrent
+// query limits of BigQuery. Queries that exceed the limit wait in a queue
+// until a slot is free or their context is done, like with the limits of
+// WithLaneLimits, which apply as well. A limit of zero (or less) means
+// unlimited.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithMaxConcurrentQueries(50))
+func WithMaxConcurrentQueries(n int) Option {
+	return func(c *Client) {
+		c.maxConcurrent = newSemaphore(n)
+	}
+}
+
+// semaphore limits concurrency, a nil semaphore is unlimited.
+type semaphore chan struct{}
+
+// newSemaphore creates a semaphore with n slots, or nil when n <= 0.
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+// acquire waits for a free slot or until the context is done.
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot acquired with acquire.
+func (s semaphore) release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
+
+// limited reports whether a limit applies to the queries in the lane, so
+// their slot has to be held until their job is done.
+func (c *Client) limited(lane Lane) bool {
+	return c != nil && (c.lanes[lane] != nil || c.maxConcurrent != nil || c.scheduler != nil)
+}
+
+// acquire waits for a free slot in the lane (and in the limit of
+// WithMaxConcurrentQueries) and returns the function that frees the slots
+// again. The client may be nil, in which case no limit applies.
+func (c *Client) acquire(ctx context.Context, lane Lane) (func(), error) {
+	if c == nil {
+		return func() {}, nil
+	}
+	if lane < 0 || lane >= laneCount {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLane, lane)
+	}
+	s := c.lanes[lane]
+	if err := s.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("failed to acquire %s lane: %w", lane, err)
+	}
+	if err := c.maxConcurrent.acquire(ctx); err != nil {
+		s.release()
+		return nil, fmt.Errorf("failed to acquire %s lane: %w", lane, err)
+	}
+	return func() {
+		c.maxConcurrent.release()
+		s.release()
+	}, nil
+}
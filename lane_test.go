@@ -0,0 +1,133 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/option"
+)
+
+func TestLaneString(t *testing.T) {
+	tests := []struct {
+		lane Lane
+		name string
+	}{
+		{LaneInteractive, "interactive"},
+		{LaneBackground, "background"},
+		{Lane(5), "lane(5)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.lane.String(); got != tt.name {
+			t.Errorf("Lane.String() = %q, want %q", got, tt.name)
+		}
+	}
+}
+
+func TestClientAcquire(t *testing.T) {
+	ctx := context.Background()
+	client := (&Client{}).Configure(WithLaneLimits(1, 0))
+
+	release, err := client.acquire(ctx, LaneInteractive)
+	if err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+
+	// The interactive lane is full, so the next caller has to wait
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.acquire(timeoutCtx, LaneInteractive)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The background lane is unlimited and not affected
+	for range 3 {
+		if _, err := client.acquire(ctx, LaneBackground); err != nil {
+			t.Errorf("acquire() unexpected error: %v", err)
+		}
+	}
+
+	// After releasing, the interactive lane is available again
+	release()
+	release, err = client.acquire(ctx, LaneInteractive)
+	if err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	release()
+
+	_, err = client.acquire(ctx, Lane(5))
+	if !errors.Is(err, ErrInvalidLane) {
+		t.Errorf("acquire() error = %v, want ErrInvalidLane", err)
+	}
+}
+
+func TestWithMaxConcurrentQueries(t *testing.T) {
+	ctx := context.Background()
+	client := (&Client{}).Configure(WithMaxConcurrentQueries(2))
+
+	first, err := client.acquire(ctx, LaneInteractive)
+	if err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	if _, err := client.acquire(ctx, LaneBackground); err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+
+	// Both lanes share the limit, so the next caller has to wait
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.acquire(timeoutCtx, LaneInteractive); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// A released slot is available to the queued caller
+	done := make(chan error)
+	go func() {
+		_, err := client.acquire(ctx, LaneBackground)
+		done <- err
+	}()
+	first()
+	if err := <-done; err != nil {
+		t.Errorf("acquire() unexpected error: %v", err)
+	}
+}
+
+func TestClientAcquireNil(t *testing.T) {
+	var client *Client
+	release, err := client.acquire(context.Background(), LaneBackground)
+	if err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	release()
+}
+
+func TestQueryReadLaneCancelled(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewClient(ctx, "test-project", option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	defer client.Close()
+	client.Configure(WithLaneLimits(0, 1))
+
+	release, err := client.acquire(ctx, LaneBackground)
+	if err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	defer release()
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	q := client.Query("SELECT * FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "test_table"}}
+	q.Lane = LaneBackground
+
+	_, err = q.Read(cancelledCtx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Read() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,161 @@
+package saferbq
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+)
+
+// IntLiteral is a $identifier value that is injected as an integer literal,
+// for clauses that don't accept query parameters in all contexts, such as
+// LIMIT and OFFSET. Translation fails with ErrInvalidInteger when the value
+// is negative.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM events ORDER BY id LIMIT $limit OFFSET $offset")
+//	q.SetParams(map[string]any{
+//		"$limit":  saferbq.IntLiteral(pageSize),
+//		"$offset": saferbq.IntLiteral(page * pageSize),
+//	})
+type IntLiteral int64
+
+// render checks that the value is not negative and formats it.
+func (n IntLiteral) render(name string) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("%w: %s must not be negative", ErrInvalidInteger, name)
+	}
+	return strconv.FormatInt(int64(n), 10), nil
+}
+
+// EscapeStringLiteral returns the value as a single-quoted BigQuery string
+// literal, with backslashes, quotes and control characters escaped, for
+// the few places that don't accept query parameters, such as the OPTIONS
+// of DDL statements. Use query parameters for values wherever BigQuery
+// accepts them.
+//
+// Example:
+//
+//	literal := saferbq.EscapeStringLiteral("it's")
+//	// literal = 'it\'s'
+func EscapeStringLiteral(value string) string {
+	var result strings.Builder
+	result.Grow(len(value) + 2)
+	result.WriteByte('\'')
+	for _, r := range value {
+		switch r {
+		case '\\':
+			result.WriteString(`\\`)
+		case '\'':
+			result.WriteString(`\'`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\r':
+			result.WriteString(`\r`)
+		case '\t':
+			result.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&result, `\x%02x`, r)
+			} else {
+				result.WriteRune(r)
+			}
+		}
+	}
+	result.WriteByte('\'')
+	return result.String()
+}
+
+// renderLiteral renders the value as a SQL literal, for statements that
+// don't accept query parameters, such as DDL. Strings are escaped with
+// EscapeStringLiteral, and slices are rendered as array literals.
+//
+// Returns an error wrapping ErrInvalidLiteral if the type of the value is
+// not supported or a float is not finite.
+func renderLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return EscapeStringLiteral(v), nil
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(v)), nil
+	case time.Time:
+		return "TIMESTAMP " + EscapeStringLiteral(v.UTC().Format(systemTimeLayout)), nil
+	case civil.Date:
+		return "DATE " + EscapeStringLiteral(v.String()), nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("%w: %v is not finite", ErrInvalidLiteral, f)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]string, rv.Len())
+		for i := range elements {
+			element, err := renderLiteral(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			elements[i] = element
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	default:
+		return "", fmt.Errorf("%w: unsupported type %T", ErrInvalidLiteral, value)
+	}
+}
+
+// inlineFragment builds a Fragment from the SQL template, with the values
+// of the @named parameters in params rendered as literals and the
+// $identifier values validated and quoted as in NewFragment.
+//
+// Returns an error if a @named parameter is missing, unused or can't be
+// rendered, or if NewFragment fails.
+func inlineFragment(sql string, params map[string]any) (Fragment, error) {
+	identifiers := map[string]any{}
+	literals := map[string]any{}
+	for name, value := range params {
+		if strings.HasPrefix(name, string(atSign)) {
+			literals[name] = value
+		} else {
+			identifiers[name] = value
+		}
+	}
+	var result strings.Builder
+	result.Grow(len(sql))
+	used := map[string]bool{}
+	for _, tok := range scan(sql) {
+		if tok.kind != tokenNamedParam {
+			result.WriteString(tok.text)
+			continue
+		}
+		value, ok := literals[tok.text]
+		if !ok {
+			return Fragment{}, fmt.Errorf("%w: %s", ErrParameterNotProvided, tok.text)
+		}
+		literal, err := renderLiteral(value)
+		if err != nil {
+			return Fragment{}, fmt.Errorf("%s: %w", tok.text, err)
+		}
+		used[tok.text] = true
+		result.WriteString(literal)
+	}
+	for _, name := range sortedKeys(literals) {
+		if !used[name] {
+			return Fragment{}, fmt.Errorf("%w: %s", ErrParameterNotFound, name)
+		}
+	}
+	return NewFragment(result.String(), identifiers)
+}
@@ -0,0 +1,132 @@
+package saferbq
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+func TestIntLiteral(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  IntLiteral
+		offset IntLiteral
+		sqlOut string
+		err    error
+	}{
+		{"limit and offset", 100, 200, "SELECT * FROM events LIMIT 100 OFFSET 200", nil},
+		{"zero", 0, 0, "SELECT * FROM events LIMIT 0 OFFSET 0", nil},
+		{"negative limit", -1, 0, "", ErrInvalidInteger},
+		{"negative offset", 10, -10, "", ErrInvalidInteger},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlOut, _, err := translate("SELECT * FROM events LIMIT $limit OFFSET $offset", []bigquery.QueryParameter{
+				{Name: "$limit", Value: tt.limit},
+				{Name: "$offset", Value: tt.offset},
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("translate() error = %v, want %v", err, tt.err)
+			}
+			if sqlOut != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", sqlOut, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestIntLiteralErrorMessage(t *testing.T) {
+	_, _, err := translate("SELECT * FROM events LIMIT $limit", []bigquery.QueryParameter{{Name: "$limit", Value: IntLiteral(-5)}})
+	want := "invalid integer literal: $limit must not be negative"
+	if err == nil || err.Error() != want {
+		t.Errorf("translate() error = %v, want %q", err, want)
+	}
+}
+
+func TestEscapeStringLiteral(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"plain", `'plain'`},
+		{"it's", `'it\'s'`},
+		{`back\slash`, `'back\\slash'`},
+		{"line\nbreak\ttab\r", `'line\nbreak\ttab\r'`},
+		{"nul\x00bell\x07", `'nul\x00bell\x07'`},
+		{"ünïcode", `'ünïcode'`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeStringLiteral(tt.value); got != tt.expected {
+			t.Errorf("EscapeStringLiteral(%q) = %s, want %s", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestRenderLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+		err      error
+	}{
+		{"nil", nil, "NULL", nil},
+		{"string", "o'reilly", `'o\'reilly'`, nil},
+		{"bool", true, "TRUE", nil},
+		{"int", -42, "-42", nil},
+		{"uint", uint8(7), "7", nil},
+		{"float", 1.5, "1.5", nil},
+		{"nan", math.NaN(), "", ErrInvalidLiteral},
+		{"timestamp", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "TIMESTAMP '2024-01-02 03:04:05+00:00'", nil},
+		{"date", civil.Date{Year: 2024, Month: 1, Day: 2}, "DATE '2024-01-02'", nil},
+		{"array", []string{"a", "b'"}, `['a', 'b\'']`, nil},
+		{"unsupported", struct{}{}, "", ErrInvalidLiteral},
+		{"unsupported element", []any{1, map[string]int{}}, "", ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderLiteral(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("renderLiteral() error = %v, want %v", err, tt.err)
+			}
+			if got != tt.expected {
+				t.Errorf("renderLiteral() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInlineFragment(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		params   map[string]any
+		expected string
+		err      error
+	}{
+		{"literals and identifiers", "$column = @region AND '@kept' != @region",
+			map[string]any{"$column": "region", "@region": "emea' OR TRUE --"},
+			"`region` = 'emea\\' OR TRUE --' AND '@kept' != 'emea\\' OR TRUE --'", nil},
+		{"missing parameter", "region = @region", nil, "", ErrParameterNotProvided},
+		{"unused parameter", "TRUE", map[string]any{"@region": "emea"}, "", ErrParameterNotFound},
+		{"positional parameter", "region = ?", nil, "", ErrInvalidParameterName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inlineFragment(tt.sql, tt.params)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("inlineFragment() error = %v, want %v", err, tt.err)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("inlineFragment() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package saferbq
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// LoadConfig configures a load job of SafeLoad.
+type LoadConfig struct {
+	// SourceFormat is the format of the files (the default is CSV)
+	SourceFormat bigquery.DataFormat
+	// Schema is the schema of the files (optional with AutoDetect)
+	Schema bigquery.Schema
+	// AutoDetect infers the schema from the files
+	AutoDetect bool
+	// SkipLeadingRows is the number of header rows of CSV files
+	SkipLeadingRows int64
+	// WriteDisposition specifies what happens when the table has data
+	WriteDisposition bigquery.TableWriteDisposition
+	// CreateDisposition specifies whether the table is created
+	CreateDisposition bigquery.TableCreateDisposition
+	// Labels are the labels of the load job
+	Labels map[string]string
+}
+
+// SafeLoad returns a loader that loads the files at the Cloud Storage URI
+// into the table, after validating the dataset and table IDs (with the
+// rules of DatasetID and TableID) and the URI, that must have the form
+// gs://bucket/object and may contain one * wildcard in the object name.
+//
+// Example:
+//
+//	loader, err := client.SafeLoad("gs://my-bucket/exports/"+day+"/*.csv", "staging", "events_"+day,
+//	    saferbq.LoadConfig{SourceFormat: bigquery.CSV, SkipLeadingRows: 1, AutoDetect: true})
+//	if err != nil {
+//	    return err
+//	}
+//	job, err := loader.Run(ctx)
+//
+// Returns an error wrapping ErrInvalidURI if the URI is not valid,
+// ErrInvalidLabel if a label is not valid, or one of the identifier errors
+// if the dataset or table ID is not valid.
+func (c *Client) SafeLoad(gcsURI, dataset, table string, cfg LoadConfig) (*bigquery.Loader, error) {
+	if err := validateGCSURI(gcsURI); err != nil {
+		return nil, fmt.Errorf("failed to create loader: %w", err)
+	}
+	t, err := c.safeTable(dataset, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create loader: %w", err)
+	}
+	if err := validateLabels(cfg.Labels); err != nil {
+		return nil, fmt.Errorf("failed to create loader: %w", err)
+	}
+	ref := bigquery.NewGCSReference(gcsURI)
+	ref.SourceFormat = cfg.SourceFormat
+	ref.Schema = cfg.Schema
+	ref.AutoDetect = cfg.AutoDetect
+	ref.SkipLeadingRows = cfg.SkipLeadingRows
+	loader := t.LoaderFrom(ref)
+	loader.WriteDisposition = cfg.WriteDisposition
+	loader.CreateDisposition = cfg.CreateDisposition
+	loader.Labels = cfg.Labels
+	return loader, nil
+}
@@ -0,0 +1,69 @@
+package saferbq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestSafeLoad(t *testing.T) {
+	cfg := LoadConfig{
+		SourceFormat:      bigquery.CSV,
+		SkipLeadingRows:   1,
+		AutoDetect:        true,
+		WriteDisposition:  bigquery.WriteTruncate,
+		CreateDisposition: bigquery.CreateIfNeeded,
+		Labels:            map[string]string{"pipeline": "ingest"},
+	}
+	loader, err := (&Client{}).SafeLoad("gs://my-bucket/exports/*.csv", "staging", "events", cfg)
+	if err != nil {
+		t.Fatalf("SafeLoad() unexpected error: %v", err)
+	}
+	if loader.Dst.DatasetID != "staging" || loader.Dst.TableID != "events" {
+		t.Errorf("SafeLoad() destination = %s.%s, want staging.events", loader.Dst.DatasetID, loader.Dst.TableID)
+	}
+	ref, ok := loader.Src.(*bigquery.GCSReference)
+	if !ok {
+		t.Fatalf("SafeLoad() source = %T, want *bigquery.GCSReference", loader.Src)
+	}
+	if !reflect.DeepEqual(ref.URIs, []string{"gs://my-bucket/exports/*.csv"}) {
+		t.Errorf("SafeLoad() URIs = %v", ref.URIs)
+	}
+	if ref.SourceFormat != bigquery.CSV || ref.SkipLeadingRows != 1 || !ref.AutoDetect {
+		t.Errorf("SafeLoad() file config = %+v", ref.FileConfig)
+	}
+	if loader.WriteDisposition != bigquery.WriteTruncate || loader.CreateDisposition != bigquery.CreateIfNeeded {
+		t.Errorf("SafeLoad() dispositions = %s, %s", loader.WriteDisposition, loader.CreateDisposition)
+	}
+	if loader.Labels["pipeline"] != "ingest" {
+		t.Errorf("SafeLoad() labels = %v", loader.Labels)
+	}
+}
+
+func TestSafeLoadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		dataset string
+		table   string
+		err     error
+	}{
+		{"invalid uri", "https://example.com/file.csv", "staging", "events", ErrInvalidURI},
+		{"invalid dataset", "gs://my-bucket/file.csv", "my-dataset", "events", ErrIdentifierInvalidChars},
+		{"invalid table", "gs://my-bucket/file.csv", "staging", "events;", ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader, err := (&Client{}).SafeLoad(tt.uri, tt.dataset, tt.table, LoadConfig{})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SafeLoad() error = %v, want %v", err, tt.err)
+			}
+			if loader != nil {
+				t.Errorf("SafeLoad() loader = %v, want nil", loader)
+			}
+		})
+	}
+}
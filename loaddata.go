@@ -0,0 +1,186 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// loadDataFormats are the data formats that the LOAD DATA statement reads
+var loadDataFormats = map[bigquery.DataFormat]bool{
+	bigquery.CSV:             true,
+	bigquery.JSON:            true,
+	bigquery.Avro:            true,
+	bigquery.Parquet:         true,
+	bigquery.ORC:             true,
+	bigquery.DatastoreBackup: true,
+}
+
+// LoadDataBuilder builds a LOAD DATA statement, that loads files from
+// Cloud Storage into a table from SQL, as an alternative to SafeLoad in
+// scripts and transactions. Use Client.LoadData to create one. Invalid
+// formats, URIs or columns are reported by SQL and Exec.
+type LoadDataBuilder struct {
+	client          *Client
+	dataset         string
+	table           string
+	overwrite       bool
+	format          bigquery.DataFormat
+	uris            []string
+	schema          bigquery.Schema
+	skipLeadingRows int64
+	fieldDelimiter  string
+}
+
+// LoadData returns a builder for a LOAD DATA statement into the table. The
+// dataset and table IDs are validated (with the rules of DatasetID and
+// TableID) and quoted, the URIs are validated like in SafeLoad and escaped
+// as string literals, and the format must be supported by LOAD DATA.
+//
+// Example:
+//
+//	err := client.LoadData("staging", "events_"+day).
+//	    Overwrite().
+//	    From(bigquery.CSV, "gs://my-bucket/exports/"+day+"/*.csv").
+//	    SkipLeadingRows(1).
+//	    Exec(ctx)
+//	// LOAD DATA OVERWRITE `staging`.`events_20240101` FROM FILES (format = 'CSV',
+//	//   uris = ['gs://my-bucket/exports/20240101/*.csv'], skip_leading_rows = 1)
+func (c *Client) LoadData(dataset, table string) *LoadDataBuilder {
+	return &LoadDataBuilder{client: c, dataset: dataset, table: table}
+}
+
+// Overwrite replaces the data of the table, instead of appending to it.
+func (l *LoadDataBuilder) Overwrite() *LoadDataBuilder {
+	l.overwrite = true
+	return l
+}
+
+// From sets the format and the Cloud Storage URIs of the files. URIs have
+// the form gs://bucket/object and may contain one * wildcard in the object
+// name.
+func (l *LoadDataBuilder) From(format bigquery.DataFormat, uris ...string) *LoadDataBuilder {
+	l.format, l.uris = format, uris
+	return l
+}
+
+// Schema sets the columns of the table, which are created when the table
+// does not exist. Without a schema, the schema of the table is used or
+// detected from the files.
+func (l *LoadDataBuilder) Schema(schema bigquery.Schema) *LoadDataBuilder {
+	l.schema = schema
+	return l
+}
+
+// SkipLeadingRows sets the number of header rows of CSV files.
+func (l *LoadDataBuilder) SkipLeadingRows(n int64) *LoadDataBuilder {
+	l.skipLeadingRows = n
+	return l
+}
+
+// FieldDelimiter sets the separator of the fields of CSV files (the
+// default is ",").
+func (l *LoadDataBuilder) FieldDelimiter(delimiter string) *LoadDataBuilder {
+	l.fieldDelimiter = delimiter
+	return l
+}
+
+// statement renders the LOAD DATA statement, with $dataset.$table as the
+// destination.
+func (l *LoadDataBuilder) statement() (string, error) {
+	options, err := fileOptions(l.format, l.uris, l.skipLeadingRows, l.fieldDelimiter)
+	if err != nil {
+		return "", err
+	}
+	var sql strings.Builder
+	if l.overwrite {
+		sql.WriteString("LOAD DATA OVERWRITE $dataset.$table")
+	} else {
+		sql.WriteString("LOAD DATA INTO $dataset.$table")
+	}
+	if len(l.schema) > 0 {
+		columns, err := columnDefinitions(l.schema)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(" (" + columns + ")")
+	}
+	sql.WriteString(" FROM FILES (" + strings.Join(options, ", ") + ")")
+	return sql.String(), nil
+}
+
+// fileOptions validates and renders the format, URIs and CSV options of
+// files in Cloud Storage.
+func fileOptions(format bigquery.DataFormat, uris []string, skipLeadingRows int64, fieldDelimiter string) ([]string, error) {
+	if !loadDataFormats[format] {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("%w: no URIs", ErrInvalidURI)
+	}
+	quoted := make([]string, len(uris))
+	for i, uri := range uris {
+		if err := validateGCSURI(uri); err != nil {
+			return nil, err
+		}
+		quoted[i] = EscapeStringLiteral(uri)
+	}
+	if format != bigquery.CSV && (skipLeadingRows != 0 || fieldDelimiter != "") {
+		return nil, fmt.Errorf("%w: skip_leading_rows and field_delimiter only apply to CSV", ErrInvalidDDL)
+	}
+	if skipLeadingRows < 0 {
+		return nil, fmt.Errorf("%w: skip_leading_rows must not be negative", ErrInvalidDDL)
+	}
+	options := []string{"format = " + EscapeStringLiteral(string(format)), "uris = [" + strings.Join(quoted, ", ") + "]"}
+	if skipLeadingRows > 0 {
+		options = append(options, "skip_leading_rows = "+strconv.FormatInt(skipLeadingRows, 10))
+	}
+	if fieldDelimiter != "" {
+		options = append(options, "field_delimiter = "+EscapeStringLiteral(fieldDelimiter))
+	}
+	return options, nil
+}
+
+// query returns the LOAD DATA query with the dataset and table bound.
+func (l *LoadDataBuilder) query() (*Query, error) {
+	sql, err := l.statement()
+	if err != nil {
+		return nil, err
+	}
+	q := l.client.Query(sql)
+	q.SetParams(map[string]any{"$dataset": DatasetID(l.dataset), "$table": TableID(l.table)})
+	return q, nil
+}
+
+// SQL returns the translated LOAD DATA statement without executing it.
+//
+// Returns an error wrapping ErrInvalidFormat if the format is not
+// supported, ErrInvalidURI if a URI is not valid, or an error if a column
+// or identifier is not valid.
+func (l *LoadDataBuilder) SQL() (string, error) {
+	q, err := l.query()
+	if err == nil {
+		err = q.translate()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load data: %w", err)
+	}
+	return q.QueryConfig.Q, nil
+}
+
+// Exec runs the LOAD DATA statement.
+//
+// Returns the errors of SQL, or an error if the statement fails.
+func (l *LoadDataBuilder) Exec(ctx context.Context) error {
+	q, err := l.query()
+	if err == nil {
+		_, err = q.Exec(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestClientLoadData(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	err := client.LoadData("staging", "events_20240101").
+		Overwrite().
+		From(bigquery.CSV, "gs://my-bucket/exports/20240101/*.csv", "gs://my-bucket/exports/it's.csv").
+		SkipLeadingRows(1).
+		FieldDelimiter(";").
+		Schema(bigquery.Schema{{Name: "id", Type: bigquery.IntegerFieldType}, {Name: "name", Type: bigquery.StringFieldType}}).
+		Exec(context.Background())
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	expected := "LOAD DATA OVERWRITE `staging`.`events_20240101` (`id` INT64, `name` STRING) FROM FILES (format = 'CSV', " +
+		"uris = ['gs://my-bucket/exports/20240101/*.csv', 'gs://my-bucket/exports/it\\'s.csv'], skip_leading_rows = 1, field_delimiter = ';')"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Exec() queries = %q, want %q", queries, expected)
+	}
+}
+
+func TestLoadDataBuilderSQL(t *testing.T) {
+	client := &Client{}
+	tests := []struct {
+		name     string
+		builder  *LoadDataBuilder
+		expected string
+		err      error
+	}{
+		{"parquet", client.LoadData("staging", "events").From(bigquery.Parquet, "gs://my-bucket/events/*.parquet"),
+			"LOAD DATA INTO `staging`.`events` FROM FILES (format = 'PARQUET', uris = ['gs://my-bucket/events/*.parquet'])", nil},
+		{"json", client.LoadData("staging", "events").From(bigquery.JSON, "gs://my-bucket/events.json"),
+			"LOAD DATA INTO `staging`.`events` FROM FILES (format = 'NEWLINE_DELIMITED_JSON', uris = ['gs://my-bucket/events.json'])", nil},
+		{"no format", client.LoadData("staging", "events"), "", ErrInvalidFormat},
+		{"unsupported format", client.LoadData("staging", "events").From(bigquery.GoogleSheets, "gs://my-bucket/a.csv"), "", ErrInvalidFormat},
+		{"no uris", client.LoadData("staging", "events").From(bigquery.CSV), "", ErrInvalidURI},
+		{"invalid uri", client.LoadData("staging", "events").From(bigquery.CSV, "gs://My Bucket/a.csv"), "", ErrInvalidURI},
+		{"http uri", client.LoadData("staging", "events").From(bigquery.CSV, "https://example.com/a.csv"), "", ErrInvalidURI},
+		{"csv option for parquet", client.LoadData("staging", "events").From(bigquery.Parquet, "gs://my-bucket/a.parquet").SkipLeadingRows(1), "", ErrInvalidDDL},
+		{"negative skip", client.LoadData("staging", "events").From(bigquery.CSV, "gs://my-bucket/a.csv").SkipLeadingRows(-1), "", ErrInvalidDDL},
+		{"invalid column", client.LoadData("staging", "events").From(bigquery.CSV, "gs://my-bucket/a.csv").
+			Schema(bigquery.Schema{{Name: "a b)", Type: bigquery.StringFieldType}}), "", ErrIdentifierInvalidChars},
+		{"invalid table", client.LoadData("staging", "events;").From(bigquery.CSV, "gs://my-bucket/a.csv"), "", ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := tt.builder.SQL()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SQL() error = %v, want %v", err, tt.err)
+			}
+			if sql != tt.expected {
+				t.Errorf("SQL() = %q, want %q", sql, tt.expected)
+			}
+		})
+	}
+}
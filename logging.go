@@ -0,0 +1,62 @@
+package saferbq
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// WithLogger logs every executed query using the given structured logger.
+// Each Run and Read is logged with the SQL template, the translated SQL, the
+// parameter names (values are never logged), the duration and the job ID.
+// Successful queries are logged at info level, failed queries at error level.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithLogger(slog.Default()))
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// parameterNames returns the names of the parameters, with positional
+// parameters represented by a question mark.
+func parameterNames(params []bigquery.QueryParameter) []string {
+	names := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.Name == "" {
+			names = append(names, string(questionMark))
+		} else {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// logQuery logs an executed query when the client has a logger configured.
+func (q *Query) logQuery(ctx context.Context, method string, start time.Time, names []string, job *bigquery.Job, err error) {
+	if q.client == nil || q.client.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("template", q.originalSQL),
+		slog.Any("params", names),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if q.translated {
+		attrs = append(attrs, slog.String("sql", q.QueryConfig.Q))
+	}
+	if job != nil {
+		attrs = append(attrs, slog.String("job_id", job.ID()))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		q.client.logger.LogAttrs(ctx, slog.LevelError, "saferbq query failed", attrs...)
+		return
+	}
+	q.client.logger.LogAttrs(ctx, slog.LevelInfo, "saferbq query", attrs...)
+}
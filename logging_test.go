@@ -0,0 +1,75 @@
+package saferbq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestWithLogger(t *testing.T) {
+	fake := &fakeBigQuery{schema: []map[string]any{{"name": "id", "type": "INTEGER"}}}
+	client := newFakeClient(t, fake)
+	var buf bytes.Buffer
+	client.Configure(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	ctx := context.Background()
+
+	q := client.Query("SELECT id FROM $table WHERE status = @status")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}, {Name: "@status", Value: "secret-value"}}
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry is not valid JSON: %v", err)
+	}
+	expected := map[string]any{
+		"level":    "INFO",
+		"msg":      "saferbq query",
+		"method":   "Run",
+		"template": "SELECT id FROM $table WHERE status = @status",
+		"sql":      "SELECT id FROM `mytable` WHERE status = @status",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("log entry %s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if params, _ := json.Marshal(entry["params"]); string(params) != `["$table","@status"]` {
+		t.Errorf("log entry params = %s, want [\"$table\",\"@status\"]", params)
+	}
+	if entry["job_id"] == nil || entry["duration"] == nil {
+		t.Errorf("log entry = %v, want job_id and duration", entry)
+	}
+	if strings.Contains(buf.String(), "secret-value") {
+		t.Error("log entry should not contain parameter values")
+	}
+}
+
+func TestWithLoggerError(t *testing.T) {
+	var buf bytes.Buffer
+	client := (&Client{}).Configure(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	q := &Query{client: client}
+	q.QueryConfig.Q = "SELECT * FROM $table"
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "my;table"}, {Value: 1}}
+	if _, err := q.Read(context.Background()); err == nil {
+		t.Fatal("Read() expected error")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry is not valid JSON: %v", err)
+	}
+	if entry["level"] != "ERROR" || entry["method"] != "Read" || entry["error"] == nil {
+		t.Errorf("log entry = %v, want error entry for Read", entry)
+	}
+	if params, _ := json.Marshal(entry["params"]); string(params) != `["$table","?"]` {
+		t.Errorf("log entry params = %s, want [\"$table\",\"?\"]", params)
+	}
+}
@@ -0,0 +1,89 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery/storage/managedwriter"
+	"google.golang.org/protobuf/proto"
+)
+
+// ManagedStream is a Storage Write API stream to a validated destination
+// table. It embeds managedwriter.ManagedStream and owns the underlying
+// write client, that is closed with the stream.
+type ManagedStream struct {
+	*managedwriter.ManagedStream
+	writer *managedwriter.Client
+}
+
+// ManagedStream opens a Storage Write API stream to the table, for high
+// throughput writes. The dataset and table IDs are validated with the same
+// rules as DatasetID and TableID and the destination is built from the
+// project of the client, so it can't be overridden by the options. The
+// write client is created with the options the client was created with.
+//
+// The options must include the schema of the rows, see
+// managedwriter.WithSchemaDescriptor. Without a stream type option the
+// default stream is used, which commits rows as soon as they are appended.
+//
+// Example:
+//
+//	descriptor, err := adapt.NormalizeDescriptor((&pb.Event{}).ProtoReflect().Descriptor())
+//	if err != nil {
+//	    return err
+//	}
+//	stream, err := client.ManagedStream(ctx, "analytics", "events",
+//	    managedwriter.WithSchemaDescriptor(descriptor))
+//	if err != nil {
+//	    return err
+//	}
+//	defer stream.Close()
+//	result, err := stream.AppendMessages(ctx, events...)
+//
+// Returns an error wrapping one of the identifier errors (such as
+// ErrIdentifierInvalidChars) if the dataset or table ID is not valid, or an
+// error if the stream could not be opened.
+func (c *Client) ManagedStream(ctx context.Context, dataset, table string, opts ...managedwriter.WriterOption) (*ManagedStream, error) {
+	if _, err := c.safeTable(dataset, table); err != nil {
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+	writer, err := managedwriter.NewClient(ctx, c.Project(), c.clientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+	destination := managedwriter.TableParentFromParts(c.Project(), dataset, table)
+	opts = append(opts[:len(opts):len(opts)], managedwriter.WithDestinationTable(destination))
+	stream, err := writer.NewManagedStream(ctx, opts...)
+	if err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+	return &ManagedStream{ManagedStream: stream, writer: writer}, nil
+}
+
+// AppendMessages serializes the messages and appends them to the stream as
+// a single request. The messages must match the schema descriptor of the
+// stream. Use the result to wait for the append to be acknowledged.
+//
+// Returns an error if a message could not be serialized or the append
+// could not be sent.
+func (s *ManagedStream) AppendMessages(ctx context.Context, messages ...proto.Message) (*managedwriter.AppendResult, error) {
+	rows := make([][]byte, len(messages))
+	for i, message := range messages {
+		row, err := proto.Marshal(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize row %d: %w", i, err)
+		}
+		rows[i] = row
+	}
+	return s.AppendRows(ctx, rows)
+}
+
+// Close closes the stream and the underlying write client.
+func (s *ManagedStream) Close() error {
+	err := s.ManagedStream.Close()
+	if closeErr := s.writer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestManagedStreamValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataset string
+		table   string
+		err     error
+	}{
+		{"dataset with dash", "my-dataset", "events", ErrIdentifierInvalidChars},
+		{"table with path", "analytics", "other.events", ErrIdentifierInvalidChars},
+		{"empty table", "analytics", "", ErrIdentifierEmpty},
+	}
+
+	client := &Client{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.ManagedStream(context.Background(), tt.dataset, tt.table)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ManagedStream() error = %v, want %v", err, tt.err)
+			}
+			if stream != nil {
+				t.Errorf("ManagedStream() stream = %v, want nil", stream)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package saferbq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Manifest is a machine-readable description of the prepared statements of
+// a Client and the guardrails that apply to them. It can be encoded as JSON
+// to feed documentation generators and access-review tooling.
+type Manifest struct {
+	// Templates describes every statement prepared with Client.Prepare, when
+	// the client is configured with WithTemplateManifest
+	Templates []TemplateInfo `json:"templates"`
+	// Guardrails describes the limits that apply to all queries of the client
+	Guardrails Guardrails `json:"guardrails"`
+}
+
+// TemplateInfo describes the placeholders of a prepared statement.
+type TemplateInfo struct {
+	// SQL is the original SQL of the statement
+	SQL string `json:"sql"`
+	// Identifiers are the $identifier names in the SQL, sorted
+	Identifiers []string `json:"identifiers"`
+	// Parameters are the @parameter names in the SQL, sorted
+	Parameters []string `json:"parameters"`
+	// PositionalParameters is the number of ? parameters in the SQL
+	PositionalParameters int `json:"positional_parameters"`
+	// Tables are the table paths the SQL reads from or writes to, sorted.
+	// Paths may contain $identifier placeholders, like "$dataset.$table".
+	Tables []string `json:"tables"`
+	// Variables are the scripting variables declared in the SQL with their
+	// declared type (empty when the type is inferred from the DEFAULT)
+	Variables map[string]string `json:"variables"`
+}
+
+// Guardrails describes the limits configured on a Client.
+// A zero value means that no limit is configured.
+type Guardrails struct {
+	// MaxScanBytes is the limit set with WithMaxScanBytes
+	MaxScanBytes int64 `json:"max_scan_bytes"`
+	// MaxBytesBilled is the limit set with WithMaxBytesBilled, where
+	// NoMaxBytesBilled (-1) disables the limit of the project
+	MaxBytesBilled int64 `json:"max_bytes_billed"`
+	// MaxConcurrentQueries is the limit set with WithMaxConcurrentQueries
+	MaxConcurrentQueries int `json:"max_concurrent_queries"`
+	// InteractiveLaneLimit is the interactive limit set with WithLaneLimits
+	InteractiveLaneLimit int `json:"interactive_lane_limit"`
+	// BackgroundLaneLimit is the background limit set with WithLaneLimits
+	BackgroundLaneLimit int `json:"background_lane_limit"`
+	// QueryTimeout is the timeout set with WithQueryTimeout, like "30s"
+	QueryTimeout string `json:"query_timeout"`
+	// Scheduler describes the scheduler set with WithScheduler, using its
+	// String method when it has one, or its type otherwise
+	Scheduler string `json:"scheduler"`
+	// OffPeakWindows are the windows set with WithOffPeakSchedule, like
+	// "22:00-06:00 Europe/Amsterdam"
+	OffPeakWindows []string `json:"off_peak_windows"`
+}
+
+// WithTemplateManifest registers every statement that is prepared with
+// Client.Prepare, so it is described by Client.TemplateManifest. The
+// registry keeps the SQL of every distinct statement for the lifetime of the
+// client, so enable it only for clients that prepare a fixed set of
+// templates, not for clients that prepare SQL that is built at run time.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithTemplateManifest())
+func WithTemplateManifest() Option {
+	return func(c *Client) {
+		c.registerStatements = true
+	}
+}
+
+// TemplateManifest returns a description of every statement prepared on the
+// client, sorted by SQL, together with the configured guardrails. Statements
+// are only described when the client is configured with WithTemplateManifest.
+//
+// Example:
+//
+//	manifest := client.TemplateManifest()
+//	json.NewEncoder(os.Stdout).Encode(manifest)
+func (c *Client) TemplateManifest() Manifest {
+	manifest := Manifest{
+		Templates: []TemplateInfo{},
+		Guardrails: Guardrails{
+			MaxScanBytes:         c.maxScanBytes,
+			MaxBytesBilled:       c.maxBytesBilled,
+			MaxConcurrentQueries: cap(c.maxConcurrent),
+			InteractiveLaneLimit: cap(c.lanes[LaneInteractive]),
+			BackgroundLaneLimit:  cap(c.lanes[LaneBackground]),
+			OffPeakWindows:       c.deferred.schedule.describe(),
+		},
+	}
+	if c.queryTimeout > 0 {
+		manifest.Guardrails.QueryTimeout = c.queryTimeout.String()
+	}
+	switch s := c.scheduler.(type) {
+	case nil:
+	case fmt.Stringer:
+		manifest.Guardrails.Scheduler = s.String()
+	default:
+		manifest.Guardrails.Scheduler = fmt.Sprintf("%T", s)
+	}
+	c.statements.Range(func(_, value any) bool {
+		manifest.Templates = append(manifest.Templates, value.(*template).info())
+		return true
+	})
+	sort.Slice(manifest.Templates, func(i, j int) bool {
+		return manifest.Templates[i].SQL < manifest.Templates[j].SQL
+	})
+	return manifest
+}
+
+// Describe parses the SQL and describes its placeholders, without binding
+// any values. It is meant for tooling that supplies only the parameters a
+// template uses.
+//
+// Example:
+//
+//	info, err := saferbq.Describe("SELECT * FROM $table WHERE id = @id")
+//	// info.Identifiers: [$table], info.Parameters: [@id]
+//
+// Returns an error if the SQL is empty or mixes named and positional
+// parameters.
+func Describe(sql string) (TemplateInfo, error) {
+	t, err := parse(sql)
+	if err != nil {
+		return TemplateInfo{}, err
+	}
+	return t.info(), nil
+}
+
+// info returns the description of the template.
+func (t *template) info() TemplateInfo {
+	tokens := scan(t.source)
+	variables := map[string]string{}
+	for _, d := range declarations(tokens) {
+		if _, seen := variables[d.name]; !seen {
+			variables[d.name] = d.typ
+		}
+	}
+	return TemplateInfo{
+		SQL:                  t.source,
+		Identifiers:          sortedKeys(t.identifiers),
+		Parameters:           sortedKeys(t.parameters),
+		PositionalParameters: len(t.positionals),
+		Tables:               referencedTables(tokens),
+		Variables:            variables,
+	}
+}
+
+// tableKeywords are the keywords that can be followed by a table path.
+var tableKeywords = map[string]bool{
+	"FROM": true, "JOIN": true, "INTO": true, "UPDATE": true, "TABLE": true,
+	"USING": true, "INSERT": true, "DELETE": true, "MERGE": true,
+}
+
+// referencedTables returns the sorted table paths that follow the table
+// keywords in the tokens. Function calls, like UNNEST(...), subqueries, the
+// FROM of EXTRACT(... FROM ...) and the names of common table expressions
+// are skipped. The result is a best effort, as the SQL is not fully parsed.
+func referencedTables(tokens []token) []string {
+	// Skip whitespace and comments
+	significant := tokens[:0:0]
+	for _, tok := range tokens {
+		if tok.kind != tokenWhitespace && tok.kind != tokenComment {
+			significant = append(significant, tok)
+		}
+	}
+	ctes := map[string]bool{}
+	tables := map[string]bool{}
+	// extract holds for every open bracket whether it belongs to EXTRACT
+	var extract []bool
+	for i, tok := range significant {
+		switch {
+		case tok.text == "(":
+			extract = append(extract, i > 0 && strings.EqualFold(significant[i-1].text, "EXTRACT"))
+		case tok.text == ")":
+			if len(extract) > 0 {
+				extract = extract[:len(extract)-1]
+			}
+		case tok.kind == tokenWord && i > 0 && i+2 < len(significant) &&
+			(significant[i-1].text == "," || strings.EqualFold(significant[i-1].text, "WITH") ||
+				strings.EqualFold(significant[i-1].text, "RECURSIVE")) &&
+			strings.EqualFold(significant[i+1].text, "AS") && significant[i+2].text == "(":
+			// WITH name AS ( is a common table expression
+			ctes[strings.ToLower(tok.text)] = true
+		case tok.kind == tokenWord && tableKeywords[strings.ToUpper(tok.text)]:
+			if len(extract) > 0 && extract[len(extract)-1] {
+				continue
+			}
+			j := i + 1
+			// Skip the IF [NOT] EXISTS of DDL statements
+			for j < len(significant) && significant[j].kind == tokenWord &&
+				strings.Contains(" IF NOT EXISTS ", " "+strings.ToUpper(significant[j].text)+" ") {
+				j++
+			}
+			keyword := strings.ToUpper(tok.text)
+			if path, ok := tablePath(tokens, significant, j, keyword == "FROM" || keyword == "JOIN"); ok {
+				tables[path] = true
+			}
+		}
+	}
+	for path := range tables {
+		if ctes[strings.ToLower(path)] {
+			delete(tables, path)
+		}
+	}
+	return sortedKeys(tables)
+}
+
+// tablePath returns the table path that starts at the significant token j,
+// or false when there is no table path, because the name is a keyword or,
+// when functions is set, a function call. The path consists of adjacent
+// tokens, so it may contain dashes, as in my-project.dataset.table.
+func tablePath(tokens, significant []token, j int, functions bool) (string, bool) {
+	if j >= len(significant) {
+		return "", false
+	}
+	first := significant[j]
+	switch {
+	case first.kind == tokenWord && reservedKeywords[strings.ToUpper(first.text)]:
+		return "", false
+	case first.kind != tokenWord && first.kind != tokenQuotedIdentifier && first.kind != tokenIdentifierParam:
+		return "", false
+	}
+	// Find the token in the full stream to collect the adjacent tokens
+	k := sort.Search(len(tokens), func(k int) bool { return tokens[k].offset >= first.offset })
+	var path strings.Builder
+	for ; k < len(tokens); k++ {
+		tok := tokens[k]
+		if tok.kind != tokenWord && tok.kind != tokenQuotedIdentifier && tok.kind != tokenIdentifierParam &&
+			!(tok.kind == tokenOther && strings.Contains(".-0123456789", tok.text)) {
+			break
+		}
+		path.WriteString(tok.text)
+	}
+	// A name followed by a bracket is a function call
+	for ; functions && k < len(tokens); k++ {
+		if tokens[k].kind != tokenWhitespace && tokens[k].kind != tokenComment {
+			if tokens[k].text == "(" {
+				return "", false
+			}
+			break
+		}
+	}
+	return path.String(), true
+}
+
+// describe returns the windows of the schedule, like "22:00-06:00 UTC".
+func (s OffPeakSchedule) describe() []string {
+	location := s.Location
+	if location == nil {
+		location = time.UTC
+	}
+	windows := []string{}
+	for _, w := range s.Windows {
+		windows = append(windows, fmt.Sprintf("%s-%s %s", clock(w.Start), clock(w.End), location))
+	}
+	return windows
+}
+
+// clock formats an offset since midnight as hours and minutes.
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package saferbq
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientTemplateManifest(t *testing.T) {
+	client := (&Client{}).Configure(
+		WithTemplateManifest(),
+		WithMaxScanBytes(1000),
+		WithMaxBytesBilled(5000),
+		WithMaxConcurrentQueries(50),
+		WithLaneLimits(10, 2),
+		WithQueryTimeout(90*time.Second),
+		WithScheduler(NewTokenBucketScheduler(10, 20)),
+		WithOffPeakSchedule(OffPeakSchedule{Windows: []OffPeakWindow{{Start: 22 * time.Hour, End: 6*time.Hour + 30*time.Minute}}}),
+	)
+
+	for _, sql := range []string{
+		"SELECT * FROM $table WHERE status = @status AND id = @id",
+		"DELETE FROM $dataset.$table WHERE id = ?",
+		"SELECT * FROM $table WHERE status = @status AND id = @id",
+	} {
+		if _, err := client.Prepare(sql); err != nil {
+			t.Fatalf("Prepare() unexpected error: %v", err)
+		}
+	}
+
+	manifest, err := json.Marshal(client.TemplateManifest())
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	expected := `{"templates":[` +
+		`{"sql":"DELETE FROM $dataset.$table WHERE id = ?","identifiers":["$dataset","$table"],"parameters":[],"positional_parameters":1,"tables":["$dataset.$table"],"variables":{}},` +
+		`{"sql":"SELECT * FROM $table WHERE status = @status AND id = @id","identifiers":["$table"],"parameters":["@id","@status"],"positional_parameters":0,"tables":["$table"],"variables":{}}` +
+		`],"guardrails":{"max_scan_bytes":1000,"max_bytes_billed":5000,"max_concurrent_queries":50,` +
+		`"interactive_lane_limit":10,"background_lane_limit":2,"query_timeout":"1m30s",` +
+		`"scheduler":"token bucket: 10 queries/s, burst 20","off_peak_windows":["22:00-06:30 UTC"]}}`
+	if string(manifest) != expected {
+		t.Errorf("TemplateManifest() = %s, want %s", manifest, expected)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	info, err := Describe("SELECT * FROM $dataset.$table WHERE id = @id AND '$x' = ''")
+	if err != nil {
+		t.Fatalf("Describe() unexpected error: %v", err)
+	}
+	want := TemplateInfo{
+		SQL:         "SELECT * FROM $dataset.$table WHERE id = @id AND '$x' = ''",
+		Identifiers: []string{"$dataset", "$table"},
+		Parameters:  []string{"@id"},
+		Tables:      []string{"$dataset.$table"},
+		Variables:   map[string]string{},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("Describe() = %+v, want %+v", info, want)
+	}
+	if _, err := Describe(""); !errors.Is(err, ErrEmptySQL) {
+		t.Errorf("Describe() error = %v, want %v", err, ErrEmptySQL)
+	}
+}
+
+func TestDescribeVariables(t *testing.T) {
+	info, err := Describe("DECLARE x, y INT64 DEFAULT 0;\nDECLARE tags ARRAY<STRING>;\nDECLARE z DEFAULT 'a';\nSELECT x")
+	if err != nil {
+		t.Fatalf("Describe() unexpected error: %v", err)
+	}
+	want := map[string]string{"x": "INT64", "y": "INT64", "tags": "ARRAY<STRING>", "z": ""}
+	if !reflect.DeepEqual(info.Variables, want) {
+		t.Errorf("Describe() variables = %v, want %v", info.Variables, want)
+	}
+}
+
+func TestReferencedTables(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{"SELECT * FROM users u JOIN `my-project.sales.orders` o ON u.id = o.user_id", []string{"`my-project.sales.orders`", "users"}},
+		{"SELECT * FROM my-project.sales.orders", []string{"my-project.sales.orders"}},
+		{"INSERT INTO $dataset.$table (id) SELECT id FROM staging", []string{"$dataset.$table", "staging"}},
+		{"MERGE target t USING source s ON t.id = s.id WHEN MATCHED THEN DELETE", []string{"source", "target"}},
+		{"UPDATE users SET name = 'x' WHERE id = 1", []string{"users"}},
+		{"DELETE users WHERE true", []string{"users"}},
+		{"CREATE TABLE IF NOT EXISTS archive AS (SELECT * FROM events)", []string{"archive", "events"}},
+		{"WITH recent AS (SELECT * FROM events) SELECT * FROM recent", []string{"events"}},
+		{"SELECT EXTRACT(DAY FROM created) FROM events, UNNEST(tags)", []string{"events"}},
+		{"SELECT * FROM UNNEST([1, 2]) JOIN t USING (id)", []string{"t"}},
+		{"SELECT 'FROM x' -- FROM y\nFROM (SELECT 1)", []string{}},
+	}
+	for _, tt := range tests {
+		got := referencedTables(scan(tt.sql))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("referencedTables(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestClientTemplateManifestDisabled(t *testing.T) {
+	client := &Client{}
+	if _, err := client.Prepare("SELECT * FROM $table"); err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+	if templates := client.TemplateManifest().Templates; len(templates) != 0 {
+		t.Errorf("TemplateManifest() templates = %v, want none without WithTemplateManifest", templates)
+	}
+}
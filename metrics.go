@@ -0,0 +1,269 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/api/googleapi"
+)
+
+// Metrics is a Prometheus collector that tracks the queries executed by one
+// or more clients. Spikes in translation failures (for instance of
+// ErrIdentifierInvalidChars) may indicate injection attempts.
+//
+// Use NewMetrics() to create a new Metrics instance.
+type Metrics struct {
+	queries             *prometheus.CounterVec
+	translationFailures *prometheus.CounterVec
+	identifiersQuoted   prometheus.Counter
+	duration            *prometheus.HistogramVec
+	bytesBilled         prometheus.Counter
+	queueDepth          *prometheus.GaugeVec
+	queueWait           *prometheus.HistogramVec
+}
+
+// NewMetrics creates a new metrics collector. Register it with a
+// prometheus.Registerer and configure it on the clients to track.
+//
+// Example:
+//
+//	metrics := saferbq.NewMetrics()
+//	prometheus.MustRegister(metrics)
+//	client.Configure(saferbq.WithMetrics(metrics))
+func NewMetrics() *Metrics {
+	return &Metrics{
+		queries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "saferbq_queries_total",
+			Help: "Number of executed queries by method and status.",
+		}, []string{"method", "status"}),
+		translationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "saferbq_translation_failures_total",
+			Help: "Number of failed query translations by error, counted once for every error of a translation.",
+		}, []string{"error"}),
+		identifiersQuoted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "saferbq_identifiers_quoted_total",
+			Help: "Number of $identifier values that were validated and quoted.",
+		}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "saferbq_query_duration_seconds",
+			Help:    "Duration of Run and Read calls in seconds.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"method"}),
+		bytesBilled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "saferbq_bytes_billed_total",
+			Help: "Number of bytes billed for completed query jobs.",
+		}),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "saferbq_queue_depth",
+			Help: "Number of queries waiting for a lane slot or the scheduler by lane.",
+		}, []string{"lane"}),
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "saferbq_queue_wait_seconds",
+			Help:    "Time queries waited for a lane slot and the scheduler in seconds.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+		}, []string{"lane"}),
+	}
+}
+
+// WithMetrics records the queries executed by the client in the collector.
+// The same collector may be shared by multiple clients.
+func WithMetrics(m *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.queries.Describe(ch)
+	m.translationFailures.Describe(ch)
+	m.identifiersQuoted.Describe(ch)
+	m.duration.Describe(ch)
+	m.bytesBilled.Describe(ch)
+	m.queueDepth.Describe(ch)
+	m.queueWait.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.queries.Collect(ch)
+	m.translationFailures.Collect(ch)
+	m.identifiersQuoted.Collect(ch)
+	m.duration.Collect(ch)
+	m.bytesBilled.Collect(ch)
+	m.queueDepth.Collect(ch)
+	m.queueWait.Collect(ch)
+}
+
+// observeTranslation records the result of a translation. The metrics may be nil.
+func (m *Metrics) observeTranslation(identifiers int, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		for _, kind := range errorKindsOf(err) {
+			m.translationFailures.WithLabelValues(kind).Inc()
+		}
+		return
+	}
+	m.identifiersQuoted.Add(float64(identifiers))
+}
+
+// observeQuery records an executed query. The metrics may be nil.
+func (m *Metrics) observeQuery(method string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	m.queries.WithLabelValues(method, status).Inc()
+	m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// enterQueue records a query that starts waiting in the lane. The metrics
+// may be nil.
+func (m *Metrics) enterQueue(lane Lane) {
+	if m == nil {
+		return
+	}
+	m.queueDepth.WithLabelValues(lane.String()).Inc()
+}
+
+// leaveQueue records a query that stops waiting in the lane. The metrics
+// may be nil.
+func (m *Metrics) leaveQueue(lane Lane, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.queueDepth.WithLabelValues(lane.String()).Dec()
+	m.queueWait.WithLabelValues(lane.String()).Observe(time.Since(start).Seconds())
+}
+
+// observeStatus records the bytes billed of a completed job. The metrics
+// may be nil.
+func (m *Metrics) observeStatus(status *bigquery.JobStatus) {
+	if m == nil || status == nil || status.Statistics == nil {
+		return
+	}
+	if details, ok := status.Statistics.Details.(*bigquery.QueryStatistics); ok {
+		m.bytesBilled.Add(float64(details.TotalBytesBilled))
+	}
+}
+
+// errorKinds are the sentinel errors of the package, which are used as error
+// label and are kept by redactValue. Errors are
+// matched with errors.Is, so the number of label values stays bounded
+// whatever the error messages contain.
+var errorKinds = []error{
+	ErrInvalidParameterName,
+	ErrParameterNotFound,
+	ErrParameterNotProvided,
+	ErrIdentifierNotFound,
+	ErrIdentifierNotProvided,
+	ErrIdentifierEmpty,
+	ErrIdentifierTooLong,
+	ErrIdentifierInvalidChars,
+	ErrIdentifierNotAllowed,
+	ErrNotEnoughPositionalParams,
+	ErrTooManyPositionalParams,
+	ErrMixedParameterTypes,
+	ErrEmptySQL,
+	ErrQueryTooExpensive,
+	ErrDryRunFailed,
+	ErrJobFailed,
+	ErrInvalidLane,
+	ErrInvalidSamplePercent,
+	ErrInvalidSweep,
+	ErrParameterConflict,
+	ErrDuplicateParameter,
+	ErrInvalidInteger,
+	ErrInvalidURI,
+	ErrInvalidFormat,
+	ErrInvalidDDL,
+	ErrInvalidLiteral,
+	ErrInvalidPrincipal,
+	ErrInvalidLabel,
+	ErrNoRows,
+	ErrTooManyRows,
+	ErrNotRun,
+	ErrScriptVariable,
+	ErrUntypedNull,
+	context.Canceled,
+	context.DeadlineExceeded,
+}
+
+// apiReasons are the BigQuery error reasons that are used as error label,
+// see https://cloud.google.com/bigquery/docs/error-messages
+var apiReasons = map[string]bool{
+	"accessDenied":             true,
+	"backendError":             true,
+	"billingNotEnabled":        true,
+	"billingTierLimitExceeded": true,
+	"blocked":                  true,
+	"duplicate":                true,
+	"internalError":            true,
+	"invalid":                  true,
+	"invalidQuery":             true,
+	"invalidUser":              true,
+	"jobBackendError":          true,
+	"jobInternalError":         true,
+	"jobRateLimitExceeded":     true,
+	"notFound":                 true,
+	"notImplemented":           true,
+	"quotaExceeded":            true,
+	"rateLimitExceeded":        true,
+	"resourceInUse":            true,
+	"resourcesExceeded":        true,
+	"responseTooLarge":         true,
+	"stopped":                  true,
+	"tableUnavailable":         true,
+	"timeout":                  true,
+}
+
+// errorKindsOf returns the label values of the error: the messages of all
+// sentinel errors it wraps, as a translation reports all its problems at
+// once, joined with errors.Join. Errors that wrap no sentinel error have the
+// reason of a BigQuery API error, or "other", as their only label value.
+func errorKindsOf(err error) []string {
+	var kinds []string
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			kinds = append(kinds, kind.Error())
+		}
+	}
+	if len(kinds) > 0 {
+		return kinds
+	}
+	return []string{errorKind(err)}
+}
+
+// errorKind returns the label value of an error that wraps no sentinel
+// error: the reason of a BigQuery API error, or "other".
+func errorKind(err error) string {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, item := range apiErr.Errors {
+			if apiReasons[item.Reason] {
+				return item.Reason
+			}
+		}
+	}
+	var bqErr *bigquery.Error
+	if errors.As(err, &bqErr) && apiReasons[bqErr.Reason] {
+		return bqErr.Reason
+	}
+	return "other"
+}
+
+// metricsOrNil returns the metrics of the client. The client may be nil.
+func (c *Client) metricsOrNil() *Metrics {
+	if c == nil {
+		return nil
+	}
+	return c.metrics
+}
@@ -0,0 +1,91 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/api/googleapi"
+)
+
+func TestWithMetrics(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema:     []map[string]any{{"name": "id", "type": "INTEGER"}},
+		statistics: map[string]any{"totalBytesBilled": "1000"},
+	}
+	client := newFakeClient(t, fake)
+	metrics := NewMetrics()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+	client.Configure(WithMetrics(metrics))
+	ctx := context.Background()
+
+	q := client.Query("SELECT id FROM $dataset.$table")
+	q.SetParams(map[string]any{"$dataset": "mydataset", "$table": "mytable"})
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	q = client.Query("SELECT id FROM $table WHERE id = @id")
+	q.SetParams(map[string]any{"$table": "my`table"})
+	if _, err := q.Read(ctx); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Fatalf("Read() error = %v, want ErrIdentifierInvalidChars", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.queries.WithLabelValues("Run", "ok")); got != 1 {
+		t.Errorf("queries{Run,ok} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.queries.WithLabelValues("Read", "error")); got != 1 {
+		t.Errorf("queries{Read,error} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.translationFailures.WithLabelValues(ErrIdentifierInvalidChars.Error())); got != 1 {
+		t.Errorf("translation_failures{invalid chars} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.translationFailures.WithLabelValues(ErrParameterNotProvided.Error())); got != 1 {
+		t.Errorf("translation_failures{not provided} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.identifiersQuoted); got != 2 {
+		t.Errorf("identifiers_quoted = %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(metrics, "saferbq_query_duration_seconds"); got != 2 {
+		t.Errorf("query_duration_seconds series = %d, want 2", got)
+	}
+	// Bytes billed are recorded in the background when the job is done
+	deadline := time.Now().Add(5 * time.Second)
+	for testutil.ToFloat64(metrics.bytesBilled) != 1000 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := testutil.ToFloat64(metrics.bytesBilled); got != 1000 {
+		t.Errorf("bytes_billed = %v, want 1000", got)
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		kinds []string
+	}{
+		{"sentinel", ErrEmptySQL, []string{"query SQL cannot be empty"}},
+		{"wrapped", fmt.Errorf("failed to translate query: %w", fmt.Errorf("%w: $table", ErrIdentifierEmpty)), []string{"identifier is empty"}},
+		{"joined", errors.Join(fmt.Errorf("%w: @a", ErrParameterNotFound), ErrEmptySQL), []string{"parameter not found in query", "query SQL cannot be empty"}},
+		{"context", fmt.Errorf("failed to run query: %w", context.DeadlineExceeded), []string{"context deadline exceeded"}},
+		{"api", &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{{Reason: "invalidQuery"}}}, []string{"invalidQuery"}},
+		{"job", fmt.Errorf("%w: %w", ErrJobFailed, &bigquery.Error{Reason: "stopped"}), []string{"job failed"}},
+		{"unknown reason", &bigquery.Error{Reason: "user supplied text"}, []string{"other"}},
+		{"plain", errors.New("table `secret` not found"), []string{"other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorKindsOf(tt.err); !slices.Equal(got, tt.kinds) {
+				t.Errorf("errorKindsOf() = %q, want %q", got, tt.kinds)
+			}
+		})
+	}
+}
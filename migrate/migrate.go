@@ -0,0 +1,373 @@
+// Package migrate runs versioned schema migrations with saferbq.
+//
+// Migrations are .sql files named <version>_<name>.up.sql and (optionally)
+// <version>_<name>.down.sql. They may contain $identifier placeholders, such
+// as $dataset or $table, that are bound to the parameters of the Migrator
+// and validated like in any other saferbq query. The applied versions are
+// recorded in a history table that is created by the Migrator.
+//
+// Example:
+//
+//	//go:embed migrations/*.sql
+//	var files embed.FS
+//
+//	sub, _ := fs.Sub(files, "migrations")
+//	migrations, err := migrate.Load(sub)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	m := &migrate.Migrator{
+//	    Client:     client,
+//	    Dataset:    "analytics",
+//	    Migrations: migrations,
+//	    Params:     map[string]any{"$dataset": "analytics"},
+//	}
+//	applied, err := m.Up(ctx)
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/mevdschee/saferbq"
+	"google.golang.org/api/iterator"
+)
+
+// DefaultHistoryTable is the name of the history table when the Migrator
+// does not set one
+const DefaultHistoryTable = "schema_migrations"
+
+// ErrInvalidMigration is returned when a migration file is not named
+// correctly or a migration can't be applied or reverted.
+var ErrInvalidMigration = errors.New("invalid migration")
+
+// fileRegex matches the names of migration files
+var fileRegex = regexp.MustCompile(`^([0-9]+)_([A-Za-z0-9_]+)\.(up|down)\.sql$`)
+
+const (
+	// createHistorySQL creates the history table if it does not exist
+	createHistorySQL = "CREATE TABLE IF NOT EXISTS $dataset.$table (version INT64 NOT NULL, name STRING NOT NULL, applied_at TIMESTAMP NOT NULL)"
+	// selectHistorySQL reads the applied versions
+	selectHistorySQL = "SELECT version, applied_at FROM $dataset.$table ORDER BY version"
+	// insertHistorySQL records an applied version
+	insertHistorySQL = "INSERT INTO $dataset.$table (version, name, applied_at) VALUES (@version, @name, CURRENT_TIMESTAMP())"
+	// deleteHistorySQL removes a reverted version
+	deleteHistorySQL = "DELETE FROM $dataset.$table WHERE version = @version"
+)
+
+// Migration is a versioned schema change.
+type Migration struct {
+	// Version orders the migrations, it must be unique
+	Version int64
+	// Name describes the migration
+	Name string
+	// Up is the SQL that applies the migration
+	Up string
+	// Down is the SQL that reverts the migration (optional)
+	Down string
+}
+
+// String returns the version and name of the migration.
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Load reads the migrations from the .sql files in the root of fsys, sorted
+// by version. Files are named <version>_<name>.up.sql and (optionally)
+// <version>_<name>.down.sql, for example 0001_create_events.up.sql.
+//
+// Returns an error wrapping ErrInvalidMigration if a .sql file is not named
+// correctly, a version is used twice or a down file has no up file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, name := range names {
+		match := fileRegex.FindStringSubmatch(path.Base(name))
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s is not named <version>_<name>.(up|down).sql", ErrInvalidMigration, name)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidMigration, name, err)
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %s has no up migration", ErrInvalidMigration, m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator applies and reverts migrations and records them in the history
+// table.
+type Migrator struct {
+	// Client runs the migrations
+	Client *saferbq.Client
+	// Dataset is the dataset of the history table
+	Dataset string
+	// HistoryTable is the name of the history table (the default is
+	// DefaultHistoryTable)
+	HistoryTable string
+	// Migrations are the known migrations, see Load
+	Migrations []Migration
+	// Params are the values of the $identifier placeholders of the
+	// migrations; every migration is bound to the placeholders it uses
+	Params map[string]any
+}
+
+// Status is the state of a migration.
+type Status struct {
+	Migration
+	// Applied is set when the migration is recorded in the history table
+	Applied bool
+	// AppliedAt is the time the migration was applied
+	AppliedAt time.Time
+}
+
+// Step is a migration with its translated SQL, see Preview.
+type Step struct {
+	Migration
+	// SQL is the translated SQL of the up migration
+	SQL string
+}
+
+// Status returns the state of every migration, sorted by version. The
+// history table is created if it does not exist.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.Migrations))
+	for _, migration := range m.sorted() {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+	return statuses, nil
+}
+
+// Preview returns the translated SQL of the pending migrations, in the
+// order Up would apply them, without running them. The placeholders are
+// validated, so a preview fails for the same parameters that Up would.
+func (m *Migrator) Preview(ctx context.Context) ([]Step, error) {
+	pending, err := m.pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]Step, 0, len(pending))
+	for _, migration := range pending {
+		params, err := m.bind(migration.Up)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", migration, err)
+		}
+		sql, _, err := saferbq.Translate(migration.Up, params)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", migration, err)
+		}
+		steps = append(steps, Step{Migration: migration, SQL: sql})
+	}
+	return steps, nil
+}
+
+// Up applies the pending migrations in order of their version, and records
+// every applied migration in the history table.
+//
+// Returns the applied migrations, and an error if a migration fails; the
+// migrations before it stay applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := []Migration{}
+	for _, migration := range pending {
+		if err := m.exec(ctx, migration.Up); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", migration, err)
+		}
+		if err := m.record(ctx, insertHistorySQL, migration); err != nil {
+			return applied, fmt.Errorf("migration %s: failed to record: %w", migration, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down reverts the last applied migration and removes it from the history
+// table.
+//
+// Returns the reverted migration, or nil when no migration is applied.
+// Returns an error wrapping ErrInvalidMigration if the migration is unknown
+// or has no down migration.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+	last := slices.Max(slices.Collect(maps.Keys(applied)))
+	var migration *Migration
+	for _, candidate := range m.Migrations {
+		if candidate.Version == last {
+			migration = &candidate
+			break
+		}
+	}
+	if migration == nil {
+		return nil, fmt.Errorf("%w: applied version %d is unknown", ErrInvalidMigration, last)
+	}
+	if migration.Down == "" {
+		return nil, fmt.Errorf("%w: %s has no down migration", ErrInvalidMigration, migration)
+	}
+	if err := m.exec(ctx, migration.Down); err != nil {
+		return nil, fmt.Errorf("migration %s: %w", migration, err)
+	}
+	if err := m.record(ctx, deleteHistorySQL, *migration); err != nil {
+		return nil, fmt.Errorf("migration %s: failed to record: %w", migration, err)
+	}
+	return migration, nil
+}
+
+// sorted returns the migrations sorted by version.
+func (m *Migrator) sorted() []Migration {
+	migrations := append([]Migration(nil), m.Migrations...)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// pending returns the migrations that are not applied, sorted by version.
+func (m *Migrator) pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := []Migration{}
+	for _, migration := range m.sorted() {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// applied creates the history table if needed and returns the applied
+// versions with the time they were applied.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	create := m.history(createHistorySQL)
+	if _, err := create.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create history table: %w", err)
+	}
+	it, err := m.history(selectHistorySQL).Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history table: %w", err)
+	}
+	applied := map[int64]time.Time{}
+	for {
+		var row struct {
+			Version   int64     `bigquery:"version"`
+			AppliedAt time.Time `bigquery:"applied_at"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history table: %w", err)
+		}
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// record runs the insert or delete statement of the history table for the
+// migration.
+func (m *Migrator) record(ctx context.Context, sql string, migration Migration) error {
+	q := m.history(sql)
+	q.Parameters = append(q.Parameters, bigquery.QueryParameter{Name: "@version", Value: migration.Version})
+	if sql == insertHistorySQL {
+		q.Parameters = append(q.Parameters, bigquery.QueryParameter{Name: "@name", Value: migration.Name})
+	}
+	_, err := q.Exec(ctx)
+	return err
+}
+
+// history returns a query on the history table.
+func (m *Migrator) history(sql string) *saferbq.Query {
+	table := m.HistoryTable
+	if table == "" {
+		table = DefaultHistoryTable
+	}
+	q := m.Client.Query(sql)
+	q.SetParams(map[string]any{
+		"$dataset": saferbq.DatasetID(m.Dataset),
+		"$table":   saferbq.TableID(table),
+	})
+	return q
+}
+
+// exec binds the placeholders of the migration SQL and runs it.
+func (m *Migrator) exec(ctx context.Context, sql string) error {
+	params, err := m.bind(sql)
+	if err != nil {
+		return err
+	}
+	q := m.Client.Query(sql)
+	q.Parameters = params
+	_, err = q.Exec(ctx)
+	return err
+}
+
+// bind returns the parameters for the placeholders that the SQL uses.
+func (m *Migrator) bind(sql string) ([]bigquery.QueryParameter, error) {
+	info, err := saferbq.Describe(sql)
+	if err != nil {
+		return nil, err
+	}
+	params := []bigquery.QueryParameter{}
+	for _, names := range [][]string{info.Identifiers, info.Parameters} {
+		for _, name := range names {
+			if value, ok := m.Params[name]; ok {
+				params = append(params, bigquery.QueryParameter{Name: name, Value: value})
+			}
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,203 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/mevdschee/saferbq"
+	"github.com/mevdschee/saferbq/saferbqtest"
+)
+
+var historySchema = bigquery.Schema{
+	{Name: "version", Type: bigquery.IntegerFieldType},
+	{Name: "applied_at", Type: bigquery.TimestampFieldType},
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 2, Name: "add_index", Up: "ALTER TABLE $dataset.events ADD COLUMN source STRING", Down: "ALTER TABLE $dataset.events DROP COLUMN source"},
+		{Version: 1, Name: "create_events", Up: "CREATE TABLE $dataset.events (id INT64)", Down: "DROP TABLE $dataset.events"},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_source.up.sql":      {Data: []byte("ALTER TABLE $dataset.events ADD COLUMN source STRING")},
+		"0001_create_events.up.sql":   {Data: []byte("CREATE TABLE $dataset.events (id INT64)")},
+		"0001_create_events.down.sql": {Data: []byte("DROP TABLE $dataset.events")},
+		"README.md":                   {Data: []byte("not a migration")},
+	}
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Load() = %v, want 2 migrations", migrations)
+	}
+	first, second := migrations[0], migrations[1]
+	if first.Version != 1 || first.Name != "create_events" || first.Up == "" || first.Down != "DROP TABLE $dataset.events" {
+		t.Errorf("Load() first = %+v", first)
+	}
+	if second.Version != 2 || second.Name != "add_source" || second.Down != "" {
+		t.Errorf("Load() second = %+v", second)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"bad name", fstest.MapFS{"create_events.sql": {Data: []byte("SELECT 1")}}},
+		{"version used twice", fstest.MapFS{"1_a.up.sql": {Data: []byte("SELECT 1")}, "1_b.up.sql": {Data: []byte("SELECT 2")}}},
+		{"down without up", fstest.MapFS{"1_a.down.sql": {Data: []byte("SELECT 1")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); !errors.Is(err, ErrInvalidMigration) {
+				t.Errorf("Load() error = %v, want %v", err, ErrInvalidMigration)
+			}
+		})
+	}
+}
+
+func TestUp(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema, []bigquery.Value{1, "1704067200000000"})
+	m := &Migrator{Client: client.Client, Dataset: "analytics", Migrations: testMigrations(), Params: map[string]any{"$dataset": "analytics", "$unused": "x"}}
+
+	applied, err := m.Up(context.Background())
+	if err != nil {
+		t.Fatalf("Up() unexpected error: %v", err)
+	}
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("Up() = %v, want version 2 applied", applied)
+	}
+	want := []string{
+		"CREATE TABLE IF NOT EXISTS `analytics`.`schema_migrations` (version INT64 NOT NULL, name STRING NOT NULL, applied_at TIMESTAMP NOT NULL)",
+		"SELECT version, applied_at FROM `analytics`.`schema_migrations` ORDER BY version",
+		"ALTER TABLE `analytics`.events ADD COLUMN source STRING",
+		"INSERT INTO `analytics`.`schema_migrations` (version, name, applied_at) VALUES (@version, @name, CURRENT_TIMESTAMP())",
+	}
+	queries := client.Queries()
+	if len(queries) != len(want) {
+		t.Fatalf("Up() ran %d queries, want %d: %v", len(queries), len(want), queries)
+	}
+	for i, sql := range want {
+		if queries[i].SQL != sql {
+			t.Errorf("query %d = %q, want %q", i, queries[i].SQL, sql)
+		}
+	}
+	last := client.LastQuery()
+	if len(last.Parameters) != 2 || last.Parameters[0].Value != "2" || last.Parameters[1].Value != "add_index" {
+		t.Errorf("history parameters = %v, want version 2 and name add_index", last.Parameters)
+	}
+}
+
+func TestUpInvalidParameter(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema)
+	m := &Migrator{Client: client.Client, Dataset: "analytics", Migrations: testMigrations(), Params: map[string]any{"$dataset": "analytics; DROP"}}
+
+	applied, err := m.Up(context.Background())
+	if !errors.Is(err, saferbq.ErrIdentifierInvalidChars) {
+		t.Fatalf("Up() error = %v, want %v", err, saferbq.ErrIdentifierInvalidChars)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Up() = %v, want no applied migrations", applied)
+	}
+}
+
+func TestDown(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema, []bigquery.Value{1, "1704067200000000"}, []bigquery.Value{2, "1704153600000000"})
+	m := &Migrator{Client: client.Client, Dataset: "analytics", Migrations: testMigrations(), Params: map[string]any{"$dataset": "analytics"}}
+
+	reverted, err := m.Down(context.Background())
+	if err != nil {
+		t.Fatalf("Down() unexpected error: %v", err)
+	}
+	if reverted == nil || reverted.Version != 2 {
+		t.Fatalf("Down() = %v, want version 2", reverted)
+	}
+	queries := client.Queries()
+	if got := queries[len(queries)-2].SQL; got != "ALTER TABLE `analytics`.events DROP COLUMN source" {
+		t.Errorf("Down() SQL = %q", got)
+	}
+	if got := client.LastQuery().SQL; got != "DELETE FROM `analytics`.`schema_migrations` WHERE version = @version" {
+		t.Errorf("Down() history SQL = %q", got)
+	}
+}
+
+func TestDownNothingApplied(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema)
+	m := &Migrator{Client: client.Client, Dataset: "analytics", Migrations: testMigrations()}
+
+	reverted, err := m.Down(context.Background())
+	if err != nil || reverted != nil {
+		t.Errorf("Down() = %v, %v, want nil, nil", reverted, err)
+	}
+}
+
+func TestDownWithoutDownMigration(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema, []bigquery.Value{1, "1704067200000000"})
+	m := &Migrator{Client: client.Client, Dataset: "analytics", Migrations: []Migration{{Version: 1, Name: "a", Up: "SELECT 1"}}}
+
+	if _, err := m.Down(context.Background()); !errors.Is(err, ErrInvalidMigration) {
+		t.Errorf("Down() error = %v, want %v", err, ErrInvalidMigration)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema, []bigquery.Value{1, "1704067200000000"})
+	m := &Migrator{Client: client.Client, Dataset: "analytics", HistoryTable: "migrations", Migrations: testMigrations()}
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() unexpected error: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("Status() = %v, want 2 statuses", statuses)
+	}
+	if !statuses[0].Applied || !statuses[0].AppliedAt.Equal(time.Unix(1704067200, 0)) {
+		t.Errorf("Status() first = %v at %v, want applied at 2024-01-01", statuses[0].Applied, statuses[0].AppliedAt)
+	}
+	if statuses[1].Applied {
+		t.Errorf("Status() second = %+v, want pending", statuses[1])
+	}
+	if got := client.LastQuery().SQL; got != "SELECT version, applied_at FROM `analytics`.`migrations` ORDER BY version" {
+		t.Errorf("Status() SQL = %q", got)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	client := saferbqtest.NewFakeClient(t)
+	client.SetRows(historySchema)
+	m := &Migrator{Client: client.Client, Dataset: "analytics", Migrations: testMigrations(), Params: map[string]any{"$dataset": "analytics"}}
+
+	steps, err := m.Preview(context.Background())
+	if err != nil {
+		t.Fatalf("Preview() unexpected error: %v", err)
+	}
+	if len(steps) != 2 || steps[0].SQL != "CREATE TABLE `analytics`.events (id INT64)" || steps[1].SQL != "ALTER TABLE `analytics`.events ADD COLUMN source STRING" {
+		t.Errorf("Preview() = %+v", steps)
+	}
+	for _, q := range client.Queries() {
+		if q.SQL == steps[0].SQL {
+			t.Errorf("Preview() ran the migration %q", q.SQL)
+		}
+	}
+
+	m.Params = nil
+	if _, err := m.Preview(context.Background()); !errors.Is(err, saferbq.ErrIdentifierNotProvided) {
+		t.Errorf("Preview() error = %v, want %v", err, saferbq.ErrIdentifierNotProvided)
+	}
+}
@@ -0,0 +1,134 @@
+package saferbq
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+// Null is a NULL parameter value of the given type. BigQuery can't infer
+// the type of a nil value, so a nil value has to be passed as a Null with
+// a type hint instead:
+//
+//	q := client.Query("UPDATE $table SET deleted_at = @deleted_at WHERE id = @id")
+//	q.SetParams(map[string]any{
+//	    "$table":      "users",
+//	    "@deleted_at": saferbq.Null{Type: bigquery.TimestampFieldType},
+//	    "@id":         42,
+//	})
+//
+// Typed nil pointers, like a nil *string or *time.Time, don't need a hint:
+// they are passed as a NULL of the type they point to.
+type Null struct {
+	// Type is the BigQuery type of the NULL value
+	Type bigquery.FieldType
+}
+
+var (
+	typeOfTime      = reflect.TypeOf(time.Time{})
+	typeOfDate      = reflect.TypeOf(civil.Date{})
+	typeOfCivilTime = reflect.TypeOf(civil.Time{})
+	typeOfDateTime  = reflect.TypeOf(civil.DateTime{})
+	typeOfRat       = reflect.TypeOf(big.Rat{})
+)
+
+// parameterValue returns the value that is passed to BigQuery for the
+// parameter value: a Null or a nil pointer to a scalar becomes a NULL of
+// that type, and a non-nil pointer to a scalar the value it points to.
+// Returns ErrUntypedNull when the value is nil (use a Null instead), or a
+// Null of an unsupported type.
+func parameterValue(value any) (any, error) {
+	if value == nil {
+		return nil, ErrUntypedNull
+	}
+	if null, ok := value.(Null); ok {
+		return nullValue(null.Type)
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Pointer {
+		return value, nil
+	}
+	fieldType, ok := scalarFieldType(v.Type().Elem())
+	if !ok {
+		// Pointers to structs are passed as STRUCT parameters
+		return value, nil
+	}
+	if v.IsNil() {
+		return nullValue(fieldType)
+	}
+	if fieldType == bigquery.NumericFieldType {
+		// BigQuery expects a *big.Rat for NUMERIC
+		return value, nil
+	}
+	return v.Elem().Interface(), nil
+}
+
+// scalarFieldType returns the BigQuery type of a Go scalar type, or false
+// when the type is not a scalar.
+func scalarFieldType(t reflect.Type) (bigquery.FieldType, bool) {
+	switch t {
+	case typeOfTime:
+		return bigquery.TimestampFieldType, true
+	case typeOfDate:
+		return bigquery.DateFieldType, true
+	case typeOfCivilTime:
+		return bigquery.TimeFieldType, true
+	case typeOfDateTime:
+		return bigquery.DateTimeFieldType, true
+	case typeOfRat:
+		return bigquery.NumericFieldType, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return bigquery.StringFieldType, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return bigquery.IntegerFieldType, true
+	case reflect.Float32, reflect.Float64:
+		return bigquery.FloatFieldType, true
+	case reflect.Bool:
+		return bigquery.BooleanFieldType, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return bigquery.BytesFieldType, true
+		}
+	}
+	return "", false
+}
+
+// nullValue returns the NULL parameter value of the BigQuery type.
+func nullValue(fieldType bigquery.FieldType) (any, error) {
+	switch fieldType {
+	case bigquery.StringFieldType:
+		return bigquery.NullString{}, nil
+	case bigquery.IntegerFieldType:
+		return bigquery.NullInt64{}, nil
+	case bigquery.FloatFieldType:
+		return bigquery.NullFloat64{}, nil
+	case bigquery.BooleanFieldType:
+		return bigquery.NullBool{}, nil
+	case bigquery.TimestampFieldType:
+		return bigquery.NullTimestamp{}, nil
+	case bigquery.DateFieldType:
+		return bigquery.NullDate{}, nil
+	case bigquery.TimeFieldType:
+		return bigquery.NullTime{}, nil
+	case bigquery.DateTimeFieldType:
+		return bigquery.NullDateTime{}, nil
+	case bigquery.GeographyFieldType:
+		return bigquery.NullGeography{}, nil
+	case bigquery.JSONFieldType:
+		return bigquery.NullJSON{}, nil
+	case bigquery.NumericFieldType, bigquery.BigNumericFieldType, bigquery.BytesFieldType, bigquery.IntervalFieldType:
+		// There is no Null type for these, an invalid NullString sends no value
+		return &bigquery.QueryParameterValue{
+			Type:  bigquery.StandardSQLDataType{TypeKind: string(fieldType)},
+			Value: bigquery.NullString{},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w of unsupported type %q", ErrUntypedNull, fieldType)
+}
@@ -0,0 +1,126 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+func TestParameterValue(t *testing.T) {
+	s, n, ts := "a", int32(1), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rat := big.NewRat(1, 2)
+	type point struct{ X int }
+
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{"scalar", 1, 1},
+		{"string pointer", &s, "a"},
+		{"int pointer", &n, int32(1)},
+		{"time pointer", &ts, ts},
+		{"rat pointer", rat, rat},
+		{"struct pointer", &point{X: 1}, &point{X: 1}},
+		{"nil string", (*string)(nil), bigquery.NullString{}},
+		{"nil int", (*int)(nil), bigquery.NullInt64{}},
+		{"nil float", (*float64)(nil), bigquery.NullFloat64{}},
+		{"nil bool", (*bool)(nil), bigquery.NullBool{}},
+		{"nil time", (*time.Time)(nil), bigquery.NullTimestamp{}},
+		{"nil date", (*civil.Date)(nil), bigquery.NullDate{}},
+		{"nil civil time", (*civil.Time)(nil), bigquery.NullTime{}},
+		{"nil datetime", (*civil.DateTime)(nil), bigquery.NullDateTime{}},
+		{"nil rat", (*big.Rat)(nil), &bigquery.QueryParameterValue{
+			Type:  bigquery.StandardSQLDataType{TypeKind: "NUMERIC"},
+			Value: bigquery.NullString{},
+		}},
+		{"nil bytes", (*[]byte)(nil), &bigquery.QueryParameterValue{
+			Type:  bigquery.StandardSQLDataType{TypeKind: "BYTES"},
+			Value: bigquery.NullString{},
+		}},
+		{"null string", Null{Type: bigquery.StringFieldType}, bigquery.NullString{}},
+		{"null geography", Null{Type: bigquery.GeographyFieldType}, bigquery.NullGeography{}},
+		{"null json", Null{Type: bigquery.JSONFieldType}, bigquery.NullJSON{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parameterValue(tt.value)
+			if err != nil {
+				t.Fatalf("parameterValue() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parameterValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []any{nil, Null{}, Null{Type: bigquery.RecordFieldType}} {
+		if _, err := parameterValue(value); !errors.Is(err, ErrUntypedNull) {
+			t.Errorf("parameterValue(%#v) error = %v, want ErrUntypedNull", value, err)
+		}
+	}
+}
+
+func TestNullParameters(t *testing.T) {
+	var deletedAt *time.Time
+	q := (&Client{}).Query("UPDATE $table SET deleted_at = @deleted_at, note = @note WHERE id = @id")
+	q.SetParams(map[string]any{
+		"$table":      "users",
+		"@deleted_at": deletedAt,
+		"@note":       Null{Type: bigquery.StringFieldType},
+		"@id":         42,
+	})
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	want := []bigquery.QueryParameter{
+		{Name: "deleted_at", Value: bigquery.NullTimestamp{}},
+		{Name: "id", Value: 42},
+		{Name: "note", Value: bigquery.NullString{}},
+	}
+	if !reflect.DeepEqual(q.Parameters, want) {
+		t.Errorf("Parameters = %v, want %v", q.Parameters, want)
+	}
+
+	// Untyped nil values are reported with their name or position
+	q = (&Client{}).Query("SELECT * FROM t WHERE a = @a")
+	q.SetParams(map[string]any{"@a": nil})
+	err := q.translate()
+	if !errors.Is(err, ErrUntypedNull) || !strings.Contains(err.Error(), "@a") {
+		t.Errorf("translate() error = %v, want ErrUntypedNull for @a", err)
+	}
+	q = (&Client{}).Query("SELECT * FROM t WHERE a = ? AND b = ?")
+	q.SetPositionalParams(1, nil)
+	err = q.translate()
+	var te *TranslationError
+	if !errors.As(err, &te) || !errors.Is(err, ErrUntypedNull) || te.Offset != 36 {
+		t.Errorf("translate() error = %v, want ErrUntypedNull at offset 36", err)
+	}
+}
+
+func TestNullNumericParameter(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	q := client.Query("UPDATE $table SET price = @price WHERE true")
+	q.SetParams(map[string]any{"$table": "products", "@price": (*big.Rat)(nil)})
+	job, err := q.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	parameters := fake.jobs[job.ID()]["query"].(map[string]any)["queryParameters"].([]any)
+	parameter := parameters[0].(map[string]any)
+	if got := parameter["parameterType"].(map[string]any)["type"]; got != "NUMERIC" {
+		t.Errorf("parameter type = %v, want NUMERIC", got)
+	}
+	if value := parameter["parameterValue"].(map[string]any); value["value"] != nil {
+		t.Errorf("parameter value = %v, want NULL", value)
+	}
+}
@@ -0,0 +1,98 @@
+package saferbq
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// optionNameRegex matches the names of DDL options
+var optionNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Options renders the options as an OPTIONS (...) clause of a DDL
+// statement, sorted by name, for use as the value of a $identifier. The
+// clause is empty when there are no options. Option values can't be query
+// parameters, so they are rendered as literals:
+//
+//   - strings are escaped as string literals
+//   - time.Time values are rendered as UTC timestamp literals
+//   - time.Duration values are rendered as INTERVAL literals
+//   - map[string]string values are rendered as label lists, with the keys
+//     and values validated by the BigQuery label rules
+//   - Fragment values are injected as is
+//   - booleans, numbers, nil and slices are rendered as literals
+//
+// Example:
+//
+//	options, err := saferbq.Options(map[string]any{
+//		"description":          "events of " + tenant,
+//		"expiration_timestamp": time.Now().AddDate(0, 1, 0),
+//		"labels":               map[string]string{"tenant": tenant},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	q := client.Query("ALTER TABLE $table SET $options")
+//	q.SetParams(map[string]any{"$table": "analytics.events", "$options": options})
+//
+// Returns an error wrapping ErrInvalidDDL if an option name is not valid,
+// ErrInvalidLabel if a label is not valid, or ErrInvalidLiteral if a value
+// can't be rendered.
+func Options(options map[string]any) (Fragment, error) {
+	if len(options) == 0 {
+		return Fragment{}, nil
+	}
+	rendered := make([]string, 0, len(options))
+	for _, name := range sortedKeys(options) {
+		if !optionNameRegex.MatchString(name) {
+			return Fragment{}, fmt.Errorf("%w: %q is not a valid option name", ErrInvalidDDL, name)
+		}
+		value, err := renderOptionValue(options[name])
+		if err != nil {
+			return Fragment{}, fmt.Errorf("option %s: %w", name, err)
+		}
+		rendered = append(rendered, name+" = "+value)
+	}
+	return Fragment{sql: "OPTIONS (" + strings.Join(rendered, ", ") + ")"}, nil
+}
+
+// renderOptionValue renders the value of a DDL option as a literal.
+func renderOptionValue(value any) (string, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return intervalLiteral(v), nil
+	case map[string]string:
+		return labelsLiteral(v)
+	case Fragment:
+		return v.sql, nil
+	default:
+		return renderLiteral(value)
+	}
+}
+
+// labelsLiteral validates the labels and renders them as a list of
+// key-value pairs, sorted by key.
+func labelsLiteral(labels map[string]string) (string, error) {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range sortedKeys(labels) {
+		if err := ValidateLabel(key, labels[key]); err != nil {
+			return "", err
+		}
+		pairs = append(pairs, "("+EscapeStringLiteral(key)+", "+EscapeStringLiteral(labels[key])+")")
+	}
+	return "[" + strings.Join(pairs, ", ") + "]", nil
+}
+
+// intervalLiteral renders the duration as an INTERVAL literal of hours,
+// minutes and (fractional) seconds.
+func intervalLiteral(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	hours, minutes := int64(d/time.Hour), int64(d%time.Hour/time.Minute)
+	seconds := strconv.FormatFloat((d % time.Minute).Seconds(), 'f', -1, 64)
+	return fmt.Sprintf("INTERVAL '%s%d:%d:%s' HOUR TO SECOND", sign, hours, minutes, seconds)
+}
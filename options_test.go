@@ -0,0 +1,69 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  map[string]any
+		expected string
+		err      error
+	}{
+		{"empty", nil, "", nil},
+		{"table options", map[string]any{
+			"description":          "it's a table",
+			"expiration_timestamp": time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600)),
+			"labels":               map[string]string{"team": "data", "env": "prod"},
+		}, "OPTIONS (description = 'it\\'s a table', expiration_timestamp = TIMESTAMP '2024-06-01 10:00:00+00:00', " +
+			"labels = [('env', 'prod'), ('team', 'data')])", nil},
+		{"materialized view options", map[string]any{
+			"enable_refresh":           true,
+			"refresh_interval_minutes": 30,
+			"max_staleness":            90*time.Minute + 1500*time.Millisecond,
+		}, "OPTIONS (enable_refresh = TRUE, max_staleness = INTERVAL '1:30:1.5' HOUR TO SECOND, refresh_interval_minutes = 30)", nil},
+		{"list and fragment", map[string]any{
+			"uris":   []string{"gs://a/b.csv"},
+			"format": Fragment{sql: "'CSV'"},
+		}, "OPTIONS (format = 'CSV', uris = ['gs://a/b.csv'])", nil},
+		{"negative interval", map[string]any{"max_staleness": -time.Hour}, "OPTIONS (max_staleness = INTERVAL '-1:0:0' HOUR TO SECOND)", nil},
+		{"invalid name", map[string]any{"description = 'x', labels": "y"}, "", ErrInvalidDDL},
+		{"invalid label key", map[string]any{"labels": map[string]string{"Team": "data"}}, "", ErrInvalidLabel},
+		{"invalid label value", map[string]any{"labels": map[string]string{"team": "data')]) --"}}, "", ErrInvalidLabel},
+		{"unsupported value", map[string]any{"description": struct{}{}}, "", ErrInvalidLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Options(tt.options)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Options() error = %v, want %v", err, tt.err)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("Options() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOptionsInTemplate(t *testing.T) {
+	options, err := Options(map[string]any{"description": "events"})
+	if err != nil {
+		t.Fatalf("Options() unexpected error: %v", err)
+	}
+	sqlOut, _, err := translate("ALTER TABLE $table SET $options", []bigquery.QueryParameter{
+		{Name: "$table", Value: "analytics.events"},
+		{Name: "$options", Value: options},
+	})
+	if err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	if want := "ALTER TABLE `analytics`.`events` SET OPTIONS (description = 'events')"; sqlOut != want {
+		t.Errorf("translate() = %q, want %q", sqlOut, want)
+	}
+}
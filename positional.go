@@ -0,0 +1,134 @@
+package saferbq
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// WithNamedPositionals converts the ? placeholders of queries into generated
+// named parameters (@p1, @p2, ...) during translation, and the positional
+// parameter values into the matching named parameters. Positional queries
+// can then be combined with features that require named parameters, like
+// scripts and sessions, and validation errors reference stable names.
+//
+// As all parameters are named after the conversion, ? placeholders and @
+// parameters may be mixed in a query. Generated names that are already used
+// by the query get an underscore prefix.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithNamedPositionals())
+//
+//	q := client.Query("SELECT * FROM $table WHERE id = ? AND status = ?")
+//	q.SetParams(map[string]any{"$table": "users"})
+//	q.SetPositionalParams(1, "active")
+//	// Results: SELECT * FROM `users` WHERE id = @p1 AND status = @p2
+func WithNamedPositionals() Option {
+	return func(c *Client) {
+		c.namedPositionals = true
+	}
+}
+
+// parseQuery parses the SQL like parse, after replacing its ? placeholders
+// with generated named parameters when named is set. The template keeps
+// the SQL before the replacement as its source, so prepared statements are
+// parsed only once.
+func parseQuery(sql string, named bool) (*template, error) {
+	source := sql
+	var names []string
+	if named {
+		sql, names = namePlaceholders(sql)
+	}
+	t, err := parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	t.source, t.named, t.positionalNames = source, named, names
+	return t, nil
+}
+
+// namePlaceholders replaces the ? placeholders in the SQL with generated
+// named parameters and returns the SQL and the generated names.
+func namePlaceholders(sql string) (string, []string) {
+	tokens := scan(sql)
+	taken := map[string]bool{}
+	placeholders := 0
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenNamedParam:
+			taken[tok.text] = true
+		case tokenPositionalParam:
+			placeholders++
+		}
+	}
+	if placeholders == 0 {
+		return sql, nil
+	}
+	names := generateNames(0, placeholders, taken)
+	var result strings.Builder
+	result.Grow(len(sql))
+	n := 0
+	for _, tok := range tokens {
+		if tok.kind == tokenPositionalParam {
+			result.WriteString(names[n])
+			n++
+			continue
+		}
+		result.WriteString(tok.text)
+	}
+	return result.String(), names
+}
+
+// generateNames returns count names @p<n> that are not taken, numbered
+// from start+1.
+func generateNames(start, count int, taken map[string]bool) []string {
+	names := make([]string, count)
+	for i := range names {
+		name := fmt.Sprintf("@p%d", start+i+1)
+		for taken[name] {
+			name = "@_" + name[1:]
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// namePositionals names the positional parameters after the placeholders
+// of the template. Values without a placeholder get a generated name as
+// well, so they are reported as unused. It returns the parameters and the
+// generated names.
+func (t *template) namePositionals(params []bigquery.QueryParameter) ([]bigquery.QueryParameter, []string) {
+	values := 0
+	for _, p := range params {
+		if p.Name == "" {
+			values++
+		}
+	}
+	if values == 0 {
+		return params, nil
+	}
+	names := t.positionalNames
+	if values > len(names) {
+		taken := map[string]bool{}
+		for name := range t.parameters {
+			taken[name] = true
+		}
+		for _, p := range params {
+			taken[p.Name] = true
+		}
+		names = append(slices.Clip(names), generateNames(len(names), values-len(names), taken)...)
+	}
+	named := make([]bigquery.QueryParameter, 0, len(params))
+	n := 0
+	for _, p := range params {
+		if p.Name == "" {
+			p.Name = names[n]
+			n++
+		}
+		named = append(named, p)
+	}
+	return named, names[:values]
+}
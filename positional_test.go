@@ -0,0 +1,87 @@
+package saferbq
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestWithNamedPositionals(t *testing.T) {
+	client := (&Client{}).Configure(WithNamedPositionals())
+
+	q := client.Query("SELECT * FROM $table WHERE id = ? AND status = ? AND note != '?'")
+	q.SetParams(map[string]any{"$table": "users"})
+	q.SetPositionalParams(1, "active")
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	if want := "SELECT * FROM `users` WHERE id = @p1 AND status = @p2 AND note != '?'"; q.Q != want {
+		t.Errorf("SQL = %q, want %q", q.Q, want)
+	}
+	want := []bigquery.QueryParameter{{Name: "p1", Value: 1}, {Name: "p2", Value: "active"}}
+	if !slices.Equal(q.Parameters, want) {
+		t.Errorf("Parameters = %v, want %v", q.Parameters, want)
+	}
+}
+
+func TestWithNamedPositionalsMixed(t *testing.T) {
+	client := (&Client{}).Configure(WithNamedPositionals())
+
+	// Generated names don't collide with the named parameters of the query
+	q := client.Query("SELECT * FROM t WHERE id = ? AND parent = @p1")
+	q.SetParams(map[string]any{"@p1": 2})
+	q.SetPositionalParams(1)
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	if want := "SELECT * FROM t WHERE id = @_p1 AND parent = @p1"; q.Q != want {
+		t.Errorf("SQL = %q, want %q", q.Q, want)
+	}
+}
+
+func TestWithNamedPositionalsErrors(t *testing.T) {
+	client := (&Client{}).Configure(WithNamedPositionals())
+
+	q := client.Query("SELECT * FROM t WHERE id = ? AND status = ?")
+	q.SetPositionalParams(1)
+	err := q.translate()
+	if !errors.Is(err, ErrParameterNotProvided) || !strings.Contains(err.Error(), "@p2 at line 1, col 45") {
+		t.Errorf("translate() error = %v, want @p2 not provided", err)
+	}
+
+	q = client.Query("SELECT * FROM t WHERE id = ?")
+	q.SetPositionalParams(1, 2)
+	if err := q.translate(); !errors.Is(err, ErrParameterNotFound) || !strings.Contains(err.Error(), "@p2") {
+		t.Errorf("translate() error = %v, want @p2 not found", err)
+	}
+}
+
+func TestWithNamedPositionalsPrepared(t *testing.T) {
+	client := (&Client{}).Configure(WithNamedPositionals())
+	stmt, err := client.Prepare("SELECT * FROM t WHERE id = ?")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+	if got := stmt.SQL(); got != "SELECT * FROM t WHERE id = ?" {
+		t.Errorf("SQL() = %q, want the original SQL", got)
+	}
+	for _, id := range []int{1, 2} {
+		q := stmt.Query(bigquery.QueryParameter{Value: id})
+		if err := q.translate(); err != nil {
+			t.Fatalf("translate() unexpected error: %v", err)
+		}
+		// The prepared template is reused instead of parsed again
+		if q.template != stmt.template {
+			t.Error("translate() parsed the prepared statement again")
+		}
+		if want := "SELECT * FROM t WHERE id = @p1"; q.Q != want {
+			t.Errorf("SQL = %q, want %q", q.Q, want)
+		}
+		if want := []bigquery.QueryParameter{{Name: "p1", Value: id}}; !slices.Equal(q.Parameters, want) {
+			t.Errorf("Parameters = %v, want %v", q.Parameters, want)
+		}
+	}
+}
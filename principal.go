@@ -0,0 +1,26 @@
+package saferbq
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// principalRegex matches IAM members: users, groups and service accounts
+// by email address, domains, and all (authenticated) users
+var principalRegex = regexp.MustCompile(`^((user|group|serviceAccount):[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}|domain:[a-z0-9-]+(\.[a-z0-9-]+)*\.[a-z]{2,}|allUsers|allAuthenticatedUsers)$`)
+
+// validatePrincipal checks that the principal is an IAM member, such as
+// user:alice@example.com, group:admins@example.com,
+// serviceAccount:etl@my-project.iam.gserviceaccount.com or domain:example.com.
+//
+// Returns an error wrapping ErrInvalidPrincipal if it is not. When redact is
+// set, the principal is left out of the error (see RedactValuesInErrors).
+func validatePrincipal(principal string, redact bool) error {
+	if !principalRegex.MatchString(principal) {
+		if redact {
+			return redactValue(ErrInvalidPrincipal, "principal")
+		}
+		return fmt.Errorf("%w: %q is not a valid IAM member", ErrInvalidPrincipal, principal)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePrincipal(t *testing.T) {
+	tests := []struct {
+		principal string
+		valid     bool
+	}{
+		{"user:alice@example.com", true},
+		{"group:sales-emea@example.co.uk", true},
+		{"serviceAccount:etl@my-project.iam.gserviceaccount.com", true},
+		{"domain:example.com", true},
+		{"allUsers", true},
+		{"allAuthenticatedUsers", true},
+		{"alice@example.com", false},
+		{"user:alice", false},
+		{"admin:alice@example.com", false},
+		{"user:alice@example.com') FILTER USING (TRUE) --", false},
+		{"user:alice@example.com\n", false},
+		{"domain:Example.com", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		err := validatePrincipal(tt.principal, false)
+		if tt.valid && err != nil {
+			t.Errorf("validatePrincipal(%q) unexpected error: %v", tt.principal, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidPrincipal) {
+			t.Errorf("validatePrincipal(%q) error = %v, want %v", tt.principal, err, ErrInvalidPrincipal)
+		}
+	}
+}
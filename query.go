@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"cloud.google.com/go/bigquery"
 )
@@ -35,6 +38,9 @@ var (
 	// ErrIdentifierInvalidChars is returned when an identifier contains invalid characters.
 	ErrIdentifierInvalidChars = errors.New("identifier contains invalid characters")
 
+	// ErrIdentifierNotAllowed is returned when an identifier value is not one of the allowed values.
+	ErrIdentifierNotAllowed = errors.New("identifier is not allowed")
+
 	// ErrNotEnoughPositionalParams is returned when there are fewer positional parameters provided than required.
 	ErrNotEnoughPositionalParams = errors.New("not enough positional parameters")
 
@@ -46,6 +52,66 @@ var (
 
 	// ErrEmptySQL is returned when the query SQL is empty.
 	ErrEmptySQL = errors.New("query SQL cannot be empty")
+
+	// ErrQueryTooExpensive is returned when the estimated bytes processed exceed the configured maximum.
+	ErrQueryTooExpensive = errors.New("query is too expensive")
+
+	// ErrDryRunFailed is returned when a dry run does not return statistics.
+	ErrDryRunFailed = errors.New("dry run failed")
+
+	// ErrJobFailed is returned when a BigQuery job completed with an error.
+	ErrJobFailed = errors.New("job failed")
+
+	// ErrInvalidLane is returned when a query is submitted through an unknown execution lane.
+	ErrInvalidLane = errors.New("invalid execution lane")
+
+	// ErrInvalidSamplePercent is returned when a sample percentage is not in the range (0, 100].
+	ErrInvalidSamplePercent = errors.New("invalid sample percentage")
+
+	// ErrInvalidSweep is returned when a table sweep has missing or invalid fields.
+	ErrInvalidSweep = errors.New("invalid table sweep")
+
+	// ErrParameterConflict is returned when statements of a transaction use the same named parameter with different values.
+	ErrParameterConflict = errors.New("parameter has conflicting values")
+
+	// ErrDuplicateParameter is returned when the params slice contains a parameter name more than once.
+	ErrDuplicateParameter = errors.New("duplicate parameter")
+
+	// ErrInvalidInteger is returned when an integer literal is negative.
+	ErrInvalidInteger = errors.New("invalid integer literal")
+
+	// ErrInvalidURI is returned when a Cloud Storage URI is not a valid gs:// URI.
+	ErrInvalidURI = errors.New("invalid Cloud Storage URI")
+
+	// ErrInvalidFormat is returned when a data format is not supported for the operation.
+	ErrInvalidFormat = errors.New("unsupported data format")
+
+	// ErrInvalidDDL is returned when a DDL builder is given an invalid definition.
+	ErrInvalidDDL = errors.New("invalid DDL definition")
+
+	// ErrInvalidLiteral is returned when a parameter value can't be rendered as a SQL literal.
+	ErrInvalidLiteral = errors.New("value can't be rendered as a literal")
+
+	// ErrInvalidPrincipal is returned when a principal is not a valid IAM member.
+	ErrInvalidPrincipal = errors.New("invalid principal")
+
+	// ErrInvalidLabel is returned when a label key or value does not follow the BigQuery label rules.
+	ErrInvalidLabel = errors.New("invalid label")
+
+	// ErrNoRows is returned by ReadRow when the query returns no rows.
+	ErrNoRows = errors.New("query returned no rows")
+
+	// ErrTooManyRows is returned by ReadRow when the query returns more than one row.
+	ErrTooManyRows = errors.New("query returned more than one row")
+
+	// ErrNotRun is returned by Stats when the query has not been run.
+	ErrNotRun = errors.New("query has not been run")
+
+	// ErrScriptVariable is returned when a named parameter that is not provided refers to a declared scripting variable.
+	ErrScriptVariable = errors.New("parameter refers to a script variable")
+
+	// ErrUntypedNull is returned when a parameter value is nil, as BigQuery can't infer the type of a NULL.
+	ErrUntypedNull = errors.New("parameter value is an untyped NULL")
 )
 
 // Query represents a BigQuery query with dollar-sign parameter support.
@@ -56,13 +122,33 @@ var (
 type Query struct {
 	bigquery.Query
 	originalSQL string
+	// template is the pre-parsed SQL when the query was created from a Stmt
+	template *template
+	// positionalNames are the names (with @) that WithNamedPositionals
+	// generated for the positional parameters during translation
+	positionalNames []string
+	// client is the client that created the query (may be nil)
+	client *Client
+	// translated is set once the SQL and parameters have been translated
+	translated bool
+	// Lane is the execution lane the query is submitted through.
+	// The zero value is LaneInteractive.
+	Lane Lane
+	// retryable decides which errors are retried (nil = transient errors)
+	retryable func(err error) bool
+	// quoteStyle overrides the quote style of the client (nil = client style)
+	quoteStyle *QuoteStyle
+	// idempotencyKey derives the job ID from the translated query when set
+	idempotencyKey string
+	// job is the last job that ran the query (nil before Run or Read)
+	job *bigquery.Job
+	// timeout bounds Run and Read of the query (0 is unbounded)
+	timeout time.Duration
 }
 
 var (
 	// Regex to find $identifier parameters
 	identifierParamRegex = regexp.MustCompile(`\$[a-zA-Z_][a-zA-Z0-9_]*`)
-	// Regex to find @named parameters
-	namedParamRegex = regexp.MustCompile(`@[a-zA-Z_][a-zA-Z0-9_]*`)
 )
 
 const (
@@ -79,162 +165,625 @@ const (
 	questionMark = '?'
 )
 
-// translate converts dollar-sign parameters to BigQuery's native syntax.
-// It performs the following transformations:
-//   - $identifier parameters are validated and replaced with backtick-quoted values
-//   - @parameter names have the @ prefix removed for BigQuery compatibility
-//   - ? positional parameters are passed through unchanged
+// template is a parsed query that can be bound to parameters multiple times.
+// It is immutable after parsing, so it is safe for concurrent use.
+type template struct {
+	// sql is the original SQL of the template
+	sql string
+	// source is the SQL before the ? placeholders were replaced with named
+	// parameters (the same as sql without WithNamedPositionals)
+	source string
+	// named is set when the ? placeholders were replaced
+	named bool
+	// positionalNames are the names of the replaced ? placeholders, in order
+	positionalNames []string
+	// segments is the SQL split around $identifier references, the
+	// references themselves are stored at the odd indexes
+	segments []string
+	// identifiers contains all $identifier names found in the SQL
+	identifiers map[string]bool
+	// parameters contains all @parameter names found in the SQL
+	parameters map[string]bool
+	// positionals contains the byte offsets of the ? positional parameters in the SQL
+	positionals []int
+	// offsets contains the byte offset of the first occurrence of every
+	// $identifier and @parameter in the SQL
+	offsets map[string]int
+	// variables contains the (lower case) names of the scripting variables
+	// declared in the SQL with the byte offset of their declaration
+	variables map[string]int
+}
+
+// parse locates all parameters in the SQL and validates the parts of the
+// query that do not depend on parameter values:
+//   - The SQL is not empty
+//   - Positional and named parameters are not mixed
 //
-// The function validates:
+// Returns the parsed template or a validation error.
+func parse(sql string) (*template, error) {
+	// Validate non-empty SQL
+	if sql == "" {
+		return nil, ErrEmptySQL
+	}
+	t := &template{
+		sql:         sql,
+		source:      sql,
+		identifiers: map[string]bool{},
+		parameters:  map[string]bool{},
+		offsets:     map[string]int{},
+	}
+	// Find all parameters in the SQL and split the SQL around the identifiers,
+	// parameters in literals and comments are ignored
+	var segment strings.Builder
+	tokens := scan(sql)
+	t.variables = declaredVariables(tokens)
+	for _, tok := range tokens {
+		if _, seen := t.offsets[tok.text]; !seen && (tok.kind == tokenNamedParam || tok.kind == tokenIdentifierParam) {
+			t.offsets[tok.text] = tok.offset
+		}
+		switch tok.kind {
+		case tokenNamedParam:
+			t.parameters[tok.text] = true
+			segment.WriteString(tok.text)
+		case tokenPositionalParam:
+			t.positionals = append(t.positionals, tok.offset)
+			segment.WriteString(tok.text)
+		case tokenIdentifierParam:
+			t.segments = append(t.segments, segment.String(), tok.text)
+			t.identifiers[tok.text] = true
+			segment.Reset()
+		case tokenDollarEscape:
+			// $$ is a literal dollar sign, as in partition decorators
+			segment.WriteByte(dollarSign)
+		default:
+			segment.WriteString(tok.text)
+		}
+	}
+	t.segments = append(t.segments, segment.String())
+	// Check for mixing of positional and named parameters
+	if len(t.parameters) > 0 && len(t.positionals) > 0 {
+		return nil, &TranslationError{Kind: PositionalParameter, OriginalSQL: sql, Offset: t.positionals[0], Err: ErrMixedParameterTypes}
+	}
+	return t, nil
+}
+
+// bind validates the parameters against the template and returns the SQL with
+// all $identifier references replaced by their backtick-quoted values, and the
+// parameters to pass on to BigQuery.
+//
+// The function validates the following, and returns all problems that were
+// found as a joined error:
 //   - All parameters in SQL are provided in params
 //   - All provided parameters are used in SQL
+//   - No parameter is provided more than once
 //   - Identifiers contain only valid characters
 //   - Identifiers don't exceed 1024 bytes
 //   - Positional parameter counts match
 //
-// Returns the transformed SQL, processed parameters, and any validation error.
-func translate(sql string, params []bigquery.QueryParameter) (string, []bigquery.QueryParameter, error) {
-	// Validate non-empty SQL
-	if sql == "" {
-		return "", nil, ErrEmptySQL
+// $identifier values that are a *Query are inlined as a subquery and their
+// named parameters are merged into the returned parameters, see Query.inline.
+//
+// The options select the quote style of dotted paths and whether identifier
+// validation errors include the value.
+func (t *template) bind(params []bigquery.QueryParameter, opts bindOptions) (string, []bigquery.QueryParameter, error) {
+	// All problems are collected, so they can be fixed at once
+	var errs []error
+	fail := func(kind ParameterKind, name string, offset int, err error) {
+		errs = append(errs, &TranslationError{ParamName: name, Kind: kind, OriginalSQL: t.sql, Offset: offset, Err: err})
 	}
 	// Build parameters and identifiers map
 	parameters := map[string]bigquery.QueryParameter{}
 	identifiers := map[string]any{}
 	allParameters := []bigquery.QueryParameter{}
 	positionalParameterCount := 0
+	seen := map[string]bool{}
 	for _, p := range params {
 		paramName := p.Name
 		if len(paramName) > 0 {
+			if seen[paramName] {
+				fail(parameterKind(paramName), paramName, t.offset(paramName), fmt.Errorf("%w: %s", ErrDuplicateParameter, paramName))
+				continue
+			}
+			seen[paramName] = true
 			switch paramName[0] {
 			case atSign: // Named parameter
-				p.Name = paramName[1:]
+				value, err := parameterValue(p.Value)
+				if err != nil {
+					fail(NamedParameter, paramName, t.offset(paramName), fmt.Errorf("%w: %s", err, paramName))
+					continue
+				}
+				p.Name, p.Value = paramName[1:], value
 				parameters[paramName] = p
 				allParameters = append(allParameters, p)
 			case dollarSign: // Identifier parameter
 				identifiers[paramName] = p.Value
 			default:
-				return "", nil, fmt.Errorf("%w: %s must start with @ or $", ErrInvalidParameterName, paramName)
+				fail("", paramName, -1, fmt.Errorf("%w: %s must start with @ or $", ErrInvalidParameterName, paramName))
 			}
 		} else {
 			// Positional parameter
 			positionalParameterCount++
+			value, err := parameterValue(p.Value)
+			if err != nil {
+				fail(PositionalParameter, "", t.positional(positionalParameterCount), fmt.Errorf("%w: positional parameter %d", err, positionalParameterCount))
+				continue
+			}
+			p.Value = value
 			allParameters = append(allParameters, p)
 		}
 	}
-	// Find all identifiers in the SQL
-	identifiersInSql := map[string]bool{}
-	matches := identifierParamRegex.FindAllStringSubmatch(sql, -1)
-	for _, match := range matches {
-		identifiersInSql[match[0]] = true
-	}
-	// Find all parameters in the SQL (with @ prefix)
-	parametersInSql := map[string]bool{}
-	matches = namedParamRegex.FindAllStringSubmatch(sql, -1)
-	for _, match := range matches {
-		// Store with @ prefix to match the parameters map keys
-		parametersInSql[match[0]] = true
+	// Detect parameters not present in the original SQL
+	for _, paramName := range sortedKeys(parameters) {
+		if _, exists := t.parameters[paramName]; !exists {
+			fail(NamedParameter, paramName, -1, fmt.Errorf("%w: %s", ErrParameterNotFound, paramName))
+		}
 	}
-	// Detect parameters not present in the original SQL and return error
-	for paramName := range parameters {
-		if _, exists := parametersInSql[paramName]; !exists {
-			return "", nil, fmt.Errorf("%w: %s", ErrParameterNotFound, paramName)
+	// Detect parameters not present in the parameters slice
+	for _, paramName := range t.inOrder(t.parameters) {
+		if _, exists := parameters[paramName]; !exists {
+			// Declared variables are referenced without @ in a script
+			if variable, ok := t.scriptVariable(paramName); ok {
+				fail(NamedParameter, paramName, t.offsets[paramName], fmt.Errorf("%w: %s, reference it as %s", ErrScriptVariable, t.locate(paramName), variable))
+				continue
+			}
+			fail(NamedParameter, paramName, t.offsets[paramName], fmt.Errorf("%w: %s", ErrParameterNotProvided, t.locate(paramName)))
 		}
 	}
-	// Detect parameters not present in the parameters slice and return error
-	for paramName := range parametersInSql {
-		_, exists := parameters[paramName]
-		if !exists {
-			return "", nil, fmt.Errorf("%w: %s", ErrParameterNotProvided, paramName)
+	// Detect identifiers not present in the original SQL
+	for _, identifier := range sortedKeys(identifiers) {
+		if _, exists := t.identifiers[identifier]; !exists {
+			fail(IdentifierParameter, identifier, -1, fmt.Errorf("%w: %s", ErrIdentifierNotFound, identifier))
 		}
 	}
-	// Detect identifiers not present in the original SQL and return error
-	for identifier := range identifiers {
-		if _, exists := identifiersInSql[identifier]; !exists {
-			return "", nil, fmt.Errorf("%w: %s", ErrIdentifierNotFound, identifier)
+	// Detect identifiers not present in the identifiers map
+	for _, identifier := range t.inOrder(t.identifiers) {
+		if _, exists := identifiers[identifier]; !exists {
+			fail(IdentifierParameter, identifier, t.offsets[identifier], fmt.Errorf("%w: %s", ErrIdentifierNotProvided, t.locate(identifier)))
 		}
 	}
-	// Detect identifiers not present in the identifiers map and return error
-	for identifier := range identifiersInSql {
-		_, exists := identifiers[identifier]
+	// Compare positional parameter counts
+	if found := len(t.positionals); found > positionalParameterCount {
+		// The offset is that of the first positional parameter without value
+		fail(PositionalParameter, "", t.positionals[positionalParameterCount], fmt.Errorf("%w: found %d, provided %d", ErrNotEnoughPositionalParams, found, positionalParameterCount))
+	} else if found < positionalParameterCount {
+		fail(PositionalParameter, "", -1, fmt.Errorf("%w: found %d, provided %d", ErrTooManyPositionalParams, found, positionalParameterCount))
+	}
+	// Validate and quote all identifiers
+	quotedIdentifiers := map[string]string{}
+	var taken map[string]bool
+	for _, identifier := range t.inOrder(t.identifiers) {
+		value, exists := identifiers[identifier]
 		if !exists {
-			return "", nil, fmt.Errorf("%w: %s", ErrIdentifierNotProvided, identifier)
+			continue
 		}
+		var quoted string
+		var err error
+		if sub, ok := value.(*Query); ok {
+			// Subqueries are inlined and their parameters merged
+			if taken == nil {
+				taken = map[string]bool{}
+				for _, p := range allParameters {
+					if p.Name != "" {
+						taken[p.Name] = true
+					}
+				}
+			}
+			var subParameters []bigquery.QueryParameter
+			quoted, subParameters, err = sub.inline(identifier, taken)
+			if err == nil && len(subParameters) > 0 && positionalParameterCount > 0 {
+				err = fmt.Errorf("%w: subquery %s has named parameters", ErrMixedParameterTypes, identifier)
+			}
+			if err == nil {
+				allParameters = append(allParameters, subParameters...)
+			}
+		} else if v, ok := value.(styledIdentifierValue); ok {
+			quoted, err = v.renderStyle(identifier, opts.quoteStyle)
+		} else if v, ok := value.(identifierValue); ok {
+			quoted, err = v.render(identifier)
+		} else {
+			quoted, err = quotePath(identifier, value, opts.quoteStyle)
+		}
+		if err != nil {
+			if opts.redact {
+				err = redactValue(err, identifier)
+			}
+			fail(IdentifierParameter, identifier, t.offsets[identifier], err)
+			continue
+		}
+		quotedIdentifiers[identifier] = quoted
 	}
-	// Check for mixing of positional and named parameters
-	positionalParamsInSql := strings.Count(sql, string(questionMark))
-	hasNamedParams := len(parametersInSql) > 0
-	hasPositionalParams := positionalParamsInSql > 0
-	if hasNamedParams && hasPositionalParams {
-		return "", nil, ErrMixedParameterTypes
-	}
-	// Count positional parameters in SQL
-	positionalParamsInSql = strings.Count(sql, string(questionMark))
-	if positionalParamsInSql > positionalParameterCount {
-		return "", nil, fmt.Errorf("%w: found %d, provided %d", ErrNotEnoughPositionalParams, positionalParamsInSql, positionalParameterCount)
-	} else if positionalParamsInSql < positionalParameterCount {
-		return "", nil, fmt.Errorf("%w: found %d, provided %d", ErrTooManyPositionalParams, positionalParamsInSql, positionalParameterCount)
+	if len(errs) > 0 {
+		return "", nil, errors.Join(errs...)
 	}
 	// Apply all replacements
-	result := sql
-	for identifier, value := range identifiers {
-		quoted, replaced := QuoteIdentifier(value)
-		if replaced != "" {
-			return "", nil, fmt.Errorf("%w: %s contains %s", ErrIdentifierInvalidChars, identifier, replaced)
-		}
-		if len(quoted) == 2 {
-			return "", nil, fmt.Errorf("%w: %s", ErrIdentifierEmpty, identifier)
-		}
-		if len(quoted) > maxIdentifierBytes+2 { // +2 for backticks
-			return "", nil, fmt.Errorf("%w: %s", ErrIdentifierTooLong, identifier)
+	var result strings.Builder
+	result.Grow(len(t.sql))
+	for i, segment := range t.segments {
+		if i%2 == 1 {
+			result.WriteString(quotedIdentifiers[segment])
+		} else {
+			result.WriteString(segment)
 		}
-		result = strings.ReplaceAll(result, identifier, quoted)
 	}
-	return result, allParameters, nil
+	return result.String(), allParameters, nil
+}
+
+// offset returns the offset of the first occurrence of the name in the
+// SQL, or -1 if the name does not occur.
+func (t *template) offset(name string) int {
+	if offset, ok := t.offsets[name]; ok {
+		return offset
+	}
+	return -1
+}
+
+// positional returns the offset of the n-th (1-based) ? placeholder in the
+// SQL, or -1 if there are less placeholders.
+func (t *template) positional(n int) int {
+	if n > len(t.positionals) {
+		return -1
+	}
+	return t.positionals[n-1]
+}
+
+// inOrder returns the names in the order of their first occurrence in the SQL.
+func (t *template) inOrder(names map[string]bool) []string {
+	sorted := sortedKeys(names)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return t.offsets[sorted[i]] < t.offsets[sorted[j]]
+	})
+	return sorted
+}
+
+// locate returns the name with the line and column of its first occurrence
+// in the SQL, like "$table at line 3, col 15".
+func (t *template) locate(name string) string {
+	offset, ok := t.offsets[name]
+	if !ok {
+		return name
+	}
+	before := t.sql[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1
+	return fmt.Sprintf("%s at line %d, col %d", name, line, col)
+}
+
+// bindOptions holds the client and query settings that affect binding.
+type bindOptions struct {
+	// redact keeps identifier values out of validation errors
+	redact bool
+	// quoteStyle selects how dotted paths are quoted
+	quoteStyle QuoteStyle
+}
+
+// identifierValue is implemented by $identifier values that validate and
+// render themselves, instead of being quoted as a plain identifier.
+type identifierValue interface {
+	// render returns the SQL that replaces the named $identifier
+	render(name string) (string, error)
+}
+
+// styledIdentifierValue is implemented by $identifier values that quote
+// plain paths in the quote style of the query.
+type styledIdentifierValue interface {
+	identifierValue
+	// renderStyle returns the SQL that replaces the named $identifier
+	renderStyle(name string, style QuoteStyle) (string, error)
+}
+
+// quoteIdentifierValue validates the value of the named $identifier and
+// returns it quoted with backticks. The value may not be empty, may not
+// contain invalid characters and may not exceed 1024 bytes.
+func quoteIdentifierValue(name string, value any) (string, error) {
+	quoted, replaced := QuoteIdentifier(value)
+	if replaced != "" {
+		return "", fmt.Errorf("%w: %s contains %s", ErrIdentifierInvalidChars, name, replaced)
+	}
+	if len(quoted) == 2 {
+		return "", fmt.Errorf("%w: %s", ErrIdentifierEmpty, name)
+	}
+	if len(quoted) > maxIdentifierBytes+2 { // +2 for backticks
+		return "", fmt.Errorf("%w: %s", ErrIdentifierTooLong, name)
+	}
+	return quoted, nil
+}
+
+// translate converts dollar-sign parameters to BigQuery's native syntax.
+// It performs the following transformations:
+//   - $identifier parameters are validated and replaced with backtick-quoted values
+//   - @parameter names have the @ prefix removed for BigQuery compatibility
+//   - ? positional parameters are passed through unchanged
+//
+// See parse and bind for the validations that are performed.
+//
+// Returns the transformed SQL, processed parameters, and any validation error.
+func translate(sql string, params []bigquery.QueryParameter) (string, []bigquery.QueryParameter, error) {
+	t, err := parse(sql)
+	if err != nil {
+		return "", nil, err
+	}
+	return t.bind(params, bindOptions{})
+}
+
+// Translate returns the SQL and parameters as they would be sent to BigQuery,
+// without executing the query. It is meant for tests and tooling that want
+// to inspect the translation of a query template.
+//
+// Example:
+//
+//	sql, params, err := saferbq.Translate("SELECT * FROM $table WHERE id = @id",
+//	    []bigquery.QueryParameter{{Name: "$table", Value: "users"}, {Name: "@id", Value: 1}})
+//	// sql: SELECT * FROM `users` WHERE id = @id
+//	// params: [{Name: "id", Value: 1}]
+//
+// Returns an error if parameter validation fails.
+func Translate(sql string, params []bigquery.QueryParameter) (string, []bigquery.QueryParameter, error) {
+	return translate(sql, params)
 }
 
 // translate applies the translation of $ identifiers to the Query's SQL and parameters.
+// The translation is applied only once, subsequent calls are no-ops.
 func (q *Query) translate() error {
-	originalSQL := q.QueryConfig.Q
-	parameters := q.Parameters
+	if q.translated {
+		return nil
+	}
+	before := &Translation{SQL: q.QueryConfig.Q, Parameters: q.Parameters}
+	if err := q.client.runHooks(before, false); err != nil {
+		return fmt.Errorf("failed to translate query: %w", err)
+	}
+	originalSQL := before.SQL
+	parameters := before.Parameters
+	named := q.client != nil && q.client.namedPositionals
 
-	translatedSQL, translatedParams, err := translate(originalSQL, parameters)
+	t := q.template
+	// A hook may have changed the SQL of a prepared statement
+	if t == nil || t.source != originalSQL || t.named != named {
+		var err error
+		t, err = parseQuery(originalSQL, named)
+		if err != nil {
+			return fmt.Errorf("failed to translate query: %w", err)
+		}
+	}
+	q.positionalNames = nil
+	if named {
+		parameters, q.positionalNames = t.namePositionals(parameters)
+	}
+	q.template = t
+	opts := bindOptions{
+		redact:     q.client != nil && q.client.redactValues,
+		quoteStyle: q.effectiveQuoteStyle(),
+	}
+	translatedSQL, translatedParams, err := t.bind(parameters, opts)
 	if err != nil {
 		return fmt.Errorf("failed to translate query: %w", err)
 	}
+	if q.client != nil && q.client.autoUnnest {
+		translatedSQL = expandUnnest(translatedSQL, translatedParams)
+	}
+	if q.client != nil && q.client.normalizeKeywords {
+		translatedSQL = normalizeKeywords(translatedSQL)
+	}
+	after := &Translation{SQL: translatedSQL, Parameters: translatedParams}
+	if err := q.client.runHooks(after, true); err != nil {
+		return fmt.Errorf("failed to translate query: %w", err)
+	}
 
 	q.originalSQL = originalSQL
-	q.QueryConfig.Q = translatedSQL
-	q.Parameters = translatedParams
+	q.QueryConfig.Q = after.SQL
+	q.Parameters = after.Parameters
+	q.translated = true
 	return nil
 }
 
+// SetParams replaces the named parameters of the query with the values from
+// the map. Keys must start with $ (for identifiers) or @ (for named
+// parameters) and are routed accordingly during translation. Positional
+// parameters that were set before are kept.
+//
+// Parameters are added in the sorted order of their names, so the resulting
+// Parameters slice is deterministic.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table WHERE status = @status")
+//	q.SetParams(map[string]any{
+//	    "$table":  "users",
+//	    "@status": "active",
+//	})
+func (q *Query) SetParams(params map[string]any) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parameters := []bigquery.QueryParameter{}
+	for _, name := range names {
+		parameters = append(parameters, bigquery.QueryParameter{Name: name, Value: params[name]})
+	}
+	for _, p := range q.Parameters {
+		if p.Name == "" {
+			parameters = append(parameters, p)
+		}
+	}
+	q.Parameters = parameters
+}
+
+// SetPositionalParams replaces the positional parameters of the query with the
+// given values, in order. Each value binds to the next ? in the query.
+// Named parameters and identifiers that were set before are kept.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table WHERE id = ? AND status = ?")
+//	q.SetParams(map[string]any{"$table": "users"})
+//	q.SetPositionalParams(1, "active")
+func (q *Query) SetPositionalParams(values ...any) {
+	parameters := []bigquery.QueryParameter{}
+	for _, p := range q.Parameters {
+		if p.Name != "" {
+			parameters = append(parameters, p)
+		}
+	}
+	for _, value := range values {
+		parameters = append(parameters, bigquery.QueryParameter{Value: value})
+	}
+	q.Parameters = parameters
+}
+
 // Run initiates a query job after translating $ identifiers.
 // It validates and transforms all $identifier parameters before
 // delegating to the underlying bigquery.Query.Run method.
 //
 // Returns an error if parameter validation fails or if the
 // underlying BigQuery query execution fails.
-func (q *Query) Run(ctx context.Context) (*bigquery.Job, error) {
+func (q *Query) Run(ctx context.Context) (job *bigquery.Job, err error) {
+	start, names := time.Now(), parameterNames(q.Parameters)
+	// The deadline covers the job until it is done
+	ctx, cancel := q.withTimeout(ctx)
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+	ctx, span := q.startSpan(ctx, "saferbq.Query.Run")
+	defer func() {
+		endSpan(span, job, err)
+		q.logQuery(ctx, "Run", start, names, job, err)
+		q.client.metricsOrNil().observeQuery("Run", start, err)
+	}()
 	// Apply translation
-	if err := q.translate(); err != nil {
+	if err := q.traceTranslate(ctx); err != nil {
+		return nil, err
+	}
+	q.applyLabels()
+	// Refuse queries that would scan too many bytes
+	if err := q.checkScanBytes(ctx); err != nil {
+		return nil, err
+	}
+	// Wait for a free slot in the execution lane and the scheduler
+	release, err := q.acquire(ctx)
+	if err != nil {
 		return nil, err
 	}
 	// Call the parent Run method
-	return q.Query.Run(ctx)
+	q.applyJobID()
+	restore := q.applyTraceComment(ctx)
+	restoreLimit := q.applyMaxBytesBilled()
+	err = q.retry(ctx, func() (err error) {
+		job, err = q.Query.Run(ctx)
+		if err != nil {
+			// A duplicate job ID means the job was submitted already
+			job, err = q.existingJob(ctx, err)
+		}
+		return err
+	})
+	restore()
+	restoreLimit()
+	if err != nil {
+		release()
+		return nil, err
+	}
+	q.job = job
+	if !q.client.watchesJobs(q.Lane) {
+		release()
+		cancel()
+		return job, nil
+	}
+	// Keep the slot occupied until the job is done
+	go func() {
+		defer cancel()
+		q.client.watchJob(ctx, job, release)
+	}()
+	return job, nil
 }
 
 // Read submits a query for execution and returns results via a RowIterator.
 // It validates and transforms all $identifier parameters before
 // delegating to the underlying bigquery.Query.Read method.
 //
+// Use ReadRows for an iterator that reports the position of row errors
+// and can be closed early. The deadline of WithQueryTimeout of a Read is
+// only released when it passes.
+//
 // Returns an error if parameter validation fails or if the
 // underlying BigQuery query execution fails.
 func (q *Query) Read(ctx context.Context) (*bigquery.RowIterator, error) {
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return it.RowIterator, nil
+}
+
+// ReadRows is like Read, but returns a saferbq.RowIterator that wraps
+// errors of Next in a *RowError and keeps track of the progress. Call
+// Close when the rows are not read until iterator.Done, to release the
+// deadline of WithQueryTimeout.
+//
+// Returns an error if parameter validation fails or if the
+// underlying BigQuery query execution fails.
+func (q *Query) ReadRows(ctx context.Context) (it *RowIterator, err error) {
+	start, names := time.Now(), parameterNames(q.Parameters)
+	// The deadline covers reading the results as well
+	ctx, cancel := q.withTimeout(ctx)
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+	ctx, span := q.startSpan(ctx, "saferbq.Query.Read")
+	defer func() {
+		endSpan(span, sourceJob(it), err)
+		q.logQuery(ctx, "Read", start, names, sourceJob(it), err)
+		q.client.metricsOrNil().observeQuery("Read", start, err)
+		q.client.observeCompletedJob(context.WithoutCancel(ctx), sourceJob(it))
+	}()
 	// Apply translation
-	if err := q.translate(); err != nil {
+	if err := q.traceTranslate(ctx); err != nil {
+		return nil, err
+	}
+	q.applyLabels()
+	// Refuse queries that would scan too many bytes
+	if err := q.checkScanBytes(ctx); err != nil {
+		return nil, err
+	}
+	// Download the results through the Storage Read API when configured
+	if q.client != nil && q.client.storageRead {
+		if err := q.client.enableStorageRead(ctx); err != nil {
+			return nil, err
+		}
+	}
+	// Wait for a free slot in the execution lane and the scheduler
+	release, err := q.acquire(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer release()
 	// Call the parent Read method
-	return q.Query.Read(ctx)
+	var rows *bigquery.RowIterator
+	restore := q.applyTraceComment(ctx)
+	restoreLimit := q.applyMaxBytesBilled()
+	err = q.retry(ctx, func() (err error) {
+		rows, err = q.Query.Read(ctx)
+		return err
+	})
+	restore()
+	restoreLimit()
+	if err != nil {
+		return nil, err
+	}
+	if job := rows.SourceJob(); job != nil {
+		q.job = job
+	}
+	it = newRowIterator(rows)
+	it.cancel = cancel
+	return it, nil
+}
+
+// sourceJob returns the job that backs the iterator, or nil.
+func sourceJob(it *RowIterator) *bigquery.Job {
+	if it == nil {
+		return nil
+	}
+	return it.SourceJob()
 }
@@ -24,7 +24,7 @@ func TestQueryTranslate(t *testing.T) {
 			name:          "identifier replacement only, full table path",
 			sqlIn:         "SELECT * FROM $table WHERE id = 1",
 			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "myproject.mydataset.mytable"}},
-			sqlOut:        "SELECT * FROM `myproject.mydataset.mytable` WHERE id = 1",
+			sqlOut:        "SELECT * FROM `myproject`.`mydataset`.`mytable` WHERE id = 1",
 			parametersOut: []bigquery.QueryParameter{},
 		},
 		{
@@ -229,13 +229,13 @@ func TestQueryTranslate(t *testing.T) {
 			name:         "missing named parameter",
 			sqlIn:        "SELECT * FROM $table WHERE status = @status",
 			parametersIn: []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}},
-			errorMessage: "parameter not provided in parameters: @status",
+			errorMessage: "parameter not provided in parameters: @status at line 1, col 37",
 		},
 		{
 			name:         "missing identifier",
 			sqlIn:        "SELECT * FROM $table WHERE id = 1",
 			parametersIn: []bigquery.QueryParameter{},
-			errorMessage: "identifier not provided in parameters: $table",
+			errorMessage: "identifier not provided in parameters: $table at line 1, col 15",
 		},
 		{
 			name:         "unused named parameter",
@@ -274,6 +274,108 @@ func TestQueryTranslate(t *testing.T) {
 			parametersIn: []bigquery.QueryParameter{{Name: "$table", Value: "users` -- malicious comment"}, {Name: "@status", Value: "active"}, {Name: "@id", Value: 1}},
 			errorMessage: "identifier contains invalid characters: $table contains `",
 		},
+		{
+			name:          "escaped dollar sign in partition decorator",
+			sqlIn:         "SELECT * FROM $dataset.events$$20240101",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$dataset", Value: "mydataset"}},
+			sqlOut:        "SELECT * FROM `mydataset`.events$20240101",
+			parametersOut: []bigquery.QueryParameter{},
+		},
+		{
+			name:          "escaped dollar sign before a name",
+			sqlIn:         "SELECT $$price FROM $table",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}},
+			sqlOut:        "SELECT $price FROM `mytable`",
+			parametersOut: []bigquery.QueryParameter{},
+		},
+		{
+			name:          "parameters in raw and triple quoted strings",
+			sqlIn:         "SELECT REGEXP_EXTRACT(email, r'@(.+)$'), \"\"\"who? @me\"\"\" FROM $table WHERE id = ?",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}, {Value: 1}},
+			sqlOut:        "SELECT REGEXP_EXTRACT(email, r'@(.+)$'), \"\"\"who? @me\"\"\" FROM `mytable` WHERE id = ?",
+			parametersOut: []bigquery.QueryParameter{{Value: 1}},
+		},
+		{
+			name:          "parameters in string literals",
+			sqlIn:         "SELECT 'a?b', \"@c\" FROM $table WHERE id = @id",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}, {Name: "@id", Value: 1}},
+			sqlOut:        "SELECT 'a?b', \"@c\" FROM `mytable` WHERE id = @id",
+			parametersOut: []bigquery.QueryParameter{{Name: "id", Value: 1}},
+		},
+		{
+			name: "javascript UDF body",
+			sqlIn: "CREATE TEMP FUNCTION price(x FLOAT64) RETURNS STRING LANGUAGE js AS r\"\"\"\n" +
+				"  var s = '$' + x.toFixed(2); return s.match(/^\\$\\d+@?/) ? s : null;\n\"\"\";\n" +
+				"SELECT price(amount) FROM $table WHERE id = @id",
+			parametersIn: []bigquery.QueryParameter{{Name: "$table", Value: "orders"}, {Name: "@id", Value: 1}},
+			sqlOut: "CREATE TEMP FUNCTION price(x FLOAT64) RETURNS STRING LANGUAGE js AS r\"\"\"\n" +
+				"  var s = '$' + x.toFixed(2); return s.match(/^\\$\\d+@?/) ? s : null;\n\"\"\";\n" +
+				"SELECT price(amount) FROM `orders` WHERE id = @id",
+			parametersOut: []bigquery.QueryParameter{{Name: "id", Value: 1}},
+		},
+		{
+			name: "javascript UDF body in a single quoted string",
+			sqlIn: "CREATE TEMP FUNCTION f(x INT64) RETURNS INT64 LANGUAGE js AS 'return x > 0 ? x : $fallback;';\n" +
+				"SELECT f(?) FROM $table",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "t"}, {Value: 1}},
+			sqlOut:        "CREATE TEMP FUNCTION f(x INT64) RETURNS INT64 LANGUAGE js AS 'return x > 0 ? x : $fallback;';\nSELECT f(?) FROM `t`",
+			parametersOut: []bigquery.QueryParameter{{Value: 1}},
+		},
+		{
+			name:          "system variables",
+			sqlIn:         "SET @@query_label = @label; SELECT @@last_job_id, @@project_id FROM $table",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}, {Name: "@label", Value: "team:etl"}},
+			sqlOut:        "SET @@query_label = @label; SELECT @@last_job_id, @@project_id FROM `mytable`",
+			parametersOut: []bigquery.QueryParameter{{Name: "label", Value: "team:etl"}},
+		},
+		{
+			name:          "question marks in comments and JSON paths",
+			sqlIn:         "SELECT JSON_QUERY(data, '$.items[?(@.id)]') -- why?\n/* ? */ FROM $table # ok?\nWHERE id = ?",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}, {Value: 1}},
+			sqlOut:        "SELECT JSON_QUERY(data, '$.items[?(@.id)]') -- why?\n/* ? */ FROM `mytable` # ok?\nWHERE id = ?",
+			parametersOut: []bigquery.QueryParameter{{Value: 1}},
+		},
+		{
+			name:          "question marks in literals are not counted",
+			sqlIn:         "SELECT * FROM $table WHERE note = 'what?' AND id = ?",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}},
+			errorMessage:  "not enough positional parameters: found 1, provided 0",
+			parametersOut: nil,
+		},
+		{
+			name:         "missing identifier in multi-line template",
+			sqlIn:        "SELECT *\nFROM $dataset.$table\nJOIN $dataset.$other ON 'é' = ''\nWHERE x = @x -- $note",
+			parametersIn: []bigquery.QueryParameter{{Name: "$dataset", Value: "d"}, {Name: "$table", Value: "t"}, {Name: "@x", Value: 1}},
+			errorMessage: "identifier not provided in parameters: $other at line 3, col 15",
+		},
+		{
+			name:         "all problems are reported",
+			sqlIn:        "SELECT *\nFROM $table\nWHERE a = @a\n  AND b = @b",
+			parametersIn: []bigquery.QueryParameter{{Name: "$table", Value: "a;b"}, {Name: "$unused", Value: "x"}, {Name: "@b", Value: 1}, {Name: "@c", Value: 1}},
+			errorMessage: "parameter not found in query: @c\n" +
+				"parameter not provided in parameters: @a at line 3, col 11\n" +
+				"identifier not found in query: $unused\n" +
+				"identifier contains invalid characters: $table contains ;",
+		},
+		{
+			name:         "duplicate identifier",
+			sqlIn:        "SELECT * FROM $table",
+			parametersIn: []bigquery.QueryParameter{{Name: "$table", Value: "a"}, {Name: "$table", Value: "b"}},
+			errorMessage: "duplicate parameter: $table",
+		},
+		{
+			name:         "duplicate named parameter",
+			sqlIn:        "SELECT * FROM t WHERE status = @status",
+			parametersIn: []bigquery.QueryParameter{{Name: "@status", Value: "a"}, {Name: "@status", Value: "a"}},
+			errorMessage: "duplicate parameter: @status",
+		},
+		{
+			name:          "dollar signs in literals and comments",
+			sqlIn:         "SELECT '$amount', `t$20240101` FROM $table -- $note",
+			parametersIn:  []bigquery.QueryParameter{{Name: "$table", Value: "mytable"}},
+			sqlOut:        "SELECT '$amount', `t$20240101` FROM `mytable` -- $note",
+			parametersOut: []bigquery.QueryParameter{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -480,3 +582,83 @@ func TestQueryReadError(t *testing.T) {
 		t.Errorf("Read() error = %v, want ErrIdentifierInvalidChars", err)
 	}
 }
+
+func TestQuerySetParams(t *testing.T) {
+	q := &Query{
+		Query: bigquery.Query{
+			QueryConfig: bigquery.QueryConfig{
+				Q: "SELECT * FROM $table WHERE status = @status AND id = @id",
+			},
+		},
+	}
+	q.SetParams(map[string]any{
+		"@status": "active",
+		"$table":  "mytable",
+		"@id":     1,
+	})
+
+	expected := []bigquery.QueryParameter{
+		{Name: "$table", Value: "mytable"},
+		{Name: "@id", Value: 1},
+		{Name: "@status", Value: "active"},
+	}
+	if !equalQueryParameters(q.Parameters, expected) {
+		t.Fatalf("SetParams() Parameters = %v, want %v", q.Parameters, expected)
+	}
+
+	err := q.translate()
+	if err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	expectedSQL := "SELECT * FROM `mytable` WHERE status = @status AND id = @id"
+	if q.QueryConfig.Q != expectedSQL {
+		t.Errorf("translate() SQL = %q, want %q", q.QueryConfig.Q, expectedSQL)
+	}
+}
+
+func TestQuerySetPositionalParams(t *testing.T) {
+	q := &Query{
+		Query: bigquery.Query{
+			QueryConfig: bigquery.QueryConfig{
+				Q: "SELECT * FROM $table WHERE id = ? AND status = ?",
+			},
+		},
+	}
+	q.SetPositionalParams(0)
+	q.SetParams(map[string]any{"$table": "mytable"})
+	q.SetPositionalParams(1, "active")
+
+	expected := []bigquery.QueryParameter{
+		{Name: "$table", Value: "mytable"},
+		{Value: 1},
+		{Value: "active"},
+	}
+	if !equalQueryParameters(q.Parameters, expected) {
+		t.Fatalf("SetPositionalParams() Parameters = %v, want %v", q.Parameters, expected)
+	}
+
+	err := q.translate()
+	if err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	expectedParams := []bigquery.QueryParameter{{Value: 1}, {Value: "active"}}
+	if !equalQueryParameters(q.Parameters, expectedParams) {
+		t.Errorf("translate() Parameters = %v, want %v", q.Parameters, expectedParams)
+	}
+}
+
+func TestTranslateExported(t *testing.T) {
+	sql, params, err := Translate("SELECT * FROM $table WHERE id = @id", []bigquery.QueryParameter{
+		{Name: "$table", Value: "users"},
+		{Name: "@id", Value: 1},
+	})
+	if err != nil {
+		t.Fatalf("Translate() unexpected error: %v", err)
+	}
+	if sql != "SELECT * FROM `users` WHERE id = @id" {
+		t.Errorf("Translate() sql = %q", sql)
+	}
+	if len(params) != 1 || params[0].Name != "id" || params[0].Value != 1 {
+		t.Errorf("Translate() params = %v, want [{id 1}]", params)
+	}
+}
@@ -0,0 +1,63 @@
+package saferbq
+
+import (
+	"strings"
+)
+
+// QuoteStyle selects how $identifier values that hold a dotted path, such
+// as project.dataset.table, are quoted. BigQuery accepts both styles.
+type QuoteStyle int
+
+const (
+	// QuotePerPart quotes every part of a path separately, as in
+	// `project`.`dataset`.`table`. This is the default, as every part is
+	// validated on its own.
+	QuotePerPart QuoteStyle = iota
+	// QuoteWholePath quotes the path as a whole, as in
+	// `project.dataset.table`.
+	QuoteWholePath
+)
+
+// WithQuoteStyle sets the style that is used to quote $identifier values
+// that hold a dotted path. The default is QuotePerPart. The style can be
+// overridden per query with Query.SetQuoteStyle.
+//
+// Values that contain a slash, such as roles/bigquery.dataViewer, are
+// resource names rather than paths and are always quoted as a whole.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithQuoteStyle(saferbq.QuoteWholePath))
+func WithQuoteStyle(style QuoteStyle) Option {
+	return func(c *Client) {
+		c.quoteStyle = style
+	}
+}
+
+// SetQuoteStyle overrides the quote style of the client for this query,
+// see WithQuoteStyle. It returns the query to allow chaining.
+func (q *Query) SetQuoteStyle(style QuoteStyle) *Query {
+	q.quoteStyle = &style
+	return q
+}
+
+// effectiveQuoteStyle returns the quote style of the query, falling back
+// to the quote style of the client. The client may be nil.
+func (q *Query) effectiveQuoteStyle() QuoteStyle {
+	if q.quoteStyle != nil {
+		return *q.quoteStyle
+	}
+	if q.client != nil {
+		return q.client.quoteStyle
+	}
+	return QuotePerPart
+}
+
+// quotePath validates the value of the named $identifier and quotes it in
+// the given style.
+func quotePath(name string, value any, style QuoteStyle) (string, error) {
+	if s, ok := value.(string); ok && style == QuotePerPart && strings.Contains(s, ".") && !strings.Contains(s, "/") {
+		return QualifiedIdentifier(s).render(name)
+	}
+	return quoteIdentifierValue(name, value)
+}
@@ -0,0 +1,58 @@
+package saferbq
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestQuoteStyle(t *testing.T) {
+	tests := []struct {
+		name        string
+		clientStyle QuoteStyle
+		queryStyle  *QuoteStyle
+		value       any
+		sqlOut      string
+	}{
+		{"per part by default", QuotePerPart, nil, "project.dataset.table", "SELECT * FROM `project`.`dataset`.`table`"},
+		{"whole path from client", QuoteWholePath, nil, "project.dataset.table", "SELECT * FROM `project.dataset.table`"},
+		{"query overrides client", QuoteWholePath, ptr(QuotePerPart), "project.dataset.table", "SELECT * FROM `project`.`dataset`.`table`"},
+		{"query whole path", QuotePerPart, ptr(QuoteWholePath), "project.dataset.table", "SELECT * FROM `project.dataset.table`"},
+		{"no dots", QuotePerPart, nil, "table", "SELECT * FROM `table`"},
+		{"domain-scoped project", QuotePerPart, nil, "example.com:project.dataset.table", "SELECT * FROM `example.com:project`.`dataset`.`table`"},
+		{"resource name with slash", QuotePerPart, nil, "roles/bigquery.dataViewer", "SELECT * FROM `roles/bigquery.dataViewer`"},
+		{"qualified identifier ignores style", QuoteWholePath, nil, QualifiedIdentifier("dataset.table"), "SELECT * FROM `dataset`.`table`"},
+		{"time travel whole path", QuoteWholePath, nil, AsOf("dataset.table", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), "SELECT * FROM `dataset.table` FOR SYSTEM_TIME AS OF TIMESTAMP '2024-01-02 03:04:05+00:00'"},
+		{"time travel query style", QuoteWholePath, ptr(QuotePerPart), AsOf("dataset.table", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), "SELECT * FROM `dataset`.`table` FOR SYSTEM_TIME AS OF TIMESTAMP '2024-01-02 03:04:05+00:00'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := (&Client{}).Configure(WithQuoteStyle(tt.clientStyle)).Query("SELECT * FROM $table")
+			if tt.queryStyle != nil {
+				q.SetQuoteStyle(*tt.queryStyle)
+			}
+			q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: tt.value}}
+			if err := q.translate(); err != nil {
+				t.Fatalf("translate() unexpected error: %v", err)
+			}
+			if q.Q != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", q.Q, tt.sqlOut)
+			}
+		})
+	}
+}
+
+func TestQuoteStylePerPartEmptyPart(t *testing.T) {
+	q := (&Client{}).Query("SELECT * FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "dataset..table"}}
+	want := "failed to translate query: identifier is empty: $table"
+	if err := q.translate(); err == nil || err.Error() != want {
+		t.Errorf("translate() error = %v, want %q", err, want)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
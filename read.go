@@ -0,0 +1,112 @@
+package saferbq
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// ReadAll translates and runs the query like Query.Read, and loads every
+// result row into a T, using the struct loading of the BigQuery client
+// (see bigquery.RowIterator.Next). T is usually a struct with bigquery
+// field tags, but may be any type that Next can load into.
+//
+// Example:
+//
+//	type Event struct {
+//	    ID   int64  `bigquery:"id"`
+//	    Name string `bigquery:"name"`
+//	}
+//
+//	q := client.Query("SELECT id, name FROM $table WHERE day = @day")
+//	q.SetParams(map[string]any{"$table": "events_" + tenant, "@day": day})
+//	events, err := saferbq.ReadAll[Event](ctx, q)
+//
+// Returns an error if the query fails, or a *RowError if a row could not
+// be loaded.
+func ReadAll[T any](ctx context.Context, q *Query) ([]T, error) {
+	rows := []T{}
+	err := ForEach(ctx, q, func(row T) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ForEach translates and runs the query like Query.Read, and calls fn with
+// every result row loaded into a T, without holding all rows in memory.
+// Iteration stops at the first error that fn returns.
+//
+// Example:
+//
+//	err := saferbq.ForEach(ctx, q, func(event Event) error {
+//	    return process(event)
+//	})
+//
+// Returns an error if the query fails, a *RowError if a row could not be
+// loaded, or the error returned by fn.
+func ForEach[T any](ctx context.Context, q *Query, fn func(T) error) error {
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for {
+		var row T
+		err := it.Next(&row)
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadRow translates and runs the query like Read, and loads its single
+// result row into dst, like database/sql's QueryRow. The dst is any value
+// that bigquery.RowIterator.Next can load into, usually a pointer to a
+// struct.
+//
+// Example:
+//
+//	q := client.Query("SELECT id, name FROM $table WHERE id = @id")
+//	q.SetParams(map[string]any{"$table": "users_" + tenant, "@id": id})
+//	var user User
+//	if err := q.ReadRow(ctx, &user); errors.Is(err, saferbq.ErrNoRows) {
+//	    return nil, ErrUserNotFound
+//	}
+//
+// Returns ErrNoRows if the query returns no rows, and ErrTooManyRows if it
+// returns more than one row (dst then holds the first row). Returns an
+// error if the query fails, or a *RowError if the row could not be loaded.
+func (q *Query) ReadRow(ctx context.Context, dst any) error {
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	err = it.Next(dst)
+	if err == iterator.Done {
+		return ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+	var extra []bigquery.Value
+	err = it.Next(&extra)
+	if err == iterator.Done {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ErrTooManyRows
+}
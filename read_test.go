@@ -0,0 +1,110 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// readTestRow is a result row of the read tests.
+type readTestRow struct {
+	ID   int64  `bigquery:"id"`
+	Name string `bigquery:"name"`
+}
+
+// newReadFake returns a fake with an id and name column and two rows.
+func newReadFake() *fakeBigQuery {
+	return &fakeBigQuery{
+		schema: []map[string]any{
+			{"name": "id", "type": "INTEGER"},
+			{"name": "name", "type": "STRING"},
+		},
+		rows: [][]any{{"1", "a"}, {"2", "b"}},
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	fake := newReadFake()
+	client := newFakeClient(t, fake)
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	rows, err := ReadAll[readTestRow](context.Background(), q)
+	if err != nil {
+		t.Fatalf("ReadAll() unexpected error: %v", err)
+	}
+	expected := []readTestRow{{1, "a"}, {2, "b"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("ReadAll() = %+v, want %+v", rows, expected)
+	}
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != "SELECT id, name FROM `events`" {
+		t.Errorf("ReadAll() queries = %q", queries)
+	}
+}
+
+func TestReadAllTranslationError(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events;"})
+	if _, err := ReadAll[readTestRow](context.Background(), q); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("ReadAll() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	names := []string{}
+	err := ForEach(context.Background(), q, func(row readTestRow) error {
+		names = append(names, row.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("ForEach() names = %v", names)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	q = client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	err = ForEach(context.Background(), q, func(row readTestRow) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("ForEach() error = %v after %d calls, want %v after 1 call", err, calls, stop)
+	}
+}
+
+func TestQueryReadRow(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		rows     [][]any
+		expected readTestRow
+		err      error
+	}{
+		{"one row", [][]any{{"1", "a"}}, readTestRow{1, "a"}, nil},
+		{"no rows", [][]any{}, readTestRow{}, ErrNoRows},
+		{"too many rows", [][]any{{"1", "a"}, {"2", "b"}}, readTestRow{1, "a"}, ErrTooManyRows},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newReadFake()
+			fake.rows = tt.rows
+			client := newFakeClient(t, fake)
+			q := client.Query("SELECT id, name FROM $table WHERE id = @id")
+			q.SetParams(map[string]any{"$table": "users", "@id": 1})
+			var row readTestRow
+			err := q.ReadRow(context.Background(), &row)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ReadRow() error = %v, want %v", err, tt.err)
+			}
+			if row != tt.expected {
+				t.Errorf("ReadRow() row = %+v, want %+v", row, tt.expected)
+			}
+		})
+	}
+}
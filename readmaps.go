@@ -0,0 +1,79 @@
+package saferbq
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// ReadMaps translates and runs the query like Read, and returns every
+// result row as a map from column name to value, using the result schema.
+// Nested records are maps as well, and repeated columns are []any. It is
+// meant for generic tooling over arbitrary $table values that has no
+// struct to load the rows into.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table LIMIT 10")
+//	q.SetParams(map[string]any{"$table": table})
+//	rows, err := q.ReadMaps(ctx)
+//	for _, row := range rows {
+//	    fmt.Println(row["id"])
+//	}
+//
+// Returns an error if the query fails, or a *RowError if a row could not
+// be read.
+func (q *Query) ReadMaps(ctx context.Context) ([]map[string]any, error) {
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	rows := []map[string]any{}
+	for {
+		var values []bigquery.Value
+		err := it.Next(&values)
+		if err == iterator.Done {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, rowMap(values, it.Schema))
+	}
+}
+
+// rowMap returns the values of a row (or record) by the names of the
+// fields of the schema.
+func rowMap(values []bigquery.Value, schema bigquery.Schema) map[string]any {
+	row := make(map[string]any, len(schema))
+	for i, field := range schema {
+		if i < len(values) {
+			row[field.Name] = fieldValue(values[i], field)
+		}
+	}
+	return row
+}
+
+// fieldValue converts the value of the field, with records as maps and
+// repeated values as []any.
+func fieldValue(value bigquery.Value, field *bigquery.FieldSchema) any {
+	if value == nil {
+		return nil
+	}
+	if field.Repeated {
+		repeated, _ := value.([]bigquery.Value)
+		values := make([]any, len(repeated))
+		element := *field
+		element.Repeated = false
+		for i, v := range repeated {
+			values[i] = fieldValue(v, &element)
+		}
+		return values
+	}
+	if record, ok := value.([]bigquery.Value); ok && field.Schema != nil {
+		return rowMap(record, field.Schema)
+	}
+	return value
+}
@@ -0,0 +1,48 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQueryReadMaps(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{
+			{"name": "id", "type": "INTEGER"},
+			{"name": "name", "type": "STRING"},
+			{"name": "tags", "type": "STRING", "mode": "REPEATED"},
+			{"name": "address", "type": "RECORD", "fields": []map[string]any{
+				{"name": "city", "type": "STRING"},
+			}},
+		},
+		rows: [][]any{
+			{"1", "a", []map[string]any{{"v": "x"}, {"v": "y"}}, map[string]any{"f": []map[string]any{{"v": "Paris"}}}},
+			{"2", nil, []map[string]any{}, nil},
+		},
+	}
+	client := newFakeClient(t, fake)
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	rows, err := q.ReadMaps(context.Background())
+	if err != nil {
+		t.Fatalf("ReadMaps() unexpected error: %v", err)
+	}
+	expected := []map[string]any{
+		{"id": int64(1), "name": "a", "tags": []any{"x", "y"}, "address": map[string]any{"city": "Paris"}},
+		{"id": int64(2), "name": nil, "tags": []any{}, "address": nil},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("ReadMaps() = %#v, want %#v", rows, expected)
+	}
+}
+
+func TestQueryReadMapsError(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT * FROM $table")
+	q.SetParams(map[string]any{"$table": "a`b"})
+	if _, err := q.ReadMaps(context.Background()); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("ReadMaps() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+}
@@ -0,0 +1,55 @@
+package saferbq
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RedactValuesInErrors keeps identifier values out of validation errors, so
+// user input can't leak into logs. Errors still wrap the same sentinel error
+// and contain the parameter name, but the offending characters are replaced
+// by a placeholder.
+//
+// Example:
+//
+//	client.Configure(saferbq.RedactValuesInErrors())
+//
+//	// Error: identifier contains invalid characters: $table (value redacted)
+//	// instead of: identifier contains invalid characters: $table contains ;
+func RedactValuesInErrors() Option {
+	return func(c *Client) {
+		c.redactValues = true
+	}
+}
+
+// redactValue returns an error that wraps the same sentinel errors as err,
+// with only the parameter name as message. The whole error tree is walked,
+// so the sentinels of joined errors, like the validation errors of a
+// subquery, are kept as well. Other errors are dropped, as their message
+// may contain the value.
+func redactValue(err error, name string) error {
+	var sentinels []any
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				walk(err)
+			}
+		default:
+			if slices.Contains(errorKinds, err) && !slices.Contains(sentinels, any(err)) {
+				sentinels = append(sentinels, err)
+			}
+		}
+	}
+	walk(err)
+	format := strings.Repeat("%w, ", len(sentinels))
+	format = strings.TrimSuffix(format, ", ")
+	if format != "" {
+		format += ": "
+	}
+	return fmt.Errorf(format+"%s (value redacted)", append(sentinels, name)...)
+}
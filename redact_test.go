@@ -0,0 +1,109 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestRedactValuesInErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		err   error
+		want  string
+	}{
+		{"invalid characters", "users; DROP TABLE x", ErrIdentifierInvalidChars, "identifier contains invalid characters: $table (value redacted)"},
+		{"too long", strings.Repeat("a", 2000), ErrIdentifierTooLong, "identifier is too long: $table (value redacted)"},
+		{"not allowed", Enum("$table", "a", "b").Param("c").Value, ErrIdentifierNotAllowed, "identifier is not allowed: $table (value redacted)"},
+	}
+	client := (&Client{}).Configure(RedactValuesInErrors())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := client.Query("SELECT * FROM $table")
+			q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: tt.value}}
+			err := q.translate()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("translate() error = %v, want %v", err, tt.err)
+			}
+			if !strings.HasSuffix(err.Error(), tt.want) {
+				t.Errorf("translate() error = %q, want suffix %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactValuesDisabled(t *testing.T) {
+	q := (&Client{}).Query("SELECT * FROM $table")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "a;b"}}
+	if err := q.translate(); err == nil || !strings.Contains(err.Error(), "contains ;") {
+		t.Errorf("translate() error = %v, want the offending characters", err)
+	}
+}
+
+func TestRedactedValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"sort column", func() error { _, err := OrderBy([]string{"name"}, SortSpec{Column: "name; DROP TABLE x"}); return err }(), "identifier is not allowed: sort column (value redacted)"},
+		{"label key", ValidateLabel("Secret", "data"), "invalid label: label key (value redacted)"},
+		{"label value", ValidateLabel("team", "Secret Value"), "invalid label: label value (value redacted)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil || tt.err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactValuesInSubqueryErrors(t *testing.T) {
+	client := (&Client{}).Configure(RedactValuesInErrors())
+	sub := (&Client{}).Query("SELECT id FROM $source WHERE day = @day")
+	sub.SetParams(map[string]any{"$source": "events; DROP TABLE x"})
+	q := client.Query("SELECT * FROM $table WHERE id IN $ids")
+	q.SetParams(map[string]any{"$table": "users", "$ids": sub})
+	err := q.translate()
+	for _, sentinel := range []error{ErrIdentifierInvalidChars, ErrParameterNotProvided} {
+		if !errors.Is(err, sentinel) {
+			t.Errorf("translate() error = %v, want %v", err, sentinel)
+		}
+	}
+	if err == nil || strings.Contains(err.Error(), "DROP") || strings.Contains(err.Error(), ";") {
+		t.Errorf("translate() error = %q, want the value redacted", err)
+	}
+	if want := "$ids (value redacted)"; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("translate() error = %q, want suffix %q", err, want)
+	}
+}
+
+func TestRedactValuesInPrincipalErrors(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake).Configure(RedactValuesInErrors())
+	ctx := context.Background()
+	orders := TableRef{DatasetID: "sales", TableID: "orders"}
+	errs := []error{
+		Grant("roles/bigquery.dataViewer").On(orders).To("user:secret@example.com' --").Exec(ctx, client),
+		client.CreateRowAccessPolicy(ctx, "sales", "orders", "p", RowAccessPolicy{
+			Grantees: []string{"user:secret@example.com' --"},
+			Filter:   "true",
+		}),
+	}
+	for _, err := range errs {
+		if !errors.Is(err, ErrInvalidPrincipal) {
+			t.Errorf("error = %v, want %v", err, ErrInvalidPrincipal)
+		}
+		if err == nil || strings.Contains(err.Error(), "secret") {
+			t.Errorf("error = %q, want the principal redacted", err)
+		}
+	}
+	if len(fake.executedQueries()) != 0 {
+		t.Errorf("executed %v, want no queries", fake.executedQueries())
+	}
+}
@@ -0,0 +1,188 @@
+package saferbq
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// CachedResult is the complete result of a query, as stored in a
+// ResultStore.
+type CachedResult struct {
+	// Schema is the schema of the result
+	Schema bigquery.Schema
+	// Rows are the result rows, with the values in the order of the schema
+	Rows [][]bigquery.Value
+}
+
+// Maps returns the rows as maps from column name to value, like
+// Query.ReadMaps.
+func (r *CachedResult) Maps() []map[string]any {
+	rows := make([]map[string]any, len(r.Rows))
+	for i, values := range r.Rows {
+		rows[i] = rowMap(values, r.Schema)
+	}
+	return rows
+}
+
+// ResultStore stores the results of ReadCached by key. Implementations
+// must be safe for concurrent use. NewLRUStore returns an in-memory store.
+type ResultStore interface {
+	// Get returns the result of the key, or false when it is not stored
+	// or has expired
+	Get(ctx context.Context, key string) (*CachedResult, bool)
+	// Set stores the result of the key for the TTL
+	Set(ctx context.Context, key string, result *CachedResult, ttl time.Duration)
+}
+
+// WithResultCache sets the store in which ReadCached caches the results
+// of queries.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithResultCache(saferbq.NewLRUStore(1000)))
+func WithResultCache(store ResultStore) Option {
+	return func(c *Client) {
+		c.resultStore = store
+	}
+}
+
+// ReadCached translates and runs the query like Read, and returns the
+// complete result. The result is cached in the store of WithResultCache
+// for the TTL, keyed by a hash of the project, the translated SQL and the
+// parameter values, so identical queries (such as those of a dashboard
+// that refreshes every few seconds) are not billed again. Without a store
+// the query is run every time.
+//
+// Example:
+//
+//	q := client.Query("SELECT country, COUNT(*) AS n FROM $table GROUP BY country")
+//	q.SetParams(map[string]any{"$table": "events_" + tenant})
+//	result, err := q.ReadCached(ctx, time.Minute)
+//	rows := result.Maps()
+//
+// Returns an error if the query fails, or a *RowError if a row could not
+// be read. Errors are not cached.
+func (q *Query) ReadCached(ctx context.Context, ttl time.Duration) (*CachedResult, error) {
+	if err := q.translate(); err != nil {
+		return nil, err
+	}
+	store := q.client.resultStore
+	key := q.cacheKey()
+	if store != nil {
+		if result, ok := store.Get(ctx, key); ok {
+			return result, nil
+		}
+	}
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	result := &CachedResult{Rows: [][]bigquery.Value{}}
+	for {
+		var values []bigquery.Value
+		err := it.Next(&values)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	result.Schema = it.Schema
+	if store != nil {
+		store.Set(ctx, key, result, ttl)
+	}
+	return result, nil
+}
+
+// cacheKey returns the hash of the project, the translated SQL and the
+// canonical encoding of the parameter values of the translated query.
+func (q *Query) cacheKey() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", q.client.Project(), q.QueryConfig.Q)
+	writeParameters(h, q.Parameters)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LRUStore is an in-memory ResultStore that holds a maximum number of
+// results and evicts the least recently used result when it is full.
+type LRUStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	// now returns the current time (replaceable in tests)
+	now func() time.Time
+}
+
+// lruEntry is a result in the LRUStore with the time it expires.
+type lruEntry struct {
+	key     string
+	result  *CachedResult
+	expires time.Time
+}
+
+// NewLRUStore returns an in-memory ResultStore that holds up to capacity
+// results.
+func NewLRUStore(capacity int) *LRUStore {
+	return &LRUStore{
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+		now:      time.Now,
+	}
+}
+
+// Get implements ResultStore.
+func (s *LRUStore) Get(ctx context.Context, key string) (*CachedResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !s.now().Before(entry.expires) {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.order.MoveToFront(element)
+	return entry.result, true
+}
+
+// Set implements ResultStore.
+func (s *LRUStore) Set(ctx context.Context, key string, result *CachedResult, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &lruEntry{key: key, result: result, expires: s.now().Add(ttl)}
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of results in the store, including expired
+// results that were not evicted yet.
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,112 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryReadCached(t *testing.T) {
+	fake := newReadFake()
+	client := newFakeClient(t, fake)
+	client.Configure(WithResultCache(NewLRUStore(10)))
+	ctx := context.Background()
+	read := func(table string, id int) *CachedResult {
+		t.Helper()
+		q := client.Query("SELECT id, name FROM $table WHERE id > @id")
+		q.SetParams(map[string]any{"$table": table, "@id": id})
+		result, err := q.ReadCached(ctx, time.Minute)
+		if err != nil {
+			t.Fatalf("ReadCached() unexpected error: %v", err)
+		}
+		return result
+	}
+
+	result := read("events", 0)
+	expected := []map[string]any{{"id": int64(1), "name": "a"}, {"id": int64(2), "name": "b"}}
+	if !reflect.DeepEqual(result.Maps(), expected) {
+		t.Errorf("ReadCached() = %v, want %v", result.Maps(), expected)
+	}
+	read("events", 0)
+	if queries := fake.executedQueries(); len(queries) != 1 {
+		t.Errorf("executed %d queries, want 1 (cached)", len(queries))
+	}
+	read("events", 1)
+	read("users", 0)
+	if queries := fake.executedQueries(); len(queries) != 3 {
+		t.Errorf("executed %d queries, want 3 (other parameters and identifiers)", len(queries))
+	}
+}
+
+func TestQueryReadCachedTimeParameter(t *testing.T) {
+	fake := newReadFake()
+	client := newFakeClient(t, fake)
+	client.Configure(WithResultCache(NewLRUStore(10)))
+	now := time.Now()
+	// The same time with and without monotonic clock reading hits the cache
+	for _, at := range []time.Time{now, now.Round(0)} {
+		q := client.Query("SELECT id, name FROM $table WHERE created < @at")
+		q.SetParams(map[string]any{"$table": "events", "@at": at})
+		if _, err := q.ReadCached(context.Background(), time.Minute); err != nil {
+			t.Fatalf("ReadCached() unexpected error: %v", err)
+		}
+	}
+	if queries := fake.executedQueries(); len(queries) != 1 {
+		t.Errorf("executed %d queries, want 1 (cached)", len(queries))
+	}
+}
+
+func TestQueryReadCachedWithoutStore(t *testing.T) {
+	fake := newReadFake()
+	client := newFakeClient(t, fake)
+	for range 2 {
+		q := client.Query("SELECT id, name FROM $table")
+		q.SetParams(map[string]any{"$table": "events"})
+		if _, err := q.ReadCached(context.Background(), time.Minute); err != nil {
+			t.Fatalf("ReadCached() unexpected error: %v", err)
+		}
+	}
+	if queries := fake.executedQueries(); len(queries) != 2 {
+		t.Errorf("executed %d queries, want 2 (no store)", len(queries))
+	}
+
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "a;b"})
+	if _, err := q.ReadCached(context.Background(), time.Minute); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("ReadCached() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+}
+
+func TestLRUStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(2)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	a, b, c := &CachedResult{}, &CachedResult{}, &CachedResult{}
+
+	store.Set(ctx, "a", a, time.Minute)
+	store.Set(ctx, "b", b, time.Hour)
+	if result, ok := store.Get(ctx, "a"); !ok || result != a {
+		t.Error("Get(a) missing")
+	}
+	// b is the least recently used result
+	store.Set(ctx, "c", c, time.Hour)
+	if _, ok := store.Get(ctx, "b"); ok {
+		t.Error("Get(b) not evicted")
+	}
+	if store.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", store.Len())
+	}
+	now = now.Add(time.Minute)
+	if _, ok := store.Get(ctx, "a"); ok {
+		t.Error("Get(a) not expired")
+	}
+	if result, ok := store.Get(ctx, "c"); !ok || result != c {
+		t.Error("Get(c) missing")
+	}
+	if store.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", store.Len())
+	}
+}
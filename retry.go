@@ -0,0 +1,115 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// transientReasons are the BigQuery error reasons that indicate a temporary
+// problem, see https://cloud.google.com/bigquery/docs/error-messages
+var transientReasons = map[string]bool{
+	"backendError":         true,
+	"internalError":        true,
+	"jobBackendError":      true,
+	"jobInternalError":     true,
+	"rateLimitExceeded":    true,
+	"jobRateLimitExceeded": true,
+}
+
+// WithRetry retries failed queries up to the given number of attempts in
+// total. The backoff is doubled after every attempt. By default only
+// transient errors are retried, use Query.RetryIf to change this per query.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithRetry(3, time.Second))
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retryAttempts = attempts
+		c.retryBackoff = backoff
+	}
+}
+
+// RetryIf overrides which errors are retried for this query, for example to
+// retry DML statements that failed on a concurrent update, but not on quota
+// errors. The number of attempts and the backoff are configured on the
+// client with WithRetry. It returns the query to allow chaining.
+//
+// Example:
+//
+//	q := client.Query("UPDATE $table SET status = @status WHERE id = @id")
+//	q.RetryIf(func(err error) bool {
+//	    return strings.Contains(err.Error(), "concurrent update")
+//	})
+//	result, err := q.Exec(ctx)
+func (q *Query) RetryIf(retryable func(err error) bool) *Query {
+	q.retryable = retryable
+	return q
+}
+
+// isTransient checks if the error is a temporary BigQuery error that is
+// likely to succeed when retried.
+func isTransient(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		for _, item := range apiErr.Errors {
+			if transientReasons[item.Reason] {
+				return true
+			}
+		}
+		return false
+	}
+	var bqErr *bigquery.Error
+	if errors.As(err, &bqErr) {
+		return transientReasons[bqErr.Reason]
+	}
+	return false
+}
+
+// permanentError wraps an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// retry calls fn until it succeeds, the error is not retryable or the
+// attempts configured on the client are used up.
+func (q *Query) retry(ctx context.Context, fn func() error) error {
+	attempts, backoff := 1, time.Duration(0)
+	if q.client != nil && q.client.retryAttempts > 1 {
+		attempts, backoff = q.client.retryAttempts, q.client.retryBackoff
+	}
+	retryable := q.retryable
+	if retryable == nil {
+		retryable = isTransient
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if permanent, ok := err.(permanentError); ok {
+			return permanent.err
+		}
+		if err == nil || attempt >= attempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
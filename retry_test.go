@@ -0,0 +1,82 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"service unavailable", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"too many requests", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"backend error reason", &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "backendError"}}}, true},
+		{"invalid query", &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "invalidQuery"}}}, false},
+		{"job rate limit", &bigquery.Error{Reason: "jobRateLimitExceeded"}, true},
+		{"quota exceeded", &bigquery.Error{Reason: "quotaExceeded"}, false},
+		{"wrapped", errors.Join(ErrJobFailed, &bigquery.Error{Reason: "internalError"}), true},
+		{"other error", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		reason    string
+		retryIf   func(error) bool
+		attempts  int
+		wantCalls int
+	}{
+		{"no retry configured", "jobBackendError", nil, 0, 1},
+		{"transient error", "jobBackendError", nil, 3, 3},
+		{"permanent error", "invalidQuery", nil, 3, 1},
+		{"custom predicate retries", "invalidQuery", func(error) bool { return true }, 2, 2},
+		{"custom predicate stops", "jobBackendError", func(error) bool { return false }, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBigQuery{errorResult: tt.reason}
+			client := newFakeClient(t, fake).Configure(WithRetry(tt.attempts, 0))
+			q := client.Query("UPDATE t SET a = 1 WHERE true")
+			if tt.retryIf != nil {
+				q.RetryIf(tt.retryIf)
+			}
+			if _, err := q.Exec(context.Background()); !errors.Is(err, ErrJobFailed) {
+				t.Fatalf("Exec() error = %v, want %v", err, ErrJobFailed)
+			}
+			if got := len(fake.executedQueries()); got != tt.wantCalls {
+				t.Errorf("executed %d queries, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryCanceledContext(t *testing.T) {
+	client := (&Client{}).Configure(WithRetry(3, 0))
+	q := client.Query("SELECT 1")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := q.retry(ctx, func() error {
+		calls++
+		return &bigquery.Error{Reason: "backendError"}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("retry() = %v after %d calls, want error after 1 call", err, calls)
+	}
+}
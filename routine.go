@@ -0,0 +1,427 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// argumentNameRegex matches the names of routine arguments and struct fields
+var argumentNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dataTypeTokenRegex splits a data type into names, numbers and punctuation
+var dataTypeTokenRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*|[0-9]+|[<>(),]|\S`)
+
+// scalarTypes are the data types without type parameters, and parameterizedTypes
+// are the data types that accept (precision, scale) or (length) parameters
+var (
+	scalarTypes = []string{"INT64", "INT", "SMALLINT", "INTEGER", "BIGINT", "TINYINT", "BYTEINT",
+		"FLOAT64", "BOOL", "BOOLEAN", "DATE", "DATETIME", "TIME", "TIMESTAMP", "GEOGRAPHY", "INTERVAL", "JSON"}
+	parameterizedTypes = []string{"STRING", "BYTES", "NUMERIC", "DECIMAL", "BIGNUMERIC", "BIGDECIMAL"}
+)
+
+// routineKind is the kind of routine a RoutineBuilder creates.
+type routineKind int
+
+const (
+	procedureRoutine routineKind = iota
+	functionRoutine
+)
+
+// argumentMode is the mode of a procedure argument.
+type argumentMode string
+
+const (
+	inArgument    argumentMode = ""
+	outArgument   argumentMode = "OUT "
+	inOutArgument argumentMode = "INOUT "
+)
+
+// routineArgument is an argument of a routine with its rendered data type.
+type routineArgument struct {
+	mode     argumentMode
+	name     string
+	dataType string
+}
+
+// RoutineBuilder builds a CREATE PROCEDURE or CREATE FUNCTION statement.
+// Use Client.CreateProcedure or Client.CreateFunction to create one.
+// Invalid names, data types or bodies are reported by SQL and Exec.
+type RoutineBuilder struct {
+	client      *Client
+	kind        routineKind
+	dataset     string
+	name        string
+	orReplace   bool
+	ifNotExists bool
+	args        []routineArgument
+	returns     string
+	body        string
+	javaScript  bool
+	libraries   []string
+	errs        []error
+}
+
+// CreateProcedure returns a builder for a CREATE PROCEDURE statement. The
+// dataset and routine IDs are validated (with the rules of DatasetID and
+// RoutineID) and quoted, and the argument types are validated. The body is
+// a SQL template with $identifiers, that is run between BEGIN and END.
+//
+// Example:
+//
+//	err := client.CreateProcedure("analytics", "refresh_"+tenant).
+//	    OrReplace().
+//	    Arg("day", "DATE").
+//	    OutArg("refreshed", "INT64").
+//	    SQLBody("DELETE FROM $table WHERE day = day; SET refreshed = @@row_count;",
+//	        map[string]any{"$table": "analytics.daily_" + tenant}).
+//	    Exec(ctx)
+func (c *Client) CreateProcedure(dataset, procedure string) *RoutineBuilder {
+	return &RoutineBuilder{client: c, kind: procedureRoutine, dataset: dataset, name: procedure}
+}
+
+// CreateFunction returns a builder for a CREATE FUNCTION statement of a
+// user-defined function. The dataset and routine IDs are validated (with
+// the rules of DatasetID and RoutineID) and quoted, and the argument and
+// return types are validated. The body is either a SQL expression template
+// with $identifiers (see SQLBody), or JavaScript code that is escaped as a
+// string literal (see JavaScriptBody).
+//
+// Example:
+//
+//	err := client.CreateFunction("analytics", "normalize_email").
+//	    Arg("email", "STRING").
+//	    Returns("STRING").
+//	    SQLBody("LOWER(TRIM(email))", nil).
+//	    Exec(ctx)
+func (c *Client) CreateFunction(dataset, function string) *RoutineBuilder {
+	return &RoutineBuilder{client: c, kind: functionRoutine, dataset: dataset, name: function}
+}
+
+// OrReplace replaces the routine when it exists.
+func (r *RoutineBuilder) OrReplace() *RoutineBuilder {
+	r.orReplace = true
+	return r
+}
+
+// IfNotExists only creates the routine when it does not exist.
+func (r *RoutineBuilder) IfNotExists() *RoutineBuilder {
+	r.ifNotExists = true
+	return r
+}
+
+// Arg adds an (input) argument with the data type, such as INT64 or
+// ARRAY<STRING>. Functions also accept ANY TYPE.
+func (r *RoutineBuilder) Arg(name, dataType string) *RoutineBuilder {
+	return r.arg(inArgument, name, dataType)
+}
+
+// OutArg adds an output argument with the data type to a procedure.
+func (r *RoutineBuilder) OutArg(name, dataType string) *RoutineBuilder {
+	return r.arg(outArgument, name, dataType)
+}
+
+// InOutArg adds an input and output argument with the data type to a
+// procedure.
+func (r *RoutineBuilder) InOutArg(name, dataType string) *RoutineBuilder {
+	return r.arg(inOutArgument, name, dataType)
+}
+
+// arg validates and adds the argument.
+func (r *RoutineBuilder) arg(mode argumentMode, name, dataType string) *RoutineBuilder {
+	if mode != inArgument && r.kind != procedureRoutine {
+		return r.fail(fmt.Errorf("%w: only procedures have %sarguments", ErrInvalidDDL, mode))
+	}
+	if !argumentNameRegex.MatchString(name) {
+		return r.fail(fmt.Errorf("%w: %q is not a valid argument name", ErrInvalidDDL, name))
+	}
+	rendered, err := renderDataType(dataType, r.kind == functionRoutine)
+	if err != nil {
+		return r.fail(fmt.Errorf("argument %s: %w", name, err))
+	}
+	r.args = append(r.args, routineArgument{mode: mode, name: name, dataType: rendered})
+	return r
+}
+
+// Returns sets the return type of a function. It is optional for SQL
+// functions and required for JavaScript functions.
+func (r *RoutineBuilder) Returns(dataType string) *RoutineBuilder {
+	if r.kind != functionRoutine {
+		return r.fail(fmt.Errorf("%w: only functions have a return type", ErrInvalidDDL))
+	}
+	rendered, err := renderDataType(dataType, false)
+	if err != nil {
+		return r.fail(fmt.Errorf("return type: %w", err))
+	}
+	r.returns = rendered
+	return r
+}
+
+// SQLBody sets the body of the routine to the SQL template with the
+// $identifier values in params, that are validated and quoted as in a
+// query. The body of a procedure is a list of statements, the body of a
+// function is an expression. Routine bodies can't contain @named or ?
+// positional parameters; they refer to their arguments by name.
+func (r *RoutineBuilder) SQLBody(sql string, params map[string]any) *RoutineBuilder {
+	body, err := NewFragment(sql, params)
+	if err != nil {
+		return r.fail(fmt.Errorf("body: %w", err))
+	}
+	r.body, r.javaScript = body.sql, false
+	return r
+}
+
+// JavaScriptBody sets the body of a function to the JavaScript code, that
+// is escaped as a string literal. The libraries are gs:// URIs of
+// JavaScript files that the code uses.
+func (r *RoutineBuilder) JavaScriptBody(code string, libraries ...string) *RoutineBuilder {
+	if r.kind != functionRoutine {
+		return r.fail(fmt.Errorf("%w: only functions have a JavaScript body", ErrInvalidDDL))
+	}
+	for _, uri := range libraries {
+		if err := validateGCSURI(uri); err != nil {
+			return r.fail(fmt.Errorf("library: %w", err))
+		}
+	}
+	r.body, r.javaScript, r.libraries = code, true, libraries
+	return r
+}
+
+// fail records an error that occurred building the routine.
+func (r *RoutineBuilder) fail(err error) *RoutineBuilder {
+	r.errs = append(r.errs, err)
+	return r
+}
+
+// statement renders the CREATE statement, with $dataset.$routine as the
+// name of the routine.
+func (r *RoutineBuilder) statement() (string, error) {
+	if err := errors.Join(r.errs...); err != nil {
+		return "", err
+	}
+	if r.orReplace && r.ifNotExists {
+		return "", fmt.Errorf("%w: IfNotExists and OrReplace can't be combined", ErrInvalidDDL)
+	}
+	if r.body == "" {
+		return "", fmt.Errorf("%w: routine has no body", ErrInvalidDDL)
+	}
+	if r.javaScript && r.returns == "" {
+		return "", fmt.Errorf("%w: JavaScript functions need a return type", ErrInvalidDDL)
+	}
+	args := make([]string, len(r.args))
+	for i, arg := range r.args {
+		if r.javaScript && arg.dataType == "ANY TYPE" {
+			return "", fmt.Errorf("%w: JavaScript functions can't have ANY TYPE arguments", ErrInvalidDDL)
+		}
+		args[i] = string(arg.mode) + arg.name + " " + arg.dataType
+	}
+	var sql strings.Builder
+	sql.WriteString("CREATE ")
+	if r.orReplace {
+		sql.WriteString("OR REPLACE ")
+	}
+	if r.kind == procedureRoutine {
+		sql.WriteString("PROCEDURE ")
+	} else {
+		sql.WriteString("FUNCTION ")
+	}
+	if r.ifNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString("$dataset.$routine(" + strings.Join(args, ", ") + ")")
+	if r.returns != "" {
+		sql.WriteString(" RETURNS " + r.returns)
+	}
+	switch {
+	case r.kind == procedureRoutine:
+		sql.WriteString(" BEGIN\n$body\nEND")
+	case r.javaScript:
+		sql.WriteString(" LANGUAGE js")
+		if len(r.libraries) > 0 {
+			libraries := make([]string, len(r.libraries))
+			for i, uri := range r.libraries {
+				libraries[i] = EscapeStringLiteral(uri)
+			}
+			sql.WriteString(" OPTIONS (library = [" + strings.Join(libraries, ", ") + "])")
+		}
+		sql.WriteString(" AS $body")
+	default:
+		sql.WriteString(" AS ($body)")
+	}
+	return sql.String(), nil
+}
+
+// query returns the CREATE query with the names and body bound.
+func (r *RoutineBuilder) query() (*Query, error) {
+	sql, err := r.statement()
+	if err != nil {
+		return nil, err
+	}
+	body := Fragment{sql: r.body}
+	if r.javaScript {
+		body = Fragment{sql: EscapeStringLiteral(r.body)}
+	}
+	q := r.client.Query(sql)
+	q.SetParams(map[string]any{"$dataset": DatasetID(r.dataset), "$routine": RoutineID(r.name), "$body": body})
+	return q, nil
+}
+
+// SQL returns the translated CREATE statement without executing it.
+//
+// Returns an error if the routine or an identifier is not valid.
+func (r *RoutineBuilder) SQL() (string, error) {
+	q, err := r.query()
+	if err == nil {
+		err = q.translate()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create routine: %w", err)
+	}
+	return q.QueryConfig.Q, nil
+}
+
+// Exec runs the CREATE statement.
+//
+// Returns an error if the routine or an identifier is not valid, or if the
+// statement fails.
+func (r *RoutineBuilder) Exec(ctx context.Context) error {
+	q, err := r.query()
+	if err == nil {
+		_, err = q.Exec(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create routine: %w", err)
+	}
+	return nil
+}
+
+// renderDataType validates the data type and renders it in upper case with
+// normalized spacing. ANY TYPE is only accepted when anyType is set.
+//
+// Returns an error wrapping ErrInvalidDDL if the data type is not valid.
+func renderDataType(dataType string, anyType bool) (string, error) {
+	tokens := dataTypeTokenRegex.FindAllString(dataType, -1)
+	if anyType && len(tokens) == 2 && strings.EqualFold(tokens[0], "ANY") && strings.EqualFold(tokens[1], "TYPE") {
+		return "ANY TYPE", nil
+	}
+	p := &dataTypeParser{tokens: tokens}
+	rendered, ok := p.parseType()
+	if !ok || p.pos != len(tokens) {
+		return "", fmt.Errorf("%w: %q is not a valid data type", ErrInvalidDDL, dataType)
+	}
+	return rendered, nil
+}
+
+// dataTypeParser parses the tokens of a data type.
+type dataTypeParser struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the next token, or "" at the end.
+func (p *dataTypeParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+// peek returns the next token without consuming it, or "" at the end.
+func (p *dataTypeParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// parseType parses a scalar, ARRAY or STRUCT type.
+func (p *dataTypeParser) parseType() (string, bool) {
+	name := strings.ToUpper(p.next())
+	switch {
+	case name == "ARRAY":
+		if p.next() != "<" {
+			return "", false
+		}
+		element, ok := p.parseType()
+		if !ok || p.next() != ">" {
+			return "", false
+		}
+		return "ARRAY<" + element + ">", true
+	case name == "STRUCT":
+		if p.next() != "<" {
+			return "", false
+		}
+		var fields []string
+		for {
+			field := p.next()
+			if !argumentNameRegex.MatchString(field) {
+				return "", false
+			}
+			fieldType, ok := p.parseType()
+			if !ok {
+				return "", false
+			}
+			fields = append(fields, string(backtick)+field+string(backtick)+" "+fieldType)
+			if separator := p.next(); separator == ">" {
+				return "STRUCT<" + strings.Join(fields, ", ") + ">", true
+			} else if separator != "," {
+				return "", false
+			}
+		}
+	case slices.Contains(scalarTypes, name):
+		return name, true
+	case slices.Contains(parameterizedTypes, name):
+		if p.peek() != "(" {
+			return name, true
+		}
+		p.next()
+		params := []string{}
+		for {
+			param := p.next()
+			if param == "" || param[0] < '0' || param[0] > '9' {
+				return "", false
+			}
+			params = append(params, param)
+			if separator := p.next(); separator == ")" {
+				break
+			} else if separator != "," || len(params) == 2 {
+				return "", false
+			}
+		}
+		return name + "(" + strings.Join(params, ", ") + ")", true
+	default:
+		return "", false
+	}
+}
+
+// Call calls the stored procedure with the arguments, that are bound as
+// the named parameters @arg0, @arg1 and so on, and returns an iterator over the result set of
+// the last statement of the procedure that returns rows (if any). The
+// dataset and routine IDs are validated (with the rules of DatasetID and
+// RoutineID) and quoted.
+//
+// Example:
+//
+//	it, err := client.Call(ctx, "analytics", "report_"+tenant, day, 10)
+//	// CALL `analytics`.`report_acme`(@arg0, @arg1)
+//
+// Returns an error if an identifier is not valid, or if the call fails.
+func (c *Client) Call(ctx context.Context, dataset, routine string, args ...any) (*RowIterator, error) {
+	params := map[string]any{"$dataset": DatasetID(dataset), "$routine": RoutineID(routine)}
+	placeholders := make([]string, len(args))
+	for i, arg := range args {
+		placeholders[i] = fmt.Sprintf("@arg%d", i)
+		params[placeholders[i]] = arg
+	}
+	q := c.Query("CALL $dataset.$routine(" + strings.Join(placeholders, ", ") + ")")
+	q.SetParams(params)
+	it, err := q.ReadRows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", routine, err)
+	}
+	return it, nil
+}
@@ -0,0 +1,124 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestClientCreateProcedure(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	err := client.CreateProcedure("analytics", "refresh_acme").
+		OrReplace().
+		Arg("day", "date").
+		OutArg("refreshed", "INT64").
+		SQLBody("DELETE FROM $table WHERE day = day; SET refreshed = @@row_count;",
+			map[string]any{"$table": "analytics.daily_acme"}).
+		Exec(context.Background())
+	if err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	expected := "CREATE OR REPLACE PROCEDURE `analytics`.`refresh_acme`(day DATE, OUT refreshed INT64) BEGIN\n" +
+		"DELETE FROM `analytics`.`daily_acme` WHERE day = day; SET refreshed = @@row_count;\nEND"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Exec() queries = %q, want %q", queries, expected)
+	}
+}
+
+func TestRoutineBuilderSQL(t *testing.T) {
+	client := &Client{}
+	tests := []struct {
+		name     string
+		routine  *RoutineBuilder
+		expected string
+		err      error
+	}{
+		{"sql function",
+			client.CreateFunction("analytics", "normalize_email").Arg("email", "STRING").Returns("STRING").SQLBody("LOWER(TRIM(email))", nil),
+			"CREATE FUNCTION `analytics`.`normalize_email`(email STRING) RETURNS STRING AS (LOWER(TRIM(email)))", nil},
+		{"templated function",
+			client.CreateFunction("analytics", "first").IfNotExists().Arg("values", "any type").SQLBody("values[SAFE_OFFSET(0)]", nil),
+			"CREATE FUNCTION IF NOT EXISTS `analytics`.`first`(values ANY TYPE) AS (values[SAFE_OFFSET(0)])", nil},
+		{"javascript function",
+			client.CreateFunction("analytics", "parse").Arg("s", "string(100)").Returns("array<struct<key string, value numeric(10,2)>>").
+				JavaScriptBody("return JSON.parse(s || '[]');", "gs://my-bucket/lib.js"),
+			"CREATE FUNCTION `analytics`.`parse`(s STRING(100)) RETURNS ARRAY<STRUCT<`key` STRING, `value` NUMERIC(10, 2)>> " +
+				"LANGUAGE js OPTIONS (library = ['gs://my-bucket/lib.js']) AS 'return JSON.parse(s || \\'[]\\');'", nil},
+		{"invalid routine name", client.CreateFunction("analytics", "f`; DROP TABLE x; --").SQLBody("1", nil), "", ErrIdentifierInvalidChars},
+		{"invalid dataset", client.CreateProcedure("my-dataset", "p").SQLBody("SELECT 1;", nil), "", ErrIdentifierInvalidChars},
+		{"invalid argument name", client.CreateFunction("analytics", "f").Arg("x INT64) AS (1); --", "INT64").SQLBody("1", nil), "", ErrInvalidDDL},
+		{"injected data type", client.CreateFunction("analytics", "f").Arg("x", "INT64) AS (1) --").SQLBody("1", nil), "", ErrInvalidDDL},
+		{"unknown data type", client.CreateFunction("analytics", "f").Arg("x", "VARCHAR").SQLBody("1", nil), "", ErrInvalidDDL},
+		{"unclosed array", client.CreateFunction("analytics", "f").Arg("x", "ARRAY<INT64").SQLBody("1", nil), "", ErrInvalidDDL},
+		{"too many type parameters", client.CreateFunction("analytics", "f").Arg("x", "NUMERIC(1, 2, 3)").SQLBody("1", nil), "", ErrInvalidDDL},
+		{"any type procedure argument", client.CreateProcedure("analytics", "p").Arg("x", "ANY TYPE").SQLBody("SELECT x;", nil), "", ErrInvalidDDL},
+		{"function out argument", client.CreateFunction("analytics", "f").OutArg("x", "INT64").SQLBody("1", nil), "", ErrInvalidDDL},
+		{"procedure return type", client.CreateProcedure("analytics", "p").Returns("INT64").SQLBody("SELECT 1;", nil), "", ErrInvalidDDL},
+		{"procedure javascript", client.CreateProcedure("analytics", "p").JavaScriptBody("return 1;"), "", ErrInvalidDDL},
+		{"javascript without return type", client.CreateFunction("analytics", "f").JavaScriptBody("return 1;"), "", ErrInvalidDDL},
+		{"javascript any type", client.CreateFunction("analytics", "f").Arg("x", "ANY TYPE").Returns("INT64").JavaScriptBody("return 1;"), "", ErrInvalidDDL},
+		{"invalid library", client.CreateFunction("analytics", "f").Returns("INT64").JavaScriptBody("return 1;", "http://example.com/lib.js"), "", ErrInvalidURI},
+		{"no body", client.CreateFunction("analytics", "f"), "", ErrInvalidDDL},
+		{"body with parameter", client.CreateFunction("analytics", "f").SQLBody("@x + 1", nil), "", ErrInvalidParameterName},
+		{"if not exists and or replace", client.CreateFunction("analytics", "f").OrReplace().IfNotExists().SQLBody("1", nil), "", ErrInvalidDDL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := tt.routine.SQL()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SQL() error = %v, want %v", err, tt.err)
+			}
+			if sql != tt.expected {
+				t.Errorf("SQL() = %q, want %q", sql, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClientCall(t *testing.T) {
+	fake := &fakeBigQuery{
+		schema: []map[string]any{{"name": "n", "type": "INTEGER"}},
+		rows:   [][]any{{"7"}},
+	}
+	client := newFakeClient(t, fake)
+	it, err := client.Call(context.Background(), "analytics", "report_acme", "2024-01-01", 10)
+	if err != nil {
+		t.Fatalf("Call() unexpected error: %v", err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	if len(row) != 1 || row[0] != int64(7) {
+		t.Errorf("Next() row = %v, want [7]", row)
+	}
+	config := fake.jobs[it.SourceJob().ID()]["query"].(map[string]any)
+	if config["query"] != "CALL `analytics`.`report_acme`(@arg0, @arg1)" {
+		t.Errorf("submitted query = %v", config["query"])
+	}
+	params, _ := config["queryParameters"].([]any)
+	var names []string
+	for _, p := range params {
+		names = append(names, p.(map[string]any)["name"].(string))
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"arg0", "arg1"}) {
+		t.Errorf("submitted parameters = %v, want arg0 and arg1", config["queryParameters"])
+	}
+
+	if _, err := client.Call(context.Background(), "analytics", "r`(); DROP TABLE x; --"); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("Call() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if _, err := client.Call(context.Background(), "analytics", "cleanup"); err != nil {
+		t.Errorf("Call() unexpected error: %v", err)
+	}
+	if queries := fake.executedQueries(); queries[len(queries)-1] != "CALL `analytics`.`cleanup`()" {
+		t.Errorf("queries = %q", queries)
+	}
+}
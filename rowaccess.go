@@ -0,0 +1,135 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RowAccessPolicy configures the CREATE ROW ACCESS POLICY statement of
+// CreateRowAccessPolicy.
+type RowAccessPolicy struct {
+	// OrReplace replaces the policy when it exists
+	OrReplace bool
+	// IfNotExists only creates the policy when it does not exist
+	IfNotExists bool
+	// Grantees are the IAM members the policy grants access to, such as
+	// user:alice@example.com or group:sales@example.com
+	Grantees []string
+	// Filter is the SQL template of the filter expression, such as
+	// "region = @region"
+	Filter string
+	// Params are the values of the $identifiers and @named parameters of
+	// the filter; @named parameters are rendered as escaped literals, as
+	// DDL statements don't accept query parameters
+	Params map[string]any
+}
+
+// CreateRowAccessPolicy creates a row access policy on the table, that
+// grants the grantees access to the rows that match the filter. The policy
+// name, dataset and table IDs are validated and quoted, the grantees are
+// validated as IAM members, and the @named parameters of the filter are
+// rendered as escaped literals.
+//
+// Example:
+//
+//	err := client.CreateRowAccessPolicy(ctx, "sales", "orders", "region_"+region, saferbq.RowAccessPolicy{
+//	    OrReplace: true,
+//	    Grantees:  []string{"group:sales-" + region + "@example.com"},
+//	    Filter:    "region = @region",
+//	    Params:    map[string]any{"@region": region},
+//	})
+//	// CREATE OR REPLACE ROW ACCESS POLICY `region_emea` ON `sales`.`orders`
+//	//   GRANT TO ('group:sales-emea@example.com') FILTER USING (region = 'emea')
+//
+// Returns an error wrapping ErrInvalidDDL if the policy is not valid, an
+// error wrapping ErrInvalidPrincipal if a grantee is not valid, an error
+// if the filter or an identifier is not valid, or an error if the
+// statement fails.
+func (c *Client) CreateRowAccessPolicy(ctx context.Context, dataset, table, policy string, p RowAccessPolicy) error {
+	sql, filter, err := createRowAccessPolicySQL(p, c.redactValues)
+	if err != nil {
+		return fmt.Errorf("failed to create row access policy: %w", err)
+	}
+	q := c.Query(sql)
+	q.SetParams(map[string]any{
+		"$policy":  Ident{kind: policyIdent, value: policy},
+		"$dataset": DatasetID(dataset),
+		"$table":   TableID(table),
+		"$filter":  filter,
+	})
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create row access policy: %w", err)
+	}
+	return nil
+}
+
+// DropRowAccessPolicy drops the row access policy from the table. When
+// ifExists is set, a missing policy is not an error.
+//
+// Returns an error if an identifier is not valid, or if the statement
+// fails.
+func (c *Client) DropRowAccessPolicy(ctx context.Context, dataset, table, policy string, ifExists bool) error {
+	sql := "DROP ROW ACCESS POLICY $policy ON $dataset.$table"
+	if ifExists {
+		sql = "DROP ROW ACCESS POLICY IF EXISTS $policy ON $dataset.$table"
+	}
+	q := c.Query(sql)
+	q.SetParams(map[string]any{
+		"$policy":  Ident{kind: policyIdent, value: policy},
+		"$dataset": DatasetID(dataset),
+		"$table":   TableID(table),
+	})
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to drop row access policy: %w", err)
+	}
+	return nil
+}
+
+// DropAllRowAccessPolicies drops all row access policies from the table.
+//
+// Returns an error if an identifier is not valid, or if the statement
+// fails.
+func (c *Client) DropAllRowAccessPolicies(ctx context.Context, dataset, table string) error {
+	if err := c.execTableStatement(ctx, "DROP ALL ROW ACCESS POLICIES ON $dataset.$table", dataset, table); err != nil {
+		return fmt.Errorf("failed to drop row access policies: %w", err)
+	}
+	return nil
+}
+
+// createRowAccessPolicySQL renders the CREATE ROW ACCESS POLICY statement,
+// with $policy, $dataset.$table and $filter as placeholders, and returns
+// the filter. When redact is set, invalid grantees are left out of the error.
+func createRowAccessPolicySQL(p RowAccessPolicy, redact bool) (string, Fragment, error) {
+	if p.OrReplace && p.IfNotExists {
+		return "", Fragment{}, fmt.Errorf("%w: IfNotExists and OrReplace can't be combined", ErrInvalidDDL)
+	}
+	if len(p.Grantees) == 0 {
+		return "", Fragment{}, fmt.Errorf("%w: row access policy has no grantees", ErrInvalidDDL)
+	}
+	grantees := make([]string, len(p.Grantees))
+	for i, grantee := range p.Grantees {
+		if err := validatePrincipal(grantee, redact); err != nil {
+			return "", Fragment{}, err
+		}
+		grantees[i] = EscapeStringLiteral(grantee)
+	}
+	if p.Filter == "" {
+		return "", Fragment{}, fmt.Errorf("%w: row access policy has no filter", ErrInvalidDDL)
+	}
+	filter, err := inlineFragment(p.Filter, p.Params)
+	if err != nil {
+		return "", Fragment{}, fmt.Errorf("filter: %w", err)
+	}
+	var sql strings.Builder
+	sql.WriteString("CREATE ")
+	if p.OrReplace {
+		sql.WriteString("OR REPLACE ")
+	}
+	sql.WriteString("ROW ACCESS POLICY ")
+	if p.IfNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString("$policy ON $dataset.$table GRANT TO (" + strings.Join(grantees, ", ") + ") FILTER USING ($filter)")
+	return sql.String(), filter, nil
+}
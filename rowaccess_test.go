@@ -0,0 +1,74 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClientRowAccessPolicies(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	err := client.CreateRowAccessPolicy(ctx, "sales", "orders", "region_emea", RowAccessPolicy{
+		OrReplace: true,
+		Grantees:  []string{"group:sales-emea@example.com", "user:alice@example.com"},
+		Filter:    "$column = @region",
+		Params:    map[string]any{"$column": "region", "@region": "emea"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRowAccessPolicy() unexpected error: %v", err)
+	}
+	if err := client.DropRowAccessPolicy(ctx, "sales", "orders", "region_emea", true); err != nil {
+		t.Fatalf("DropRowAccessPolicy() unexpected error: %v", err)
+	}
+	if err := client.DropAllRowAccessPolicies(ctx, "sales", "orders"); err != nil {
+		t.Fatalf("DropAllRowAccessPolicies() unexpected error: %v", err)
+	}
+	expected := []string{
+		"CREATE OR REPLACE ROW ACCESS POLICY `region_emea` ON `sales`.`orders` " +
+			"GRANT TO ('group:sales-emea@example.com', 'user:alice@example.com') FILTER USING (`region` = 'emea')",
+		"DROP ROW ACCESS POLICY IF EXISTS `region_emea` ON `sales`.`orders`",
+		"DROP ALL ROW ACCESS POLICIES ON `sales`.`orders`",
+	}
+	queries := fake.executedQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("queries = %q, want %q", queries, expected)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], expected[i])
+		}
+	}
+}
+
+func TestClientCreateRowAccessPolicyValidation(t *testing.T) {
+	valid := RowAccessPolicy{Grantees: []string{"domain:example.com"}, Filter: "TRUE"}
+	tests := []struct {
+		name   string
+		policy string
+		p      RowAccessPolicy
+		err    error
+	}{
+		{"invalid policy name", "region-emea", valid, ErrIdentifierInvalidChars},
+		{"no grantees", "p", RowAccessPolicy{Filter: "TRUE"}, ErrInvalidDDL},
+		{"invalid grantee", "p", RowAccessPolicy{Grantees: []string{"user:a@example.com') FILTER USING (TRUE) --"}, Filter: "TRUE"}, ErrInvalidPrincipal},
+		{"no filter", "p", RowAccessPolicy{Grantees: []string{"allUsers"}}, ErrInvalidDDL},
+		{"missing filter parameter", "p", RowAccessPolicy{Grantees: []string{"allUsers"}, Filter: "region = @region"}, ErrParameterNotProvided},
+		{"if not exists and or replace", "p", RowAccessPolicy{OrReplace: true, IfNotExists: true, Grantees: []string{"allUsers"}, Filter: "TRUE"}, ErrInvalidDDL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBigQuery{}
+			client := newFakeClient(t, fake)
+			err := client.CreateRowAccessPolicy(context.Background(), "sales", "orders", tt.policy, tt.p)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("CreateRowAccessPolicy() error = %v, want %v", err, tt.err)
+			}
+			if queries := fake.executedQueries(); len(queries) != 0 {
+				t.Errorf("CreateRowAccessPolicy() queries = %q, want none", queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// Script is a multi-statement script that ran with RunScript, with the
+// child jobs that BigQuery created for its statements.
+type Script struct {
+	// Job is the parent job of the script
+	Job *bigquery.Job
+	// Statements are the statements of the script in the order they ran
+	Statements []*ScriptStatement
+}
+
+// ScriptStatement is a statement of a script that ran as a child job.
+type ScriptStatement struct {
+	// Job is the child job of the statement
+	Job *bigquery.Job
+	// Statistics are the statistics of the child job, including the
+	// ScriptStatistics with the location of the statement in the script
+	Statistics *bigquery.JobStatistics
+}
+
+// RunScript translates the $identifier and @named parameters across the
+// whole multi-statement script, runs it and waits until it is done. The
+// returned Script exposes the statistics and results of every statement.
+//
+// Example:
+//
+//	script, err := client.RunScript(ctx, `
+//	    DECLARE cutoff DATE DEFAULT @cutoff;
+//	    DELETE FROM $table WHERE day < cutoff;
+//	    SELECT COUNT(*) AS remaining FROM $table;`,
+//	    map[string]any{"$table": "events", "@cutoff": cutoff})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, statement := range script.Statements {
+//	    fmt.Println(statement.Text(), statement.Statistics.TotalBytesProcessed)
+//	}
+//
+// Returns an error if parameter validation fails or if the script fails.
+// When the script fails after it started, the statements that ran are
+// returned together with the error.
+func (c *Client) RunScript(ctx context.Context, sql string, params map[string]any) (*Script, error) {
+	q := c.Query(sql)
+	q.SetParams(params)
+	job, err := q.RunAndWait(ctx)
+	if job == nil {
+		return nil, err
+	}
+	script, listErr := newScript(ctx, job)
+	if err != nil {
+		return script, err
+	}
+	if listErr != nil {
+		return nil, listErr
+	}
+	return script, nil
+}
+
+// newScript lists the child jobs of the script job, ordered by the time
+// they were created.
+func newScript(ctx context.Context, job *bigquery.Job) (*Script, error) {
+	script := &Script{Job: job}
+	it := job.Children(ctx)
+	for {
+		child, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statements of script %s: %w", job.ID(), err)
+		}
+		statement := &ScriptStatement{Job: child}
+		if status := child.LastStatus(); status != nil {
+			statement.Statistics = status.Statistics
+		}
+		script.Statements = append(script.Statements, statement)
+	}
+	slices.SortStableFunc(script.Statements, func(a, b *ScriptStatement) int {
+		return a.created().Compare(b.created())
+	})
+	return script, nil
+}
+
+// Read returns the results of the last statement of the script that
+// returned results, see bigquery.Job.Read.
+func (s *Script) Read(ctx context.Context) (*RowIterator, error) {
+	rows, err := s.Job.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRowIterator(rows), nil
+}
+
+// Read returns the results of the statement.
+func (s *ScriptStatement) Read(ctx context.Context) (*RowIterator, error) {
+	rows, err := s.Job.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRowIterator(rows), nil
+}
+
+// Text returns the SQL text of the statement (the innermost statement for
+// calls to procedures), or an empty string when BigQuery did not report it.
+func (s *ScriptStatement) Text() string {
+	if s.Statistics == nil || s.Statistics.ScriptStatistics == nil {
+		return ""
+	}
+	frames := s.Statistics.ScriptStatistics.StackFrames
+	if len(frames) == 0 {
+		return ""
+	}
+	return frames[0].Text
+}
+
+// created returns the time the child job was created.
+func (s *ScriptStatement) created() time.Time {
+	if s.Statistics == nil {
+		return time.Time{}
+	}
+	return s.Statistics.CreationTime
+}
@@ -0,0 +1,89 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestRunScript(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := newReadFake()
+	// Jobs are listed newest first
+	fake.children = []map[string]any{
+		childJob("child-2", "SELECT id, name FROM `events`", created.Add(time.Second)),
+		childJob("child-1", "DELETE FROM `events` WHERE id > @max_id", created),
+	}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	script, err := client.RunScript(ctx,
+		"DELETE FROM $table WHERE id > @max_id;\nSELECT id, name FROM $table;",
+		map[string]any{"$table": "events", "@max_id": 2})
+	if err != nil {
+		t.Fatalf("RunScript() unexpected error: %v", err)
+	}
+	want := []string{"DELETE FROM `events` WHERE id > @max_id;\nSELECT id, name FROM `events`;"}
+	if queries := fake.executedQueries(); !slices.Equal(queries, want) {
+		t.Errorf("executed queries = %q, want %q", queries, want)
+	}
+	if !slices.Equal(fake.parentJobIDs, []string{script.Job.ID()}) {
+		t.Errorf("listed parents = %v, want %v", fake.parentJobIDs, []string{script.Job.ID()})
+	}
+
+	if len(script.Statements) != 2 {
+		t.Fatalf("statements = %d, want 2", len(script.Statements))
+	}
+	var ids, texts []string
+	for _, statement := range script.Statements {
+		ids = append(ids, statement.Job.ID())
+		texts = append(texts, statement.Text())
+	}
+	if want := []string{"child-1", "child-2"}; !slices.Equal(ids, want) {
+		t.Errorf("statement jobs = %v, want %v", ids, want)
+	}
+	if want := []string{"DELETE FROM `events` WHERE id > @max_id", "SELECT id, name FROM `events`"}; !slices.Equal(texts, want) {
+		t.Errorf("statement texts = %q, want %q", texts, want)
+	}
+	if kind := script.Statements[0].Statistics.ScriptStatistics.EvaluationKind; kind != "STATEMENT" {
+		t.Errorf("evaluation kind = %q, want STATEMENT", kind)
+	}
+
+	it, err := script.Statements[1].Read(ctx)
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil || len(row) != 2 || row[1] != "a" {
+		t.Errorf("Next() = %v, %v, want the first row", row, err)
+	}
+}
+
+func TestRunScriptInvalid(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	_, err := client.RunScript(context.Background(), "SELECT * FROM $table; SELECT 1;", nil)
+	if !errors.Is(err, ErrIdentifierNotProvided) {
+		t.Errorf("RunScript() error = %v, want ErrIdentifierNotProvided", err)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("executed queries = %v, want none", queries)
+	}
+}
+
+func TestRunScriptFailed(t *testing.T) {
+	fake := &fakeBigQuery{errorResult: "invalidQuery"}
+	fake.children = []map[string]any{childJob("child-1", "SELECT 1", time.Now())}
+	client := newFakeClient(t, fake)
+	script, err := client.RunScript(context.Background(), "SELECT 1; SELECT 2;", nil)
+	if !errors.Is(err, ErrJobFailed) {
+		t.Fatalf("RunScript() error = %v, want ErrJobFailed", err)
+	}
+	if script == nil || len(script.Statements) != 1 {
+		t.Errorf("RunScript() script = %v, want the statement that ran", script)
+	}
+}
@@ -2,8 +2,12 @@ package saferbq
 
 import (
 	"context"
+	"log/slog"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/bigquery"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/option"
 )
 
@@ -12,6 +16,92 @@ import (
 // for $identifier parameters in queries.
 type Client struct {
 	bigquery.Client
+	// lanes holds the concurrency limiters per execution lane
+	lanes [laneCount]semaphore
+	// maxConcurrent limits the concurrent queries over all lanes
+	maxConcurrent semaphore
+	// maxScanBytes is the maximum estimated bytes processed per query (0 is unlimited)
+	maxScanBytes int64
+	// maxBytesBilled is the default maximum bytes billed of queries (0 is the project default)
+	maxBytesBilled int64
+	// registerStatements keeps the prepared statements for the manifest
+	registerStatements bool
+	// statements holds the prepared statements by SQL, for the manifest
+	statements sync.Map
+	// tracerProvider creates the spans of the client (may be nil)
+	tracerProvider trace.TracerProvider
+	// logger logs every executed query (may be nil)
+	logger *slog.Logger
+	// normalizeKeywords uppercases reserved keywords in translated SQL
+	normalizeKeywords bool
+	// metrics records the executed queries (may be nil)
+	metrics *Metrics
+	// deferred holds the queries waiting for an off-peak window
+	deferred deferQueue
+	// retryAttempts is the maximum number of attempts per query (0 = no retries)
+	retryAttempts int
+	// retryBackoff is the delay before the first retry
+	retryBackoff time.Duration
+	// templateLabels labels every job with the template hash and caller
+	templateLabels bool
+	// redactValues keeps identifier values out of validation errors
+	redactValues bool
+	// quoteStyle selects how $identifier values with dotted paths are quoted
+	quoteStyle QuoteStyle
+	// clientOptions are the options the client was created with, they are
+	// reused for the Storage API clients
+	clientOptions []option.ClientOption
+	// storageRead downloads all results through the Storage Read API
+	storageRead bool
+	// storageReader enables the Storage Read API once
+	storageReader storageReader
+	// schemas caches the table schemas of TableSchema
+	schemas schemaCache
+	// resultStore caches the results of ReadCached (may be nil)
+	resultStore ResultStore
+	// cancelOnDone cancels the jobs of Run when their context is done
+	cancelOnDone bool
+	// scheduler decides when queries are submitted (may be nil)
+	scheduler Scheduler
+	// queryTimeout is the default timeout of new queries (0 is unbounded)
+	queryTimeout time.Duration
+	// hooks inspect or modify the queries around their translation
+	hooks []TranslationHook
+	// traceComments appends a sqlcommenter comment to the submitted SQL
+	traceComments bool
+	// traceCommentApp is the app key of the trace comments (may be empty)
+	traceCommentApp string
+	// autoUnnest rewrites IN @param to IN UNNEST(@param) for array values
+	autoUnnest bool
+	// namedPositionals converts ? placeholders into generated named parameters
+	namedPositionals bool
+	// lifetime is done when the client is closed, it stops the background
+	// work of the client (see background)
+	lifetime     context.Context
+	stop         context.CancelFunc
+	lifetimeOnce sync.Once
+}
+
+// Option configures the saferbq specific behavior of a Client.
+// Options are applied using Client.Configure.
+type Option func(*Client)
+
+// Configure applies the options to the client and returns the client, so
+// it can be chained to NewClient. It should be called before the client
+// is used to run queries.
+//
+// Example:
+//
+//	client, err := saferbq.NewClient(ctx, "my-project")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client.Configure(saferbq.WithLaneLimits(20, 2))
+func (c *Client) Configure(opts ...Option) *Client {
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // NewClient creates a new BigQuery client with saferbq enhancements.
@@ -30,7 +120,24 @@ func NewClient(ctx context.Context, projectID string, opts ...option.ClientOptio
 	if err != nil {
 		return nil, err
 	}
-	return &Client{*bqClient}, nil
+	return &Client{Client: *bqClient, clientOptions: opts}, nil
+}
+
+// Close stops the background work of the client, like waiting for the
+// jobs of Run, and closes the underlying BigQuery client.
+func (c *Client) Close() error {
+	c.background()
+	c.stop()
+	return c.Client.Close()
+}
+
+// background returns the context of the background work of the client,
+// which is done when the client is closed.
+func (c *Client) background() context.Context {
+	c.lifetimeOnce.Do(func() {
+		c.lifetime, c.stop = context.WithCancel(context.Background())
+	})
+	return c.lifetime
 }
 
 // Query creates a new Query with dollar-sign parameter support.
@@ -47,8 +154,11 @@ func NewClient(ctx context.Context, projectID string, opts ...option.ClientOptio
 //	}
 func (c *Client) Query(q string) *Query {
 	bq := c.Client.Query(q)
+	bq.JobTimeout = c.queryTimeout
 	return &Query{
 		Query:       *bq,
 		originalSQL: q,
+		client:      c,
+		timeout:     c.queryTimeout,
 	}
 }
@@ -0,0 +1,13 @@
+// Command saferbqcheck runs the saferbqcheck analyzer as a go vet tool.
+//
+//	go vet -vettool=$(which saferbqcheck) ./...
+package main
+
+import (
+	"github.com/mevdschee/saferbq/saferbqcheck"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(saferbqcheck.Analyzer)
+}
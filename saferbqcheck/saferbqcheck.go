@@ -0,0 +1,103 @@
+// Package saferbqcheck provides an analyzer that flags queries whose SQL is
+// built with fmt.Sprintf or string concatenation, as these bypass the
+// validation of $identifier parameters.
+//
+// Run it with go vet:
+//
+//	go install github.com/mevdschee/saferbq/saferbqcheck/cmd/saferbqcheck@latest
+//	go vet -vettool=$(which saferbqcheck) ./...
+package saferbqcheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// saferbqPath is the import path of the saferbq package
+const saferbqPath = "github.com/mevdschee/saferbq"
+
+// Analyzer flags calls to Client.Query and Client.Prepare of saferbq where
+// the SQL is built with fmt.Sprintf or string concatenation.
+var Analyzer = &analysis.Analyzer{
+	Name:     "saferbqcheck",
+	Doc:      "flag saferbq queries built with fmt.Sprintf or string concatenation, use $identifier parameters instead",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// checkedMethods are the methods of saferbq.Client that take SQL
+var checkedMethods = map[string]bool{
+	"Query":   true,
+	"Prepare": true,
+}
+
+// run reports all calls that pass dynamically built SQL to saferbq.
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if len(call.Args) != 1 || !isSaferbqCall(pass, call) {
+			return
+		}
+		arg := ast.Unparen(call.Args[0])
+		// Concatenated constants are safe
+		if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil {
+			return
+		}
+		switch {
+		case isSprintf(pass, arg):
+			pass.Reportf(arg.Pos(), "SQL built with fmt.Sprintf, use $identifier parameters instead")
+		case isConcatenation(arg):
+			pass.Reportf(arg.Pos(), "SQL built with string concatenation, use $identifier parameters instead")
+		}
+	})
+	return nil, nil
+}
+
+// isSaferbqCall checks if the call is a call to Client.Query or
+// Client.Prepare of saferbq.
+func isSaferbqCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !checkedMethods[sel.Sel.Name] {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != saferbqPath {
+		return false
+	}
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return false
+	}
+	ptr, ok := recv.Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Name() == "Client"
+}
+
+// isSprintf checks if the expression is a call to fmt.Sprintf.
+func isSprintf(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "fmt" && fn.Name() == "Sprintf"
+}
+
+// isConcatenation checks if the expression is a string concatenation.
+func isConcatenation(expr ast.Expr) bool {
+	binary, ok := expr.(*ast.BinaryExpr)
+	return ok && binary.Op == token.ADD
+}
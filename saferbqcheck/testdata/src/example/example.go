@@ -0,0 +1,22 @@
+package example
+
+import (
+	"fmt"
+
+	"github.com/mevdschee/saferbq"
+)
+
+const sampleClause = "TABLESAMPLE SYSTEM (@pct PERCENT)"
+
+type other struct{}
+
+func (o other) Query(sql string) {}
+
+func queries(client *saferbq.Client, table string) {
+	client.Query("SELECT * FROM $table")
+	client.Query("SELECT * FROM $table " + sampleClause)
+	client.Query(fmt.Sprintf("SELECT * FROM %s", table)) // want `SQL built with fmt.Sprintf, use \$identifier parameters instead`
+	client.Query("SELECT * FROM " + table)               // want `SQL built with string concatenation, use \$identifier parameters instead`
+	client.Prepare(("SELECT * FROM " + table))           // want `SQL built with string concatenation`
+	other{}.Query("SELECT * FROM " + table)
+}
@@ -0,0 +1,12 @@
+// Package saferbq is a stub of the saferbq package for the analyzer tests.
+package saferbq
+
+type Client struct{}
+
+type Query struct{}
+
+type Stmt struct{}
+
+func (c *Client) Query(sql string) *Query { return nil }
+
+func (c *Client) Prepare(sql string) (*Stmt, error) { return nil, nil }
@@ -0,0 +1,219 @@
+// Package saferbqtest provides test helpers for code that uses saferbq.
+//
+// FakeClient is a saferbq.Client that is connected to an in-memory fake of
+// the BigQuery API, so unit tests can assert on the exact SQL that saferbq
+// produces without hitting GCP.
+package saferbqtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/mevdschee/saferbq"
+	"google.golang.org/api/option"
+)
+
+// Query is a query as it was received by the fake BigQuery API.
+type Query struct {
+	// SQL is the translated SQL
+	SQL string
+	// Parameters are the query parameters (without $identifiers)
+	Parameters []Parameter
+	// DryRun is set when the query was submitted as dry run
+	DryRun bool
+}
+
+// Parameter is a query parameter as it was received by the fake BigQuery
+// API. Positional parameters have an empty name.
+type Parameter struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// FakeClient is a saferbq.Client that records all queries and answers them
+// with canned rows. It supports the complete Query, Run and Read surface of
+// the client, including prepared statements and dry runs.
+//
+// Use NewFakeClient() to create a new FakeClient instance.
+type FakeClient struct {
+	*saferbq.Client
+	mu      sync.Mutex
+	schema  bigquery.Schema
+	rows    [][]bigquery.Value
+	queries []Query
+	jobs    map[string]map[string]any
+}
+
+// NewFakeClient starts a fake BigQuery API and returns a client that is
+// connected to it. The fake is stopped when the test finishes.
+//
+// Example:
+//
+//	client := saferbqtest.NewFakeClient(t)
+//	client.SetRows(bigquery.Schema{{Name: "id", Type: bigquery.IntegerFieldType}}, []bigquery.Value{1})
+//
+//	q := client.Query("SELECT id FROM $table")
+//	q.SetParams(map[string]any{"$table": "users"})
+//	it, err := q.Read(ctx)
+//
+//	if got := client.LastQuery().SQL; got != "SELECT id FROM `users`" {
+//	    t.Errorf("SQL = %q", got)
+//	}
+func NewFakeClient(t testing.TB) *FakeClient {
+	t.Helper()
+	f := &FakeClient{jobs: map[string]map[string]any{}}
+	server := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(server.Close)
+	client, err := saferbq.NewClient(context.Background(), "test-project",
+		option.WithEndpoint(server.URL+"/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	f.Client = client
+	return f
+}
+
+// SetRows sets the schema and rows that are returned for every query.
+// Only flat schemas are supported, nil values are returned as NULL.
+func (f *FakeClient) SetRows(schema bigquery.Schema, rows ...[]bigquery.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schema = schema
+	f.rows = rows
+}
+
+// Queries returns all queries that were received, in order.
+func (f *FakeClient) Queries() []Query {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Query(nil), f.queries...)
+}
+
+// LastQuery returns the last query that was received, or the zero Query
+// when no query was received.
+func (f *FakeClient) LastQuery() Query {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.queries) == 0 {
+		return Query{}
+	}
+	return f.queries[len(f.queries)-1]
+}
+
+// serveHTTP answers the jobs.insert, jobs.get, jobs.query and
+// jobs.getQueryResults requests of the BigQuery API.
+func (f *FakeClient) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "jobs":
+		var job struct {
+			JobReference  map[string]any `json:"jobReference"`
+			Configuration map[string]any `json:"configuration"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobID, _ := job.JobReference["jobId"].(string)
+		query, _ := job.Configuration["query"].(map[string]any)
+		dryRun, _ := job.Configuration["dryRun"].(bool)
+		f.record(query, dryRun)
+		f.jobs[jobID] = job.Configuration
+		json.NewEncoder(w).Encode(f.job(jobID))
+	case r.Method == http.MethodGet && len(parts) == 4 && parts[2] == "jobs":
+		json.NewEncoder(w).Encode(f.job(parts[3]))
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "queries":
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobID := fmt.Sprintf("fake-query-%d", len(f.queries))
+		f.record(request, false)
+		f.jobs[jobID] = map[string]any{"query": request}
+		json.NewEncoder(w).Encode(f.results(jobID))
+	case r.Method == http.MethodGet && len(parts) == 4 && parts[2] == "queries":
+		json.NewEncoder(w).Encode(f.results(parts[3]))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// record adds the query of a request to the received queries.
+func (f *FakeClient) record(request map[string]any, dryRun bool) {
+	query := Query{DryRun: dryRun}
+	query.SQL, _ = request["query"].(string)
+	params, _ := request["queryParameters"].([]any)
+	for _, p := range params {
+		p, _ := p.(map[string]any)
+		name, _ := p["name"].(string)
+		paramType, _ := p["parameterType"].(map[string]any)
+		typeName, _ := paramType["type"].(string)
+		paramValue, _ := p["parameterValue"].(map[string]any)
+		value, _ := paramValue["value"].(string)
+		query.Parameters = append(query.Parameters, Parameter{Name: name, Type: typeName, Value: value})
+	}
+	f.queries = append(f.queries, query)
+}
+
+// fields returns the schema as BigQuery JSON fields.
+func (f *FakeClient) fields() []map[string]any {
+	fields := []map[string]any{}
+	for _, field := range f.schema {
+		fields = append(fields, map[string]any{"name": field.Name, "type": string(field.Type)})
+	}
+	return fields
+}
+
+// job returns the job resource for the given job ID.
+func (f *FakeClient) job(jobID string) map[string]any {
+	return map[string]any{
+		"jobReference":  map[string]any{"projectId": "test-project", "jobId": jobID, "location": "US"},
+		"configuration": f.jobs[jobID],
+		"status":        map[string]any{"state": "DONE"},
+		"statistics": map[string]any{
+			"totalBytesProcessed": "0",
+			"query": map[string]any{
+				"totalBytesProcessed": "0",
+				"schema":              map[string]any{"fields": f.fields()},
+			},
+		},
+	}
+}
+
+// results returns the query results for the given job ID.
+func (f *FakeClient) results(jobID string) map[string]any {
+	rows := []map[string]any{}
+	for _, row := range f.rows {
+		cells := []map[string]any{}
+		for _, value := range row {
+			if value == nil {
+				cells = append(cells, map[string]any{"v": nil})
+				continue
+			}
+			cells = append(cells, map[string]any{"v": fmt.Sprint(value)})
+		}
+		rows = append(rows, map[string]any{"f": cells})
+	}
+	return map[string]any{
+		"jobReference": map[string]any{"projectId": "test-project", "jobId": jobID, "location": "US"},
+		"jobComplete":  true,
+		"schema":       map[string]any{"fields": f.fields()},
+		"rows":         rows,
+		"totalRows":    fmt.Sprint(len(f.rows)),
+	}
+}
@@ -0,0 +1,85 @@
+package saferbqtest
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+func TestFakeClientRead(t *testing.T) {
+	client := NewFakeClient(t)
+	client.SetRows(bigquery.Schema{
+		{Name: "id", Type: bigquery.IntegerFieldType},
+		{Name: "name", Type: bigquery.StringFieldType},
+	}, []bigquery.Value{1, "alice"}, []bigquery.Value{2, nil})
+
+	q := client.Query("SELECT id, name FROM $table WHERE status = @status")
+	q.SetParams(map[string]any{"$table": "users", "@status": "active"})
+	it, err := q.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	var rows [][]bigquery.Value
+	for {
+		var row []bigquery.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) != 2 || rows[0][0] != int64(1) || rows[0][1] != "alice" || rows[1][1] != nil {
+		t.Errorf("rows = %v, want [[1 alice] [2 <nil>]]", rows)
+	}
+
+	got := client.LastQuery()
+	if got.SQL != "SELECT id, name FROM `users` WHERE status = @status" {
+		t.Errorf("SQL = %q", got.SQL)
+	}
+	want := Parameter{Name: "status", Type: "STRING", Value: "active"}
+	if len(got.Parameters) != 1 || got.Parameters[0] != want {
+		t.Errorf("Parameters = %v, want [%v]", got.Parameters, want)
+	}
+}
+
+func TestFakeClientRun(t *testing.T) {
+	client := NewFakeClient(t)
+	ctx := context.Background()
+
+	q := client.Query("DELETE FROM $table WHERE id = ?")
+	q.SetParams(map[string]any{"$table": "users"})
+	q.SetPositionalParams(42)
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if _, err := q.DryRun(ctx); err != nil {
+		t.Fatalf("DryRun() unexpected error: %v", err)
+	}
+
+	queries := client.Queries()
+	if len(queries) != 2 {
+		t.Fatalf("Queries() = %v, want 2 queries", queries)
+	}
+	if queries[0].SQL != "DELETE FROM `users` WHERE id = ?" || queries[0].DryRun {
+		t.Errorf("Queries()[0] = %+v", queries[0])
+	}
+	want := Parameter{Type: "INT64", Value: "42"}
+	if len(queries[0].Parameters) != 1 || queries[0].Parameters[0] != want {
+		t.Errorf("Parameters = %v, want [%v]", queries[0].Parameters, want)
+	}
+	if !queries[1].DryRun {
+		t.Error("Queries()[1].DryRun = false, want true")
+	}
+}
+
+func TestFakeClientNoQueries(t *testing.T) {
+	client := NewFakeClient(t)
+	if got := client.LastQuery(); got.SQL != "" || got.Parameters != nil {
+		t.Errorf("LastQuery() = %+v, want zero Query", got)
+	}
+}
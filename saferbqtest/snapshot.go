@@ -0,0 +1,84 @@
+package saferbqtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/mevdschee/saferbq"
+)
+
+// UpdateSnapshotsEnv is the environment variable that makes Snapshot
+// (re)write the golden files instead of comparing against them.
+const UpdateSnapshotsEnv = "SAFERBQ_UPDATE_SNAPSHOTS"
+
+// snapshotDir is the directory of the golden files, relative to the
+// directory of the test
+const snapshotDir = "testdata/snapshots"
+
+// snapshotNameRegex restricts snapshot names to safe file names
+var snapshotNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// Snapshot translates the SQL with the parameters and compares the result
+// with the golden file testdata/snapshots/<name>.golden. Run the tests with
+// SAFERBQ_UPDATE_SNAPSHOTS=1 to write the golden files, so changes in the
+// translation of large query templates show up in code review.
+//
+// The golden file contains the translated SQL and the parameters, or the
+// error when the translation failed.
+//
+// Example:
+//
+//	func TestReportQuery(t *testing.T) {
+//	    saferbqtest.Snapshot(t, "report", reportSQL, []bigquery.QueryParameter{
+//	        {Name: "$table", Value: "events"},
+//	        {Name: "@since", Value: "2024-01-01"},
+//	    })
+//	}
+func Snapshot(t testing.TB, name string, sql string, params []bigquery.QueryParameter) {
+	t.Helper()
+	if !snapshotNameRegex.MatchString(name) {
+		t.Fatalf("invalid snapshot name %q", name)
+	}
+	got := formatSnapshot(saferbq.Translate(sql, params))
+	path := filepath.Join(snapshotDir, name+".golden")
+	if os.Getenv(UpdateSnapshotsEnv) != "" {
+		if err := os.MkdirAll(snapshotDir, 0o755); err != nil {
+			t.Fatalf("failed to write snapshot: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("failed to write snapshot: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read snapshot (run with %s=1 to create it): %v", UpdateSnapshotsEnv, err)
+	}
+	if got != string(want) {
+		t.Errorf("snapshot %s does not match (run with %s=1 to update it)\ngot:\n%s\nwant:\n%s", path, UpdateSnapshotsEnv, got, want)
+	}
+}
+
+// formatSnapshot formats the result of a translation as golden file.
+func formatSnapshot(sql string, params []bigquery.QueryParameter, err error) string {
+	var b strings.Builder
+	if err != nil {
+		fmt.Fprintf(&b, "-- error --\n%s\n", err)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "-- sql --\n%s\n", sql)
+	b.WriteString("-- parameters --\n")
+	for _, p := range params {
+		name := p.Name
+		if name == "" {
+			name = "?"
+		}
+		fmt.Fprintf(&b, "%s = %#v\n", name, p.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package saferbqtest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/mevdschee/saferbq"
+)
+
+func TestFormatSnapshot(t *testing.T) {
+	got := formatSnapshot(saferbq.Translate("SELECT * FROM $table WHERE id = @id AND name = @name",
+		[]bigquery.QueryParameter{
+			{Name: "$table", Value: "users"},
+			{Name: "@id", Value: 1},
+			{Name: "@name", Value: "alice"},
+		}))
+	want := "-- sql --\nSELECT * FROM `users` WHERE id = @id AND name = @name\n" +
+		"-- parameters --\nid = 1\nname = \"alice\"\n"
+	if got != want {
+		t.Errorf("formatSnapshot() = %q, want %q", got, want)
+	}
+
+	got = formatSnapshot("", nil, errors.New("boom"))
+	if want := "-- error --\nboom\n"; got != want {
+		t.Errorf("formatSnapshot() = %q, want %q", got, want)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	sql := "SELECT * FROM $table WHERE id = ?"
+	params := []bigquery.QueryParameter{{Name: "$table", Value: "users"}, {Value: 1}}
+
+	t.Setenv(UpdateSnapshotsEnv, "1")
+	Snapshot(t, "users", sql, params)
+	data, err := os.ReadFile(filepath.Join(dir, snapshotDir, "users.golden"))
+	if err != nil {
+		t.Fatalf("snapshot was not written: %v", err)
+	}
+	want := "-- sql --\nSELECT * FROM `users` WHERE id = ?\n-- parameters --\n? = 1\n"
+	if string(data) != want {
+		t.Errorf("snapshot = %q, want %q", data, want)
+	}
+
+	t.Setenv(UpdateSnapshotsEnv, "")
+	Snapshot(t, "users", sql, params)
+}
@@ -0,0 +1,47 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// samplePercentParam is the name of the parameter that holds the sample percentage
+const samplePercentParam = "@sample_percent"
+
+// SampleClause is a TABLESAMPLE clause that samples the preceding table
+// with the percentage that is bound by Query.Sample. It is a constant, so
+// concatenating it to a query template is safe.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table " + saferbq.SampleClause)
+const SampleClause = "TABLESAMPLE SYSTEM (" + samplePercentParam + " PERCENT)"
+
+// Sample binds the sample percentage to the @sample_percent parameter of
+// the query and reads the results. Use SampleClause to sample dynamically
+// named tables, so exploratory queries only scan a fraction of the data.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table " + saferbq.SampleClause)
+//	q.SetParams(map[string]any{"$table": "events"})
+//	it, err := q.Sample(ctx, 1.5)
+//
+// Returns an error wrapping ErrInvalidSamplePercent if the percentage is not
+// greater than 0 and at most 100, ErrParameterNotFound if the query has no
+// @sample_percent parameter, or an error if the query fails.
+func (q *Query) Sample(ctx context.Context, percent float64) (*RowIterator, error) {
+	if !(percent > 0 && percent <= 100) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSamplePercent, percent)
+	}
+	parameters := []bigquery.QueryParameter{}
+	for _, p := range q.Parameters {
+		if p.Name != samplePercentParam {
+			parameters = append(parameters, p)
+		}
+	}
+	q.Parameters = append(parameters, bigquery.QueryParameter{Name: samplePercentParam, Value: percent})
+	return q.ReadRows(ctx)
+}
@@ -0,0 +1,51 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestQuerySample(t *testing.T) {
+	fake := &fakeBigQuery{schema: []map[string]any{{"name": "id", "type": "INTEGER"}}}
+	client := newFakeClient(t, fake)
+
+	q := client.Query("SELECT * FROM $table " + SampleClause)
+	q.SetParams(map[string]any{"$table": "events"})
+	q.Parameters = append(q.Parameters, bigquery.QueryParameter{Name: "@sample_percent", Value: 50.0})
+	if _, err := q.Sample(context.Background(), 1.5); err != nil {
+		t.Fatalf("Sample() unexpected error: %v", err)
+	}
+	queries := fake.executedQueries()
+	want := "SELECT * FROM `events` TABLESAMPLE SYSTEM (@sample_percent PERCENT)"
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("executed queries = %q, want [%q]", queries, want)
+	}
+	if len(q.Parameters) != 1 || q.Parameters[0].Name != "sample_percent" || q.Parameters[0].Value != 1.5 {
+		t.Errorf("Parameters = %v, want only sample_percent = 1.5", q.Parameters)
+	}
+}
+
+func TestQuerySampleErrors(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	for _, percent := range []float64{0, -1, 100.5, math.NaN()} {
+		q := client.Query("SELECT * FROM t " + SampleClause)
+		if _, err := q.Sample(ctx, percent); !errors.Is(err, ErrInvalidSamplePercent) {
+			t.Errorf("Sample(%v) error = %v, want ErrInvalidSamplePercent", percent, err)
+		}
+	}
+
+	q := client.Query("SELECT * FROM t")
+	if _, err := q.Sample(ctx, 10); !errors.Is(err, ErrParameterNotFound) {
+		t.Errorf("Sample() error = %v, want ErrParameterNotFound", err)
+	}
+	if len(fake.executedQueries()) != 0 {
+		t.Errorf("executed queries = %q, want none", fake.executedQueries())
+	}
+}
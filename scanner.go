@@ -0,0 +1,195 @@
+package saferbq
+
+import (
+	"strings"
+)
+
+// tokenKind is the kind of a token in the SQL token stream.
+type tokenKind int
+
+const (
+	// tokenWhitespace is a run of spaces, tabs and newlines
+	tokenWhitespace tokenKind = iota
+	// tokenComment is a "--", "#" or "/* */" comment
+	tokenComment
+	// tokenString is a string or bytes literal, which may be raw and/or triple quoted
+	tokenString
+	// tokenQuotedIdentifier is a backtick quoted identifier
+	tokenQuotedIdentifier
+	// tokenWord is an unquoted identifier or keyword
+	tokenWord
+	// tokenIdentifierParam is a $identifier parameter
+	tokenIdentifierParam
+	// tokenNamedParam is an @parameter
+	tokenNamedParam
+	// tokenPositionalParam is a ? positional parameter
+	tokenPositionalParam
+	// tokenDollarEscape is a $$ escape for a literal dollar sign
+	tokenDollarEscape
+	// tokenSystemVariable is a @@system_variable of BigQuery scripting
+	tokenSystemVariable
+	// tokenOther is any other character, like punctuation or a digit
+	tokenOther
+)
+
+// token is a piece of SQL with its kind and byte offset in the SQL.
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// scan splits the SQL into tokens. The concatenated text of all tokens is
+// always equal to the SQL. Unterminated strings, quoted identifiers and
+// comments extend to the end of the SQL.
+func scan(sql string) []token {
+	tokens := []token{}
+	for i := 0; i < len(sql); {
+		kind, end := scanToken(sql, i)
+		tokens = append(tokens, token{kind: kind, text: sql[i:end], offset: i})
+		i = end
+	}
+	return tokens
+}
+
+// scanToken scans the token starting at byte offset i and returns its kind
+// and end offset.
+func scanToken(sql string, i int) (tokenKind, int) {
+	c := sql[i]
+	switch {
+	case isWhitespace(c):
+		end := i + 1
+		for end < len(sql) && isWhitespace(sql[end]) {
+			end++
+		}
+		return tokenWhitespace, end
+	case c == '#' || strings.HasPrefix(sql[i:], "--"):
+		end := strings.IndexByte(sql[i:], '\n')
+		if end < 0 {
+			return tokenComment, len(sql)
+		}
+		return tokenComment, i + end
+	case strings.HasPrefix(sql[i:], "/*"):
+		end := strings.Index(sql[i+2:], "*/")
+		if end < 0 {
+			return tokenComment, len(sql)
+		}
+		return tokenComment, i + 2 + end + 2
+	case c == '\'' || c == '"':
+		return tokenString, scanString(sql, i)
+	case stringPrefixLength(sql, i) > 0:
+		return tokenString, scanString(sql, i+stringPrefixLength(sql, i))
+	case c == backtick:
+		return tokenQuotedIdentifier, scanQuoted(sql, i, c)
+	case isWordStart(c):
+		return tokenWord, scanWord(sql, i)
+	case c == dollarSign && i+1 < len(sql) && sql[i+1] == dollarSign:
+		return tokenDollarEscape, i + 2
+	case c == dollarSign && i+1 < len(sql) && isWordStart(sql[i+1]):
+		return tokenIdentifierParam, scanWord(sql, i+1)
+	case c == atSign && i+2 < len(sql) && sql[i+1] == atSign && isWordStart(sql[i+2]):
+		return tokenSystemVariable, scanWord(sql, i+2)
+	case c == atSign && i+1 < len(sql) && isWordStart(sql[i+1]):
+		return tokenNamedParam, scanWord(sql, i+1)
+	case c == questionMark:
+		return tokenPositionalParam, i + 1
+	default:
+		return tokenOther, i + 1
+	}
+}
+
+// scanQuoted returns the end offset of the quoted token starting at i.
+// A backslash escapes the next character.
+func scanQuoted(sql string, i int, quote byte) int {
+	for end := i + 1; end < len(sql); end++ {
+		switch sql[end] {
+		case '\\':
+			end++
+		case quote:
+			return end + 1
+		}
+	}
+	return len(sql)
+}
+
+// stringPrefixLength returns the length of the raw (r) and bytes (b) prefix
+// of the string literal starting at i, or 0 if there is no such literal.
+func stringPrefixLength(sql string, i int) int {
+	for n := 1; n <= 2 && i+n < len(sql); n++ {
+		switch sql[i+n-1] {
+		case 'r', 'R', 'b', 'B':
+		default:
+			return 0
+		}
+		if q := sql[i+n]; q == '\'' || q == '"' {
+			if n == 2 && strings.EqualFold(sql[i:i+1], sql[i+1:i+2]) {
+				return 0 // "rr" and "bb" are not valid prefixes
+			}
+			return n
+		}
+	}
+	return 0
+}
+
+// scanString returns the end offset of the string literal whose quote
+// starts at i. Both single and triple quoted strings are supported.
+func scanString(sql string, i int) int {
+	quote := sql[i]
+	triple := strings.Repeat(string(quote), 3)
+	if !strings.HasPrefix(sql[i:], triple) {
+		return scanQuoted(sql, i, quote)
+	}
+	for end := i + 3; end < len(sql); end++ {
+		if sql[end] == '\\' {
+			end++
+		} else if strings.HasPrefix(sql[end:], triple) {
+			return end + 3
+		}
+	}
+	return len(sql)
+}
+
+// scanWord returns the end offset of the word starting at i.
+func scanWord(sql string, i int) int {
+	end := i
+	for end < len(sql) && isWordChar(sql[end]) {
+		end++
+	}
+	return end
+}
+
+// isWhitespace checks if a byte is a whitespace character.
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// isWordStart checks if a byte can start an unquoted identifier or keyword.
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isWordChar checks if a byte can be part of an unquoted identifier or keyword.
+func isWordChar(c byte) bool {
+	return isWordStart(c) || (c >= '0' && c <= '9')
+}
+
+// statementBody returns the SQL of a statement without the trailing
+// whitespace, comments and semicolons, so it can be terminated with a
+// semicolon when statements are combined into a script. A trailing line
+// comment would otherwise swallow the semicolon and the next statement.
+func statementBody(sql string) string {
+	tokens := scan(sql)
+	end := len(tokens)
+	for end > 0 {
+		tok := tokens[end-1]
+		if tok.kind != tokenWhitespace && tok.kind != tokenComment && tok.text != ";" {
+			break
+		}
+		end--
+	}
+	if end == 0 {
+		return ""
+	}
+	last := tokens[end-1]
+	return sql[:last.offset+len(last.text)]
+}
@@ -0,0 +1,151 @@
+package saferbq
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		tokens []token
+	}{
+		{
+			name: "words and parameters",
+			sql:  "SELECT * FROM $table WHERE id = @id",
+			tokens: []token{
+				{tokenWord, "SELECT", 0}, {tokenWhitespace, " ", 6}, {tokenOther, "*", 7}, {tokenWhitespace, " ", 8},
+				{tokenWord, "FROM", 9}, {tokenWhitespace, " ", 13}, {tokenIdentifierParam, "$table", 14}, {tokenWhitespace, " ", 20},
+				{tokenWord, "WHERE", 21}, {tokenWhitespace, " ", 26}, {tokenWord, "id", 27}, {tokenWhitespace, " ", 29},
+				{tokenOther, "=", 30}, {tokenWhitespace, " ", 31}, {tokenNamedParam, "@id", 32},
+			},
+		},
+		{
+			name: "strings with escapes",
+			sql:  `'it\'s ?' "say \"$x\""`,
+			tokens: []token{
+				{tokenString, `'it\'s ?'`, 0}, {tokenWhitespace, " ", 9}, {tokenString, `"say \"$x\""`, 10},
+			},
+		},
+		{
+			name: "quoted identifier",
+			sql:  "`my-table`.id=?",
+			tokens: []token{
+				{tokenQuotedIdentifier, "`my-table`", 0}, {tokenOther, ".", 10}, {tokenWord, "id", 11},
+				{tokenOther, "=", 13}, {tokenPositionalParam, "?", 14},
+			},
+		},
+		{
+			name: "raw and bytes strings",
+			sql:  `r'\d+$' B"@x" rb'?' Br"a"`,
+			tokens: []token{
+				{tokenString, `r'\d+$'`, 0}, {tokenWhitespace, " ", 7}, {tokenString, `B"@x"`, 8}, {tokenWhitespace, " ", 13},
+				{tokenString, `rb'?'`, 14}, {tokenWhitespace, " ", 19}, {tokenString, `Br"a"`, 20},
+			},
+		},
+		{
+			name: "triple quoted strings",
+			sql:  "'''it's $a''' r\"\"\"\n@b \"?\" \"\"\"x",
+			tokens: []token{
+				{tokenString, "'''it's $a'''", 0}, {tokenWhitespace, " ", 13},
+				{tokenString, "r\"\"\"\n@b \"?\" \"\"\"", 14}, {tokenWord, "x", 29},
+			},
+		},
+		{
+			name: "not a string prefix",
+			sql:  "rr'a' or'b'",
+			tokens: []token{
+				{tokenWord, "rr", 0}, {tokenString, "'a'", 2}, {tokenWhitespace, " ", 5}, {tokenWord, "or", 6},
+				{tokenString, "'b'", 8},
+			},
+		},
+		{
+			name: "system variables",
+			sql:  "SET @@query_label = @label",
+			tokens: []token{
+				{tokenWord, "SET", 0}, {tokenWhitespace, " ", 3}, {tokenSystemVariable, "@@query_label", 4},
+				{tokenWhitespace, " ", 17}, {tokenOther, "=", 18}, {tokenWhitespace, " ", 19}, {tokenNamedParam, "@label", 20},
+			},
+		},
+		{
+			name: "dollar escape",
+			sql:  "t$$2024 $$$a",
+			tokens: []token{
+				{tokenWord, "t", 0}, {tokenDollarEscape, "$$", 1}, {tokenOther, "2", 3}, {tokenOther, "0", 4},
+				{tokenOther, "2", 5}, {tokenOther, "4", 6}, {tokenWhitespace, " ", 7}, {tokenDollarEscape, "$$", 8},
+				{tokenIdentifierParam, "$a", 10},
+			},
+		},
+		{
+			name: "comments",
+			sql:  "-- $a\n# @b\n/* ? */x",
+			tokens: []token{
+				{tokenComment, "-- $a", 0}, {tokenWhitespace, "\n", 5}, {tokenComment, "# @b", 6},
+				{tokenWhitespace, "\n", 10}, {tokenComment, "/* ? */", 11}, {tokenWord, "x", 18},
+			},
+		},
+		{
+			name: "unterminated",
+			sql:  "'abc /* x",
+			tokens: []token{
+				{tokenString, "'abc /* x", 0},
+			},
+		},
+		{
+			name: "unterminated comment",
+			sql:  "x /* abc",
+			tokens: []token{
+				{tokenWord, "x", 0}, {tokenWhitespace, " ", 1}, {tokenComment, "/* abc", 2},
+			},
+		},
+		{
+			name: "dollar and at without name",
+			sql:  "$1 @ 2",
+			tokens: []token{
+				{tokenOther, "$", 0}, {tokenOther, "1", 1}, {tokenWhitespace, " ", 2},
+				{tokenOther, "@", 3}, {tokenWhitespace, " ", 4}, {tokenOther, "2", 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := scan(tt.sql)
+			if len(tokens) != len(tt.tokens) {
+				t.Fatalf("scan() = %v, want %v", tokens, tt.tokens)
+			}
+			var sql strings.Builder
+			for i := range tokens {
+				if tokens[i] != tt.tokens[i] {
+					t.Errorf("scan() token %d = %v, want %v", i, tokens[i], tt.tokens[i])
+				}
+				sql.WriteString(tokens[i].text)
+			}
+			if sql.String() != tt.sql {
+				t.Errorf("scan() tokens = %q, want %q", sql.String(), tt.sql)
+			}
+		})
+	}
+}
+
+func TestStatementBody(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT 1;;\n", "SELECT 1"},
+		{"SELECT 1 -- done;", "SELECT 1"},
+		{"SELECT 1; # done\n", "SELECT 1"},
+		{"SELECT 1 /* a */ -- b\n;", "SELECT 1"},
+		{"SELECT ';' -- x", "SELECT ';'"},
+		{"-- only a comment", ""},
+	}
+
+	for _, tt := range tests {
+		if got := statementBody(tt.sql); got != tt.want {
+			t.Errorf("statementBody(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package saferbq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// OffPeakWindow is a daily time window, given as offsets since midnight.
+// A window whose End is not after its Start wraps around midnight, so
+// {Start: 22 * time.Hour, End: 6 * time.Hour} is the night from 22:00 to 06:00.
+type OffPeakWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// OffPeakSchedule defines the daily windows in which deferred queries run.
+type OffPeakSchedule struct {
+	// Windows are the daily off-peak windows
+	Windows []OffPeakWindow
+	// Location is the time zone of the windows (UTC when nil)
+	Location *time.Location
+}
+
+// next returns the earliest time at or after t that lies within one of the
+// windows, or t when there are no windows.
+func (s OffPeakSchedule) next(t time.Time) time.Time {
+	if len(s.Windows) == 0 {
+		return t
+	}
+	location := s.Location
+	if location == nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	var earliest time.Time
+	// Windows that started yesterday may still be open today
+	for day := -1; day <= 1; day++ {
+		midnight := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, location)
+		for _, w := range s.Windows {
+			start := midnight.Add(w.Start)
+			end := midnight.Add(w.End)
+			if !end.After(start) {
+				end = end.Add(24 * time.Hour)
+			}
+			if !t.Before(start) && t.Before(end) {
+				return t
+			}
+			if start.After(t) && (earliest.IsZero() || start.Before(earliest)) {
+				earliest = start
+			}
+		}
+	}
+	return earliest
+}
+
+// WithOffPeakSchedule configures the off-peak windows in which queries that
+// are submitted with Client.Defer are executed. Without a schedule deferred
+// queries run immediately.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithOffPeakSchedule(saferbq.OffPeakSchedule{
+//	    Windows:  []saferbq.OffPeakWindow{{Start: 22 * time.Hour, End: 6 * time.Hour}},
+//	    Location: time.Local,
+//	}))
+func WithOffPeakSchedule(schedule OffPeakSchedule) Option {
+	return func(c *Client) {
+		c.deferred.schedule = schedule
+	}
+}
+
+// deferredQuery is a query waiting for an off-peak window.
+type deferredQuery struct {
+	ctx      context.Context
+	query    *Query
+	callback func(*bigquery.Job, error)
+	// stop unregisters the wake up when the context is done
+	stop func() bool
+}
+
+// deferQueue holds the deferred queries of a client in memory and runs them
+// in order once an off-peak window is open.
+type deferQueue struct {
+	mu       sync.Mutex
+	schedule OffPeakSchedule
+	queue    []deferredQuery
+	running  bool
+	// wake interrupts the wait for a window when a queued context is done
+	wake chan struct{}
+	// now returns the current time (replaceable in tests)
+	now func() time.Time
+}
+
+// Defer queues the query to be run in the next off-peak window, so heavy
+// jobs like backfills and exports don't compete with interactive traffic.
+// Queries are run in order and the callback is called with the result of
+// Run. If the context is done before the query is run, the query is removed
+// from the queue and the callback is called with the context error right
+// away. Queries that are still waiting when the client is closed are
+// reported with context.Canceled.
+//
+// Deferred queries are held in memory, they are lost when the process exits.
+// Defer panics when the callback is nil.
+//
+// Example:
+//
+//	q := client.Query("INSERT INTO $table SELECT * FROM $staging")
+//	q.Lane = saferbq.LaneBackground
+//	client.Defer(ctx, q, func(job *bigquery.Job, err error) {
+//	    if err != nil {
+//	        log.Printf("backfill failed: %v", err)
+//	    }
+//	})
+func (c *Client) Defer(ctx context.Context, q *Query, callback func(*bigquery.Job, error)) {
+	if callback == nil {
+		panic("saferbq: Defer(): nil callback")
+	}
+	d := &c.deferred
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.wake == nil {
+		d.wake = make(chan struct{}, 1)
+	}
+	item := deferredQuery{ctx: ctx, query: q, callback: callback}
+	item.stop = context.AfterFunc(ctx, d.notify)
+	d.queue = append(d.queue, item)
+	if !d.running {
+		d.running = true
+		go d.run(c.background())
+	}
+}
+
+// Deferred returns the number of queries that are waiting for an off-peak window.
+func (c *Client) Deferred() int {
+	d := &c.deferred
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.queue)
+}
+
+// notify wakes up run without blocking.
+func (d *deferQueue) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run executes the queued queries when a window is open, until the queue
+// is empty. Queries whose context is done are reported as soon as run
+// wakes up, and all queries are reported when the client is closed.
+func (d *deferQueue) run(background context.Context) {
+	for {
+		d.mu.Lock()
+		var done []deferredQuery
+		queue := d.queue[:0]
+		for _, item := range d.queue {
+			if item.ctx.Err() != nil || background.Err() != nil {
+				done = append(done, item)
+			} else {
+				queue = append(queue, item)
+			}
+		}
+		clear(d.queue[len(queue):])
+		d.queue = queue
+		var next *deferredQuery
+		var wait time.Duration
+		if len(d.queue) == 0 {
+			d.running = false
+		} else {
+			now := time.Now()
+			if d.now != nil {
+				now = d.now()
+			}
+			if start := d.schedule.next(now); start.After(now) {
+				wait = start.Sub(now)
+			} else {
+				item := d.queue[0]
+				d.queue = d.queue[1:]
+				next = &item
+			}
+		}
+		running := d.running
+		d.mu.Unlock()
+
+		for _, item := range done {
+			item.stop()
+			err := item.ctx.Err()
+			if err == nil {
+				err = background.Err()
+			}
+			item.callback(nil, err)
+		}
+		switch {
+		case !running:
+			return
+		case next != nil:
+			next.stop()
+			next.callback(next.query.Run(next.ctx))
+		default:
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-d.wake:
+			case <-background.Done():
+			}
+			timer.Stop()
+		}
+	}
+}
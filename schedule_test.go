@@ -0,0 +1,171 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestOffPeakScheduleNext(t *testing.T) {
+	night := OffPeakSchedule{Windows: []OffPeakWindow{{Start: 22 * time.Hour, End: 6 * time.Hour}}}
+	day := OffPeakSchedule{Windows: []OffPeakWindow{
+		{Start: 12 * time.Hour, End: 13 * time.Hour},
+		{Start: 18 * time.Hour, End: 19 * time.Hour},
+	}}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		schedule OffPeakSchedule
+		now      time.Time
+		want     time.Time
+	}{
+		{"no windows", OffPeakSchedule{}, at(10, 0), at(10, 0)},
+		{"before wrapping window", night, at(10, 0), at(22, 0)},
+		{"inside wrapping window late", night, at(23, 30), at(23, 30)},
+		{"inside wrapping window early", night, at(5, 59), at(5, 59)},
+		{"at window end", night, at(6, 0), at(22, 0)},
+		{"first of two windows", day, at(9, 0), at(12, 0)},
+		{"second of two windows", day, at(13, 0), at(18, 0)},
+		{"next day", day, at(20, 0), at(12, 0).AddDate(0, 0, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.next(tt.now); !got.Equal(tt.want) {
+				t.Errorf("next(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffPeakScheduleLocation(t *testing.T) {
+	location := time.FixedZone("UTC+2", 2*60*60)
+	schedule := OffPeakSchedule{
+		Windows:  []OffPeakWindow{{Start: 1 * time.Hour, End: 5 * time.Hour}},
+		Location: location,
+	}
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	want := time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)
+	if got := schedule.next(now); !got.Equal(want) {
+		t.Errorf("next(%v) = %v, want %v", now, got, want)
+	}
+}
+
+func TestDeferRunsInsideWindow(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	done := make(chan error, 1)
+	client.Defer(context.Background(), client.Query("SELECT 1"), func(job *bigquery.Job, err error) {
+		done <- err
+	})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("deferred query failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deferred query was not run")
+	}
+	if got := fake.executedQueries(); len(got) != 1 || got[0] != "SELECT 1" {
+		t.Errorf("executed queries = %v, want [SELECT 1]", got)
+	}
+	if got := client.Deferred(); got != 0 {
+		t.Errorf("Deferred() = %d, want 0", got)
+	}
+}
+
+func TestDeferWaitsOutsideWindow(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	now := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	client.Configure(WithOffPeakSchedule(OffPeakSchedule{
+		Windows: []OffPeakWindow{{Start: 22 * time.Hour, End: 6 * time.Hour}},
+	}))
+	client.deferred.now = func() time.Time { return now }
+	done := make(chan error, 1)
+	client.Defer(context.Background(), client.Query("SELECT 1"), func(job *bigquery.Job, err error) {
+		done <- err
+	})
+	time.Sleep(50 * time.Millisecond)
+	if got := client.Deferred(); got != 1 {
+		t.Errorf("Deferred() = %d, want 1", got)
+	}
+	if got := fake.executedQueries(); len(got) != 0 {
+		t.Errorf("executed queries = %v, want none", got)
+	}
+
+	// Closing the client stops the wait and reports the waiting queries
+	client.Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deferred query was not reported after Close")
+	}
+	if got := client.Deferred(); got != 0 {
+		t.Errorf("Deferred() = %d, want 0", got)
+	}
+}
+
+func TestDeferCanceledWhileWaiting(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	now := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	client.Configure(WithOffPeakSchedule(OffPeakSchedule{
+		Windows: []OffPeakWindow{{Start: 22 * time.Hour, End: 6 * time.Hour}},
+	}))
+	client.deferred.now = func() time.Time { return now }
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	client.Defer(ctx, client.Query("SELECT 1"), func(job *bigquery.Job, err error) {
+		done <- err
+	})
+	client.Defer(context.Background(), client.Query("SELECT 2"), func(*bigquery.Job, error) {})
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceled query was not reported before the window opened")
+	}
+	if got := client.Deferred(); got != 1 {
+		t.Errorf("Deferred() = %d, want 1", got)
+	}
+	client.Close()
+}
+
+func TestDeferNilCallback(t *testing.T) {
+	client := newFakeClient(t, &fakeBigQuery{})
+	defer func() {
+		if recover() == nil {
+			t.Error("Defer() with nil callback did not panic")
+		}
+	}()
+	client.Defer(context.Background(), client.Query("SELECT 1"), nil)
+}
+
+func TestDeferCanceledContext(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan error, 1)
+	client.Defer(ctx, client.Query("SELECT 1"), func(job *bigquery.Job, err error) {
+		done <- err
+	})
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want %v", err, context.Canceled)
+	}
+	if got := fake.executedQueries(); len(got) != 0 {
+		t.Errorf("executed queries = %v, want none", got)
+	}
+}
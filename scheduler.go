@@ -0,0 +1,188 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Scheduler decides when a query may be submitted to BigQuery. Run and Read
+// call Schedule after the query has a free slot in its lane, and call the
+// returned release function when the slot is freed again (for Run when the
+// job is done, for Read when the results are available).
+//
+// Implementations must be safe for concurrent use and should return the
+// error of the context when it is done while waiting.
+type Scheduler interface {
+	Schedule(ctx context.Context, req ScheduleRequest) (release func(), err error)
+}
+
+// ScheduleRequest describes the query that asks the Scheduler to be
+// submitted.
+type ScheduleRequest struct {
+	// Project is the project the job is created in
+	Project string
+	// Reservation is the reservation of the query (may be empty)
+	Reservation string
+	// Lane is the execution lane of the query
+	Lane Lane
+	// Priority is the priority of the query job
+	Priority bigquery.QueryPriority
+}
+
+// Interactive reports whether the request is for an interactive query, a
+// query in the interactive lane without batch priority.
+func (r ScheduleRequest) Interactive() bool {
+	return r.Lane == LaneInteractive && r.Priority != bigquery.BatchPriority
+}
+
+// WithScheduler submits the queries of Run and Read through the scheduler,
+// in addition to the limits of WithLaneLimits and WithMaxConcurrentQueries.
+// The time spent waiting is recorded by WithMetrics.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithScheduler(saferbq.NewTokenBucketScheduler(10, 20)))
+func WithScheduler(s Scheduler) Option {
+	return func(c *Client) {
+		c.scheduler = s
+	}
+}
+
+// TokenBucketScheduler is a Scheduler that limits the rate at which queries
+// are submitted with a token bucket per reservation, or per project for
+// queries without a reservation. When the bucket is empty, waiting
+// interactive queries get the next tokens before waiting batch and
+// background queries.
+//
+// Use NewTokenBucketScheduler() to create a new TokenBucketScheduler.
+type TokenBucketScheduler struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// tokenBucket holds the tokens of one reservation or project.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	// interactive is the number of waiting interactive requests
+	interactive int
+}
+
+// NewTokenBucketScheduler creates a scheduler that submits on average rate
+// queries per second per reservation or project, with bursts of up to burst
+// queries. A rate of zero (or less) means unlimited, a burst of less than
+// one is raised to one.
+func NewTokenBucketScheduler(rate float64, burst int) *TokenBucketScheduler {
+	return &TokenBucketScheduler{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Schedule waits for a token in the bucket of the request or until the
+// context is done. Tokens are not returned, so release does nothing.
+func (s *TokenBucketScheduler) Schedule(ctx context.Context, req ScheduleRequest) (func(), error) {
+	if s.rate <= 0 {
+		return func() {}, nil
+	}
+	key := req.Project
+	if req.Reservation != "" {
+		key = req.Reservation
+	}
+	interactive := req.Interactive()
+	waiting := false
+	for {
+		s.mu.Lock()
+		b := s.bucket(key)
+		// Batch requests yield to waiting interactive requests
+		if b.tokens >= 1 && (interactive || b.interactive == 0) {
+			b.tokens--
+			if waiting {
+				b.interactive--
+			}
+			s.mu.Unlock()
+			return func() {}, nil
+		}
+		if interactive && !waiting {
+			b.interactive++
+			waiting = true
+		}
+		delay := time.Duration((1 - b.tokens) / s.rate * float64(time.Second))
+		if delay <= 0 {
+			// A token is available, but reserved for interactive requests
+			delay = time.Duration(float64(time.Second) / s.rate)
+		}
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			if waiting {
+				s.mu.Lock()
+				b.interactive--
+				s.mu.Unlock()
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// String describes the rate and burst of the scheduler.
+func (s *TokenBucketScheduler) String() string {
+	return fmt.Sprintf("token bucket: %g queries/s, burst %g", s.rate, s.burst)
+}
+
+// bucket returns the refilled bucket of the key. The mutex must be held.
+func (s *TokenBucketScheduler) bucket(key string) *tokenBucket {
+	now := s.now()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: s.burst, last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = min(s.burst, b.tokens+now.Sub(b.last).Seconds()*s.rate)
+	b.last = now
+	return b
+}
+
+// acquire waits for a free slot in the lane of the query and for the
+// scheduler of the client, and returns the function that frees them again.
+// The client may be nil, in which case no limit applies.
+func (q *Query) acquire(ctx context.Context) (func(), error) {
+	c := q.client
+	if c == nil {
+		return func() {}, nil
+	}
+	start := time.Now()
+	c.metrics.enterQueue(q.Lane)
+	defer c.metrics.leaveQueue(q.Lane, start)
+	release, err := c.acquire(ctx, q.Lane)
+	if err != nil || c.scheduler == nil {
+		return release, err
+	}
+	done, err := c.scheduler.Schedule(ctx, ScheduleRequest{
+		Project:     c.Project(),
+		Reservation: q.Reservation,
+		Lane:        q.Lane,
+		Priority:    q.Priority,
+	})
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to schedule %s query: %w", q.Lane, err)
+	}
+	return func() {
+		done()
+		release()
+	}, nil
+}
@@ -0,0 +1,163 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTokenBucketScheduler(t *testing.T) {
+	ctx := context.Background()
+	s := NewTokenBucketScheduler(10, 2)
+	req := ScheduleRequest{Project: "my-project"}
+
+	// The burst is available immediately
+	for i := 0; i < 2; i++ {
+		if _, err := s.Schedule(ctx, req); err != nil {
+			t.Fatalf("Schedule() unexpected error: %v", err)
+		}
+	}
+
+	// The bucket is empty, so the next caller has to wait
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Schedule(timeoutCtx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Schedule() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Other reservations have their own bucket
+	if _, err := s.Schedule(timeoutCtx, ScheduleRequest{Project: "my-project", Reservation: "other"}); err != nil {
+		t.Errorf("Schedule() unexpected error for other reservation: %v", err)
+	}
+
+	// The bucket refills at the rate
+	start := time.Now()
+	if _, err := s.Schedule(ctx, req); err != nil {
+		t.Fatalf("Schedule() unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Schedule() waited %v, want about 100ms", elapsed)
+	}
+}
+
+func TestTokenBucketSchedulerPriority(t *testing.T) {
+	ctx := context.Background()
+	s := NewTokenBucketScheduler(10, 1)
+	if _, err := s.Schedule(ctx, ScheduleRequest{}); err != nil {
+		t.Fatalf("Schedule() unexpected error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	schedule := func(name string, req ScheduleRequest) {
+		defer wg.Done()
+		if _, err := s.Schedule(ctx, req); err != nil {
+			t.Errorf("Schedule() unexpected error: %v", err)
+		}
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+	wg.Add(2)
+	go schedule("batch", ScheduleRequest{Lane: LaneBackground, Priority: bigquery.BatchPriority})
+	time.Sleep(20 * time.Millisecond)
+	go schedule("interactive", ScheduleRequest{})
+	wg.Wait()
+
+	if len(order) != 2 || order[0] != "interactive" {
+		t.Errorf("order = %v, want interactive first", order)
+	}
+}
+
+func TestScheduleRequestInteractive(t *testing.T) {
+	tests := []struct {
+		req  ScheduleRequest
+		want bool
+	}{
+		{ScheduleRequest{}, true},
+		{ScheduleRequest{Priority: bigquery.InteractivePriority}, true},
+		{ScheduleRequest{Priority: bigquery.BatchPriority}, false},
+		{ScheduleRequest{Lane: LaneBackground}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.req.Interactive(); got != tt.want {
+			t.Errorf("%+v.Interactive() = %v, want %v", tt.req, got, tt.want)
+		}
+	}
+}
+
+// recordingScheduler records the requests and releases.
+type recordingScheduler struct {
+	mu       sync.Mutex
+	requests []ScheduleRequest
+	released int
+	err      error
+}
+
+func (s *recordingScheduler) Schedule(ctx context.Context, req ScheduleRequest) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return func() {
+		s.mu.Lock()
+		s.released++
+		s.mu.Unlock()
+	}, nil
+}
+
+func TestWithScheduler(t *testing.T) {
+	fake := &fakeBigQuery{schema: []map[string]any{{"name": "id", "type": "INTEGER"}}}
+	client := newFakeClient(t, fake)
+	scheduler := &recordingScheduler{}
+	metrics := NewMetrics()
+	client.Configure(WithScheduler(scheduler), WithMetrics(metrics))
+	ctx := context.Background()
+
+	q := client.BatchQuery("SELECT id FROM $table")
+	q.SetParams(map[string]any{"$table": "mytable"})
+	q.Reservation = "my-reservation"
+	if _, err := q.Read(ctx); err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+
+	scheduler.mu.Lock()
+	if len(scheduler.requests) != 1 {
+		t.Fatalf("requests = %d, want 1", len(scheduler.requests))
+	}
+	want := ScheduleRequest{
+		Project:     client.Project(),
+		Reservation: "my-reservation",
+		Lane:        LaneBackground,
+		Priority:    bigquery.BatchPriority,
+	}
+	if got := scheduler.requests[0]; got != want {
+		t.Errorf("request = %+v, want %+v", got, want)
+	}
+	if scheduler.released != 1 {
+		t.Errorf("released = %d, want 1", scheduler.released)
+	}
+	scheduler.mu.Unlock()
+
+	if got := testutil.ToFloat64(metrics.queueDepth.WithLabelValues("background")); got != 0 {
+		t.Errorf("queue_depth{background} = %v, want 0", got)
+	}
+	if got := testutil.CollectAndCount(metrics, "saferbq_queue_wait_seconds"); got != 1 {
+		t.Errorf("queue_wait_seconds series = %d, want 1", got)
+	}
+
+	// Scheduler errors are returned and free the lane slot
+	scheduler.err = errors.New("rejected")
+	if _, err := q.Read(ctx); err == nil || err.Error() != "failed to schedule background query: rejected" {
+		t.Errorf("Read() error = %v, want scheduler error", err)
+	}
+}
@@ -0,0 +1,130 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultSchemaCacheTTL is how long TableSchema caches a schema when the
+// client does not set a TTL with WithSchemaCacheTTL
+const DefaultSchemaCacheTTL = 5 * time.Minute
+
+// schemaCache holds the table schemas that TableSchema fetched, by
+// dataset and table ID.
+type schemaCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]schemaEntry
+	group   singleflight.Group
+	// now returns the current time (replaceable in tests)
+	now func() time.Time
+}
+
+// schemaEntry is a cached schema with the time it expires.
+type schemaEntry struct {
+	schema  bigquery.Schema
+	expires time.Time
+}
+
+// WithSchemaCacheTTL sets how long TableSchema caches the schema of a
+// table (the default is DefaultSchemaCacheTTL). A negative TTL disables
+// the cache, concurrent lookups of the same table are still de-duplicated.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithSchemaCacheTTL(time.Minute))
+func WithSchemaCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.schemas.ttl = ttl
+	}
+}
+
+// TableSchema returns the schema of the table, read from the table
+// metadata. The dataset and table IDs are validated (with the rules of
+// DatasetID and TableID). Schemas are cached in the client for the TTL of
+// WithSchemaCacheTTL, and concurrent lookups of the same table share a
+// single metadata request. Statements of the client that change a table,
+// such as AlterTable and DropTable, remove it from the cache.
+//
+// Example:
+//
+//	schema, err := client.TableSchema(ctx, "analytics", "events_"+tenant)
+//
+// Returns an error if an ID is not valid, or if the metadata could not be
+// read. Errors are not cached.
+func (c *Client) TableSchema(ctx context.Context, dataset, table string) (bigquery.Schema, error) {
+	if _, err := DatasetID(dataset).render("$dataset"); err != nil {
+		return nil, fmt.Errorf("failed to get table schema: %w", err)
+	}
+	if _, err := TableID(table).render("$table"); err != nil {
+		return nil, fmt.Errorf("failed to get table schema: %w", err)
+	}
+	s := &c.schemas
+	key := dataset + "." + table
+	if schema, ok := s.get(key); ok {
+		return schema, nil
+	}
+	value, err, _ := s.group.Do(key, func() (any, error) {
+		meta, err := c.Client.Dataset(dataset).Table(table).Metadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.put(key, meta.Schema)
+		return meta.Schema, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table schema: %w", err)
+	}
+	return value.(bigquery.Schema), nil
+}
+
+// InvalidateTableSchema removes the schema of the table from the cache of
+// TableSchema, so the next lookup reads the table metadata. Use it after
+// changing a table outside of the client.
+func (c *Client) InvalidateTableSchema(dataset, table string) {
+	s := &c.schemas
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, dataset+"."+table)
+}
+
+// get returns the cached schema of the key, if it has not expired.
+func (s *schemaCache) get(key string) (bigquery.Schema, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || !s.time().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.schema, true
+}
+
+// put caches the schema of the key for the TTL of the cache.
+func (s *schemaCache) put(key string, schema bigquery.Schema) {
+	ttl := s.ttl
+	if ttl == 0 {
+		ttl = DefaultSchemaCacheTTL
+	}
+	if ttl < 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = map[string]schemaEntry{}
+	}
+	s.entries[key] = schemaEntry{schema: schema, expires: s.time().Add(ttl)}
+}
+
+// time returns the current time of the cache.
+func (s *schemaCache) time() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
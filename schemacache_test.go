@@ -0,0 +1,125 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// schemaTable returns a table resource in the dataset with a schema of
+// the given STRING columns.
+func schemaTable(dataset, tableID string, columns ...string) map[string]any {
+	resource := table(dataset, tableID, time.Now(), nil)
+	fields := []map[string]any{}
+	for _, column := range columns {
+		fields = append(fields, map[string]any{"name": column, "type": "STRING"})
+	}
+	resource["schema"] = map[string]any{"fields": fields}
+	return resource
+}
+
+// getTableGets returns the number of tables.get requests of the fake.
+func (f *fakeBigQuery) getTableGets() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tableGets
+}
+
+func TestClientTableSchema(t *testing.T) {
+	fake := &fakeBigQuery{tables: []map[string]any{schemaTable("analytics", "events", "id", "name")}}
+	client := newFakeClient(t, fake)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.schemas.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	schema, err := client.TableSchema(ctx, "analytics", "events")
+	if err != nil {
+		t.Fatalf("TableSchema() unexpected error: %v", err)
+	}
+	if len(schema) != 2 || schema[0].Name != "id" || schema[1].Type != bigquery.StringFieldType {
+		t.Errorf("TableSchema() = %+v", schema)
+	}
+	if _, err := client.TableSchema(ctx, "analytics", "events"); err != nil {
+		t.Fatalf("TableSchema() unexpected error: %v", err)
+	}
+	if gets := fake.getTableGets(); gets != 1 {
+		t.Errorf("tables.get requests = %d, want 1 (cached)", gets)
+	}
+	now = now.Add(DefaultSchemaCacheTTL)
+	if _, err := client.TableSchema(ctx, "analytics", "events"); err != nil {
+		t.Fatalf("TableSchema() unexpected error: %v", err)
+	}
+	if gets := fake.getTableGets(); gets != 2 {
+		t.Errorf("tables.get requests = %d, want 2 (expired)", gets)
+	}
+	client.InvalidateTableSchema("analytics", "events")
+	if _, err := client.TableSchema(ctx, "analytics", "events"); err != nil {
+		t.Fatalf("TableSchema() unexpected error: %v", err)
+	}
+	if gets := fake.getTableGets(); gets != 3 {
+		t.Errorf("tables.get requests = %d, want 3 (invalidated)", gets)
+	}
+}
+
+func TestClientTableSchemaConcurrent(t *testing.T) {
+	fake := &fakeBigQuery{tables: []map[string]any{schemaTable("analytics", "events", "id")}}
+	client := newFakeClient(t, fake)
+	client.Configure(WithSchemaCacheTTL(-1))
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.TableSchema(context.Background(), "analytics", "events"); err != nil {
+				t.Errorf("TableSchema() unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if gets := fake.getTableGets(); gets < 1 || gets > 10 {
+		t.Errorf("tables.get requests = %d, want 1 to 10", gets)
+	}
+	if len(client.schemas.entries) != 0 {
+		t.Errorf("cached schemas = %d, want none (disabled)", len(client.schemas.entries))
+	}
+}
+
+func TestClientTableSchemaErrors(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	if _, err := client.TableSchema(ctx, "analytics.x", "events"); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("TableSchema() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if _, err := client.TableSchema(ctx, "analytics", "events`"); !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("TableSchema() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if _, err := client.TableSchema(ctx, "analytics", "missing"); err == nil {
+		t.Error("TableSchema() expected error for a missing table")
+	}
+	if gets := fake.getTableGets(); gets != 1 {
+		t.Errorf("tables.get requests = %d, want 1", gets)
+	}
+}
+
+func TestAlterTableInvalidatesSchema(t *testing.T) {
+	fake := &fakeBigQuery{tables: []map[string]any{schemaTable("analytics", "events", "id")}}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	if _, err := client.TableSchema(ctx, "analytics", "events"); err != nil {
+		t.Fatalf("TableSchema() unexpected error: %v", err)
+	}
+	if err := client.AlterTable("analytics", "events").DropColumn("legacy").Exec(ctx); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if _, ok := client.schemas.get("analytics.events"); ok {
+		t.Error("schema still cached after AlterTable")
+	}
+	if err := client.DropTable(ctx, "analytics", "events", true); err != nil {
+		t.Fatalf("DropTable() unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package saferbq
+
+import (
+	"strings"
+)
+
+// declaredVariables returns the names of the scripting variables that are
+// declared with DECLARE statements in the tokens, in lower case (variable
+// names are case-insensitive), with the byte offset of their declaration.
+func declaredVariables(tokens []token) map[string]int {
+	variables := map[string]int{}
+	for _, d := range declarations(tokens) {
+		if _, seen := variables[d.name]; !seen {
+			variables[d.name] = d.offset
+		}
+	}
+	return variables
+}
+
+// declaration is a scripting variable declared with a DECLARE statement.
+type declaration struct {
+	// name is the lower case name of the variable
+	name string
+	// offset is the byte offset of the name in the SQL
+	offset int
+	// typ is the declared type, or empty when the type is inferred from
+	// the DEFAULT expression
+	typ string
+}
+
+// declarations returns the scripting variables that are declared with
+// DECLARE statements in the tokens, in order of declaration.
+//
+// A DECLARE statement declares a comma separated list of names, followed by
+// a type and/or a DEFAULT expression:
+//
+//	DECLARE x, y INT64 DEFAULT 0;
+func declarations(tokens []token) []declaration {
+	var declared []declaration
+	statementStart := true
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case tokenWhitespace, tokenComment:
+			continue
+		case tokenWord:
+			if statementStart && strings.EqualFold(tok.text, "DECLARE") {
+				start := len(declared)
+				declared, i = declareNames(tokens, i+1, declared)
+				var typ string
+				typ, i = declareType(tokens, i+1)
+				for j := start; j < len(declared); j++ {
+					declared[j].typ = typ
+				}
+				statementStart = false
+				continue
+			}
+		}
+		// Statements start after a semicolon or the BEGIN of a block
+		statementStart = tok.text == ";" || (tok.kind == tokenWord && strings.EqualFold(tok.text, "BEGIN"))
+	}
+	return declared
+}
+
+// declareNames appends the comma separated names of the DECLARE statement
+// starting at token i to the declarations, and returns them with the index
+// of the last token of the names.
+func declareNames(tokens []token, i int, declared []declaration) ([]declaration, int) {
+	expectName := true
+	last := i - 1
+	for ; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.kind == tokenWhitespace || tok.kind == tokenComment:
+			continue
+		case expectName && (tok.kind == tokenWord || tok.kind == tokenQuotedIdentifier):
+			name := strings.ToLower(strings.Trim(tok.text, "`"))
+			declared = append(declared, declaration{name: name, offset: tok.offset})
+			expectName = false
+		case !expectName && tok.text == ",":
+			expectName = true
+		default:
+			return declared, last
+		}
+		last = i
+	}
+	return declared, last
+}
+
+// declareType returns the type of the DECLARE statement that starts at
+// token i, after the names, with the index of the last token of the type.
+// The type ends at a DEFAULT or semicolon outside of brackets, whitespace
+// and comments in the type are reduced to single spaces.
+func declareType(tokens []token, i int) (string, int) {
+	var typ strings.Builder
+	depth := 0
+	last := i - 1
+	for ; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case depth == 0 && (tok.text == ";" || (tok.kind == tokenWord && strings.EqualFold(tok.text, "DEFAULT"))):
+			return strings.TrimSpace(typ.String()), last
+		case tok.kind == tokenWhitespace || tok.kind == tokenComment:
+			if typ.Len() > 0 && !strings.HasSuffix(typ.String(), " ") {
+				typ.WriteByte(' ')
+			}
+			continue
+		case tok.text == "<" || tok.text == "(":
+			depth++
+		case tok.text == ">" || tok.text == ")":
+			depth--
+		}
+		typ.WriteString(tok.text)
+		last = i
+	}
+	return strings.TrimSpace(typ.String()), last
+}
+
+// scriptVariable returns the name of the scripting variable that the
+// @parameter refers to, when the variable is declared before the first
+// occurrence of the parameter, or false otherwise.
+func (t *template) scriptVariable(paramName string) (string, bool) {
+	name := strings.TrimPrefix(paramName, "@")
+	offset, ok := t.variables[strings.ToLower(name)]
+	if !ok || offset > t.offsets[paramName] {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,65 @@
+package saferbq
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestDeclaredVariables(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"none", "SELECT 1", nil},
+		{"single", "DECLARE x INT64; SET x = 1", []string{"x"}},
+		{"list", "DECLARE x, `Y` , z INT64 DEFAULT 0;", []string{"x", "y", "z"}},
+		{"default only", "DECLARE start_date DEFAULT CURRENT_DATE();", []string{"start_date"}},
+		{"multiple", "DECLARE a STRING;\n-- comment\nDECLARE b INT64;", []string{"a", "b"}},
+		{"block", "BEGIN DECLARE inner_var INT64; END", []string{"inner_var"}},
+		{"not a statement", "SELECT declare FROM t", nil},
+		{"in a string", "SELECT 'DECLARE x INT64'", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Sorted(maps.Keys(declaredVariables(scan(tt.sql))))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("declaredVariables(%q) = %v, want %v", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptTranslation(t *testing.T) {
+	client := &Client{}
+	sql := "DECLARE max_id INT64;\nSET max_id = @max_id;\nSELECT * FROM $table WHERE id <= max_id;\nSELECT @@script.row_count"
+	q := client.Query(sql)
+	q.SetParams(map[string]any{"$table": "events", "@max_id": 10})
+	if err := q.translate(); err != nil {
+		t.Fatalf("translate() unexpected error: %v", err)
+	}
+	want := "DECLARE max_id INT64;\nSET max_id = @max_id;\nSELECT * FROM `events` WHERE id <= max_id;\nSELECT @@script.row_count"
+	if q.Q != want {
+		t.Errorf("SQL = %q, want %q", q.Q, want)
+	}
+
+	// A variable referenced with @ is reported as such
+	q = client.Query("DECLARE Max_Id INT64 DEFAULT 10;\nSELECT * FROM $table WHERE id <= @max_id")
+	q.SetParams(map[string]any{"$table": "events"})
+	err := q.translate()
+	if !errors.Is(err, ErrScriptVariable) {
+		t.Fatalf("translate() error = %v, want ErrScriptVariable", err)
+	}
+	if want := "failed to translate query: parameter refers to a script variable: @max_id at line 2, col 34, reference it as max_id"; err.Error() != want {
+		t.Errorf("translate() error = %q, want %q", err.Error(), want)
+	}
+
+	// A parameter used before the declaration is a missing parameter
+	q = client.Query("SELECT @x; DECLARE x INT64")
+	if err := q.translate(); !errors.Is(err, ErrParameterNotProvided) {
+		t.Errorf("translate() error = %v, want ErrParameterNotProvided", err)
+	}
+}
@@ -0,0 +1,71 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// QueryStats are the statistics of a completed query job, see Stats.
+type QueryStats struct {
+	// JobID is the ID of the job that ran the query
+	JobID string
+	// TotalBytesProcessed is the number of bytes the query processed
+	TotalBytesProcessed int64
+	// TotalBytesBilled is the number of bytes that were billed
+	TotalBytesBilled int64
+	// SlotMillis is the slot time the query consumed, in milliseconds
+	SlotMillis int64
+	// CacheHit is set when the results came from the query cache
+	CacheHit bool
+	// ReferencedTables are the tables that the query read
+	ReferencedTables []*bigquery.Table
+	// Duration is the time between the start and the end of the job
+	Duration time.Duration
+}
+
+// Stats returns the statistics of the last job that ran the query with Run
+// or Read (and the methods built on them, such as Exec), waiting for the
+// job to complete if needed. With dynamic identifiers, the referenced
+// tables show which tables the query actually read.
+//
+// Example:
+//
+//	it, err := q.Read(ctx)
+//	...
+//	stats, err := q.Stats(ctx)
+//	if err == nil {
+//	    log.Printf("billed %d bytes, tables %v", stats.TotalBytesBilled, stats.ReferencedTables)
+//	}
+//
+// Returns ErrNotRun if the query has not been run, or an error if the
+// status of the job could not be read.
+func (q *Query) Stats(ctx context.Context) (*QueryStats, error) {
+	if q.job == nil {
+		return nil, ErrNotRun
+	}
+	status := q.job.LastStatus()
+	if status == nil || !status.Done() || status.Statistics == nil {
+		var err error
+		if status, err = q.job.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get query statistics: %w", err)
+		}
+	}
+	stats := &QueryStats{JobID: q.job.ID()}
+	if status.Statistics == nil {
+		return stats, nil
+	}
+	stats.TotalBytesProcessed = status.Statistics.TotalBytesProcessed
+	if status.Statistics.EndTime.After(status.Statistics.StartTime) {
+		stats.Duration = status.Statistics.EndTime.Sub(status.Statistics.StartTime)
+	}
+	if details, ok := status.Statistics.Details.(*bigquery.QueryStatistics); ok {
+		stats.TotalBytesBilled = details.TotalBytesBilled
+		stats.SlotMillis = details.SlotMillis
+		stats.CacheHit = details.CacheHit
+		stats.ReferencedTables = details.ReferencedTables
+	}
+	return stats, nil
+}
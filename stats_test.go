@@ -0,0 +1,57 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newStatsFake returns a fake that reports query statistics.
+func newStatsFake() *fakeBigQuery {
+	fake := newReadFake()
+	fake.statistics = map[string]any{
+		"totalBytesProcessed": "4096",
+		"totalBytesBilled":    "10485760",
+		"totalSlotMs":         "1234",
+		"cacheHit":            false,
+		"referencedTables": []map[string]any{
+			{"projectId": "test-project", "datasetId": "analytics", "tableId": "events_acme"},
+		},
+	}
+	return fake
+}
+
+func TestQueryStats(t *testing.T) {
+	client := newFakeClient(t, newStatsFake())
+	ctx := context.Background()
+	for _, method := range []string{"Run", "Read", "Exec"} {
+		q := client.Query("SELECT id, name FROM $dataset.$table")
+		q.SetParams(map[string]any{"$dataset": "analytics", "$table": "events_acme"})
+		if _, err := q.Stats(ctx); !errors.Is(err, ErrNotRun) {
+			t.Errorf("Stats() before %s error = %v, want %v", method, err, ErrNotRun)
+		}
+		var err error
+		switch method {
+		case "Run":
+			_, err = q.Run(ctx)
+		case "Read":
+			_, err = q.Read(ctx)
+		case "Exec":
+			_, err = q.Exec(ctx)
+		}
+		if err != nil {
+			t.Fatalf("%s() unexpected error: %v", method, err)
+		}
+		stats, err := q.Stats(ctx)
+		if err != nil {
+			t.Fatalf("Stats() after %s unexpected error: %v", method, err)
+		}
+		if stats.JobID == "" || stats.TotalBytesProcessed != 4096 || stats.TotalBytesBilled != 10485760 ||
+			stats.SlotMillis != 1234 || stats.CacheHit {
+			t.Errorf("Stats() after %s = %+v", method, stats)
+		}
+		if len(stats.ReferencedTables) != 1 || stats.ReferencedTables[0].TableID != "events_acme" {
+			t.Errorf("Stats() after %s referenced tables = %v, want events_acme", method, stats.ReferencedTables)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Stmt is a prepared query template. The SQL is parsed and validated once,
+// when the statement is prepared, and only the parameter values are bound
+// and validated on every execution.
+//
+// A Stmt is safe for concurrent use by multiple goroutines.
+//
+// Use Client.Prepare() to create a new Stmt instance.
+type Stmt struct {
+	client   *Client
+	template *template
+}
+
+// Prepare parses and validates the SQL once and returns a reusable statement.
+// All $identifier and @parameter references are located and the number of
+// positional parameters is fixed, so executions only need to bind values.
+//
+// Example:
+//
+//	stmt, err := client.Prepare("SELECT * FROM $table WHERE status = @status")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	job, err := stmt.Run(ctx,
+//	    bigquery.QueryParameter{Name: "$table", Value: "users"},
+//	    bigquery.QueryParameter{Name: "@status", Value: "active"},
+//	)
+//
+// Returns an error if the SQL is empty or mixes positional and named parameters.
+func (c *Client) Prepare(sql string) (*Stmt, error) {
+	t, err := parseQuery(sql, c.namedPositionals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+	if c.registerStatements {
+		c.statements.LoadOrStore(sql, t)
+	}
+	return &Stmt{client: c, template: t}, nil
+}
+
+// SQL returns the original SQL of the prepared statement.
+func (s *Stmt) SQL() string {
+	return s.template.source
+}
+
+// Query creates a new Query from the prepared statement with the given
+// parameters. The returned Query can be further configured before it is
+// executed and it reuses the parsed template during translation.
+func (s *Stmt) Query(params ...bigquery.QueryParameter) *Query {
+	q := s.client.Query(s.template.source)
+	q.Parameters = params
+	q.template = s.template
+	return q
+}
+
+// Run binds the parameters to the prepared statement and initiates a query job.
+//
+// Returns an error if parameter validation fails or if the
+// underlying BigQuery query execution fails.
+func (s *Stmt) Run(ctx context.Context, params ...bigquery.QueryParameter) (*bigquery.Job, error) {
+	return s.Query(params...).Run(ctx)
+}
+
+// Read binds the parameters to the prepared statement, submits the query for
+// execution and returns results via a RowIterator.
+//
+// Returns an error if parameter validation fails or if the
+// underlying BigQuery query execution fails.
+func (s *Stmt) Read(ctx context.Context, params ...bigquery.QueryParameter) (*bigquery.RowIterator, error) {
+	return s.Query(params...).Read(ctx)
+}
+
+// ReadRows is like Read, but returns a saferbq.RowIterator, see
+// Query.ReadRows.
+func (s *Stmt) ReadRows(ctx context.Context, params ...bigquery.QueryParameter) (*RowIterator, error) {
+	return s.Query(params...).ReadRows(ctx)
+}
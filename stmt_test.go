@@ -0,0 +1,122 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/option"
+)
+
+func TestClientPrepare(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewClient(ctx, "test-project", option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name         string
+		sql          string
+		errorMessage string
+	}{
+		{
+			name: "identifiers and named parameters",
+			sql:  "SELECT * FROM $table WHERE status = @status",
+		},
+		{
+			name:         "empty SQL string",
+			sql:          "",
+			errorMessage: "failed to prepare query: query SQL cannot be empty",
+		},
+		{
+			name:         "mixing positional and named parameters",
+			sql:          "SELECT * FROM $table WHERE id = ? AND status = @status",
+			errorMessage: "failed to prepare query: cannot mix positional (?) and named (@) parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := client.Prepare(tt.sql)
+			if err != nil {
+				if tt.errorMessage == "" {
+					t.Fatalf("Prepare() unexpected error: %v", err)
+				}
+				if err.Error() != tt.errorMessage {
+					t.Fatalf("Prepare() error = %q, want %q", err.Error(), tt.errorMessage)
+				}
+				return
+			}
+			if tt.errorMessage != "" {
+				t.Fatalf("Prepare() expected error %q but got none", tt.errorMessage)
+			}
+			if stmt.SQL() != tt.sql {
+				t.Errorf("Stmt.SQL() = %q, want %q", stmt.SQL(), tt.sql)
+			}
+		})
+	}
+}
+
+func TestStmtQuery(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewClient(ctx, "test-project", option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	defer client.Close()
+
+	stmt, err := client.Prepare("SELECT * FROM $table1 JOIN $table ON $table1.id = $table.id WHERE id = ?")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, table := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q := stmt.Query(
+				bigquery.QueryParameter{Name: "$table", Value: table},
+				bigquery.QueryParameter{Name: "$table1", Value: table + "1"},
+				bigquery.QueryParameter{Value: 1},
+			)
+			if err := q.translate(); err != nil {
+				t.Errorf("translate() unexpected error: %v", err)
+				return
+			}
+			expectedSQL := "SELECT * FROM `" + table + "1` JOIN `" + table + "` ON `" + table + "1`.id = `" + table + "`.id WHERE id = ?"
+			if q.QueryConfig.Q != expectedSQL {
+				t.Errorf("translate() SQL = %q, want %q", q.QueryConfig.Q, expectedSQL)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStmtRunError(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewClient(ctx, "test-project", option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	defer client.Close()
+
+	stmt, err := client.Prepare("SELECT * FROM $table")
+	if err != nil {
+		t.Fatalf("Prepare() unexpected error: %v", err)
+	}
+
+	_, err = stmt.Run(ctx, bigquery.QueryParameter{Name: "$table", Value: "test;DROP"})
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("Run() error = %v, want ErrIdentifierInvalidChars", err)
+	}
+
+	_, err = stmt.Read(ctx)
+	if !errors.Is(err, ErrIdentifierNotProvided) {
+		t.Errorf("Read() error = %v, want ErrIdentifierNotProvided", err)
+	}
+}
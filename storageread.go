@@ -0,0 +1,65 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// storageReader enables the Storage Read API of a client once.
+type storageReader struct {
+	mu      sync.Mutex
+	enabled bool
+}
+
+// WithStorageRead downloads the results of all reads of the client through
+// the BigQuery Storage Read API, which is much faster for large results
+// than the tabledata path. The read client is created with the options the
+// client was created with, when the first query is read.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithStorageRead())
+func WithStorageRead() Option {
+	return func(c *Client) {
+		c.storageRead = true
+	}
+}
+
+// ReadStorage translates and runs the query like Read, but downloads the
+// results through the BigQuery Storage Read API. The Storage Read API is
+// enabled for the whole client, so subsequent reads use it as well, see
+// WithStorageRead.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table")
+//	q.SetParams(map[string]any{"$table": "events"})
+//	it, err := q.ReadStorage(ctx)
+//
+// Returns an error if the read client could not be created, or any error
+// that Read returns.
+func (q *Query) ReadStorage(ctx context.Context) (*RowIterator, error) {
+	if err := q.client.enableStorageRead(ctx); err != nil {
+		return nil, err
+	}
+	return q.ReadRows(ctx)
+}
+
+// enableStorageRead creates the Storage Read API client of the client, if
+// it was not created yet. The client may be nil.
+func (c *Client) enableStorageRead(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	c.storageReader.mu.Lock()
+	defer c.storageReader.mu.Unlock()
+	if c.storageReader.enabled {
+		return nil
+	}
+	if err := c.Client.EnableStorageReadClient(ctx, c.clientOptions...); err != nil {
+		return fmt.Errorf("failed to enable storage read: %w", err)
+	}
+	c.storageReader.enabled = true
+	return nil
+}
@@ -0,0 +1,32 @@
+package saferbq
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnableStorageReadOnce(t *testing.T) {
+	client := newFakeClient(t, &fakeBigQuery{})
+	for i := 0; i < 2; i++ {
+		if err := client.enableStorageRead(context.Background()); err != nil {
+			t.Fatalf("enableStorageRead() call %d unexpected error: %v", i+1, err)
+		}
+	}
+	if !client.storageReader.enabled {
+		t.Error("enableStorageRead() did not enable the storage read client")
+	}
+}
+
+func TestEnableStorageReadNilClient(t *testing.T) {
+	var client *Client
+	if err := client.enableStorageRead(context.Background()); err != nil {
+		t.Errorf("enableStorageRead() unexpected error: %v", err)
+	}
+}
+
+func TestWithStorageRead(t *testing.T) {
+	client := (&Client{}).Configure(WithStorageRead())
+	if !client.storageRead {
+		t.Error("WithStorageRead() did not enable storage read")
+	}
+}
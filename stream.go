@@ -0,0 +1,76 @@
+package saferbq
+
+import (
+	"context"
+	"iter"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// streamBufferSize is the number of rows that Stream reads ahead of the
+// consumer
+const streamBufferSize = 1024
+
+// streamRow is a row, or the error reading it, passed from the reading
+// goroutine of Stream to the consumer.
+type streamRow struct {
+	values []bigquery.Value
+	err    error
+}
+
+// Stream translates and runs the query like Read, and returns the result
+// rows as an iterator. The rows are read (and the pages fetched) in a
+// background goroutine, at most streamBufferSize rows ahead of the
+// consumer, so a slow consumer holds back the reads. Breaking out of the
+// loop stops the reading goroutine.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table")
+//	q.SetParams(map[string]any{"$table": "events_" + tenant})
+//	for row, err := range q.Stream(ctx) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(row)
+//	}
+//
+// The iterator yields a single error if the query fails, or a *RowError
+// as the last value if a row could not be read.
+func (q *Query) Stream(ctx context.Context) iter.Seq2[[]bigquery.Value, error] {
+	return func(yield func([]bigquery.Value, error) bool) {
+		it, err := q.ReadRows(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		rows := make(chan streamRow, streamBufferSize)
+		go func() {
+			defer close(rows)
+			defer it.Close()
+			for {
+				var row streamRow
+				row.err = it.Next(&row.values)
+				if row.err == iterator.Done {
+					return
+				}
+				select {
+				case rows <- row:
+				case <-ctx.Done():
+					return
+				}
+				if row.err != nil {
+					return
+				}
+			}
+		}()
+		for row := range rows {
+			if !yield(row.values, row.err) || row.err != nil {
+				return
+			}
+		}
+	}
+}
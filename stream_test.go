@@ -0,0 +1,58 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestQueryStream(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	rows := [][]bigquery.Value{}
+	for row, err := range q.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream() unexpected error: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) != 2 || rows[0][0] != int64(1) || rows[1][1] != "b" {
+		t.Errorf("Stream() rows = %v", rows)
+	}
+}
+
+func TestQueryStreamBreak(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "events"})
+	count := 0
+	for _, err := range q.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream() unexpected error: %v", err)
+		}
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Stream() yielded %d rows before break, want 1", count)
+	}
+}
+
+func TestQueryStreamError(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": ""})
+	count := 0
+	for row, err := range q.Stream(context.Background()) {
+		count++
+		if row != nil || !errors.Is(err, ErrIdentifierEmpty) {
+			t.Errorf("Stream() = %v, %v, want nil, %v", row, err, ErrIdentifierEmpty)
+		}
+	}
+	if count != 1 {
+		t.Errorf("Stream() yielded %d values, want 1", count)
+	}
+}
@@ -0,0 +1,67 @@
+package saferbq
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// inline translates the query for use as the value of the named $identifier
+// of another query. It returns the translated SQL in parentheses and the
+// named parameters of the query. Parameters whose name is already taken
+// are renamed (by appending _1, _2, ...) in both the SQL and the returned
+// parameters, and their new names are added to taken.
+//
+// Returns an error if the query fails to translate or has positional
+// parameters, as their order can't be merged into the other query.
+func (q *Query) inline(name string, taken map[string]bool) (string, []bigquery.QueryParameter, error) {
+	if err := q.translate(); err != nil {
+		return "", nil, fmt.Errorf("subquery %s: %w", name, err)
+	}
+	for _, p := range q.Parameters {
+		if p.Name == "" {
+			return "", nil, fmt.Errorf("%w: subquery %s has positional parameters", ErrMixedParameterTypes, name)
+		}
+	}
+	sql, params := renameParameters(q.QueryConfig.Q, q.Parameters, taken)
+	return "(" + sql + ")", params, nil
+}
+
+// renameParameters renames the named parameters of the translated SQL whose
+// name is already taken (by appending _1, _2, ...) in both the SQL and the
+// returned parameters, and adds their new names to taken. Positional
+// parameters are returned unchanged.
+func renameParameters(sql string, parameters []bigquery.QueryParameter, taken map[string]bool) (string, []bigquery.QueryParameter) {
+	renames := map[string]string{}
+	params := make([]bigquery.QueryParameter, 0, len(parameters))
+	for _, p := range parameters {
+		if p.Name == "" {
+			params = append(params, p)
+			continue
+		}
+		newName := p.Name
+		for i := 1; taken[newName]; i++ {
+			newName = fmt.Sprintf("%s_%d", p.Name, i)
+		}
+		taken[newName] = true
+		if newName != p.Name {
+			renames[string(atSign)+p.Name] = string(atSign) + newName
+			p.Name = newName
+		}
+		params = append(params, p)
+	}
+	if len(renames) == 0 {
+		return sql, params
+	}
+	var result strings.Builder
+	result.Grow(len(sql))
+	for _, tok := range scan(sql) {
+		if newName, ok := renames[tok.text]; ok && tok.kind == tokenNamedParam {
+			result.WriteString(newName)
+		} else {
+			result.WriteString(tok.text)
+		}
+	}
+	return result.String(), params
+}
@@ -0,0 +1,112 @@
+package saferbq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestSubquery(t *testing.T) {
+	client := &Client{}
+	tests := []struct {
+		name          string
+		sub           func() *Query
+		outerParams   map[string]any
+		sqlOut        string
+		parametersOut []bigquery.QueryParameter
+		err           error
+	}{
+		{
+			name: "identifiers only",
+			sub: func() *Query {
+				sub := client.Query("SELECT user_id FROM $table")
+				sub.SetParams(map[string]any{"$table": "active_users"})
+				return sub
+			},
+			sqlOut:        "SELECT * FROM `events` WHERE user_id IN (SELECT user_id FROM `active_users`)",
+			parametersOut: []bigquery.QueryParameter{},
+		},
+		{
+			name: "named parameters are merged",
+			sub: func() *Query {
+				sub := client.Query("SELECT user_id FROM users WHERE country = @country")
+				sub.SetParams(map[string]any{"@country": "NL"})
+				return sub
+			},
+			outerParams:   map[string]any{"@day": "2024-01-01"},
+			sqlOut:        "SELECT * FROM `events` WHERE user_id IN (SELECT user_id FROM users WHERE country = @country) AND day = @day",
+			parametersOut: []bigquery.QueryParameter{{Name: "day", Value: "2024-01-01"}, {Name: "country", Value: "NL"}},
+		},
+		{
+			name: "conflicting names are renamed",
+			sub: func() *Query {
+				sub := client.Query("SELECT user_id FROM users WHERE day = @day AND day_1 = @day_1 AND '@day' != ''")
+				sub.SetParams(map[string]any{"@day": "2023-12-31", "@day_1": "2023-12-30"})
+				return sub
+			},
+			outerParams:   map[string]any{"@day": "2024-01-01"},
+			sqlOut:        "SELECT * FROM `events` WHERE user_id IN (SELECT user_id FROM users WHERE day = @day_1 AND day_1 = @day_1_1 AND '@day' != '') AND day = @day",
+			parametersOut: []bigquery.QueryParameter{{Name: "day", Value: "2024-01-01"}, {Name: "day_1", Value: "2023-12-31"}, {Name: "day_1_1", Value: "2023-12-30"}},
+		},
+		{
+			name: "invalid subquery",
+			sub: func() *Query {
+				sub := client.Query("SELECT user_id FROM $table")
+				sub.SetParams(map[string]any{"$table": "a;b"})
+				return sub
+			},
+			err: ErrIdentifierInvalidChars,
+		},
+		{
+			name: "positional parameters in subquery",
+			sub: func() *Query {
+				sub := client.Query("SELECT user_id FROM users WHERE country = ?")
+				sub.SetPositionalParams("NL")
+				return sub
+			},
+			err: ErrMixedParameterTypes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := "SELECT * FROM $table WHERE user_id IN $users"
+			if _, ok := tt.outerParams["@day"]; ok {
+				sql += " AND day = @day"
+			}
+			q := client.Query(sql)
+			params := map[string]any{"$table": "events", "$users": tt.sub()}
+			for name, value := range tt.outerParams {
+				params[name] = value
+			}
+			q.SetParams(params)
+			err := q.translate()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("translate() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if q.Q != tt.sqlOut {
+				t.Errorf("translate() = %q, want %q", q.Q, tt.sqlOut)
+			}
+			if !reflect.DeepEqual(q.Parameters, tt.parametersOut) {
+				t.Errorf("translate() parameters = %v, want %v", q.Parameters, tt.parametersOut)
+			}
+		})
+	}
+}
+
+func TestSubqueryNamedInPositionalQuery(t *testing.T) {
+	client := &Client{}
+	sub := client.Query("SELECT user_id FROM users WHERE country = @country")
+	sub.SetParams(map[string]any{"@country": "NL"})
+	q := client.Query("SELECT * FROM events WHERE user_id IN $users AND day = ?")
+	q.SetParams(map[string]any{"$users": sub})
+	q.SetPositionalParams("2024-01-01")
+	if err := q.translate(); !errors.Is(err, ErrMixedParameterTypes) {
+		t.Errorf("translate() error = %v, want %v", err, ErrMixedParameterTypes)
+	}
+}
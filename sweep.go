@@ -0,0 +1,145 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// Sweep configures the removal of expired tables from a dataset.
+type Sweep struct {
+	// Dataset is the ID of the dataset that is swept, in the project of
+	// the client
+	Dataset string
+	// Pattern is a glob pattern (see path.Match) that the table names must
+	// match, for example "tmp_*"; use "*" to match all tables
+	Pattern string
+	// Labels are labels that the tables must have (all must match)
+	Labels map[string]string
+	// TTL is the age after which a table is expired
+	TTL time.Duration
+	// ArchiveDataset is the ID of the dataset expired tables are copied to
+	// before they are dropped (optional)
+	ArchiveDataset string
+	// DryRun only reports the expired tables without removing them
+	DryRun bool
+}
+
+// SweepTables drops (or archives) all tables in the dataset that match the
+// pattern and labels and are older than the TTL, to keep scratch datasets
+// clean. The DROP and COPY statements are executed as queries with
+// $identifier parameters in the background lane, so they are validated,
+// guarded, traced, logged and counted like all other queries.
+//
+// Example:
+//
+//	swept, err := client.SweepTables(ctx, saferbq.Sweep{
+//	    Dataset: "scratch",
+//	    Pattern: "tmp_*",
+//	    Labels:  map[string]string{"owner": "etl"},
+//	    TTL:     7 * 24 * time.Hour,
+//	})
+//
+// Returns the names of the expired tables, and an error wrapping
+// ErrInvalidSweep if the sweep is not valid, or an error if listing or
+// removing the tables fails.
+func (c *Client) SweepTables(ctx context.Context, s Sweep) ([]string, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	expired, err := c.expiredTables(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	if s.DryRun {
+		return expired, nil
+	}
+	for i, table := range expired {
+		if err := c.sweepTable(ctx, s, table); err != nil {
+			return expired[:i], fmt.Errorf("failed to sweep table %s: %w", table, err)
+		}
+	}
+	return expired, nil
+}
+
+// validate checks the sweep for missing or invalid fields. The datasets
+// must be dataset IDs, not paths, as the tables are listed in the dataset
+// of the client project.
+func (s Sweep) validate() error {
+	if _, err := DatasetID(s.Dataset).render("dataset"); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSweep, err)
+	}
+	if s.ArchiveDataset != "" {
+		if _, err := DatasetID(s.ArchiveDataset).render("archive dataset"); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSweep, err)
+		}
+	}
+	if s.Pattern == "" {
+		return fmt.Errorf("%w: pattern is required", ErrInvalidSweep)
+	}
+	if _, err := path.Match(s.Pattern, ""); err != nil {
+		return fmt.Errorf("%w: pattern %q: %w", ErrInvalidSweep, s.Pattern, err)
+	}
+	if s.TTL <= 0 {
+		return fmt.Errorf("%w: TTL must be positive", ErrInvalidSweep)
+	}
+	return nil
+}
+
+// expiredTables returns the names of the tables that should be swept.
+func (c *Client) expiredTables(ctx context.Context, s Sweep) ([]string, error) {
+	cutoff := time.Now().Add(-s.TTL)
+	expired := []string{}
+	it := c.Dataset(s.Dataset).Tables(ctx)
+	for {
+		table, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if matched, _ := path.Match(s.Pattern, table.TableID); !matched {
+			continue
+		}
+		meta, err := table.Metadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if meta.Type == bigquery.RegularTable && meta.CreationTime.Before(cutoff) && hasLabels(meta.Labels, s.Labels) {
+			expired = append(expired, table.TableID)
+		}
+	}
+	return expired, nil
+}
+
+// hasLabels checks if all wanted labels are present with the same value.
+func hasLabels(labels, wanted map[string]string) bool {
+	for key, value := range wanted {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sweepTable copies the table to the archive dataset (if any) and drops it.
+func (c *Client) sweepTable(ctx context.Context, s Sweep, table string) error {
+	if s.ArchiveDataset != "" {
+		q := c.Query("CREATE TABLE $archive.$table COPY $dataset.$table")
+		q.SetParams(map[string]any{"$archive": DatasetID(s.ArchiveDataset), "$dataset": DatasetID(s.Dataset), "$table": TableID(table)})
+		q.Lane = LaneBackground
+		if _, err := q.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	q := c.Query("DROP TABLE $dataset.$table")
+	q.SetParams(map[string]any{"$dataset": DatasetID(s.Dataset), "$table": TableID(table)})
+	q.Lane = LaneBackground
+	_, err := q.Exec(ctx)
+	return err
+}
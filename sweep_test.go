@@ -0,0 +1,81 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSweepTables(t *testing.T) {
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	fresh := time.Now().Add(-time.Hour)
+	fake := &fakeBigQuery{tables: []map[string]any{
+		table("scratch", "tmp_old", old, map[string]string{"owner": "etl"}),
+		table("scratch", "tmp_fresh", fresh, map[string]string{"owner": "etl"}),
+		table("scratch", "tmp_other_owner", old, map[string]string{"owner": "bi"}),
+		table("scratch", "users", old, map[string]string{"owner": "etl"}),
+	}}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	sweep := Sweep{
+		Dataset: "scratch",
+		Pattern: "tmp_*",
+		Labels:  map[string]string{"owner": "etl"},
+		TTL:     7 * 24 * time.Hour,
+		DryRun:  true,
+	}
+
+	swept, err := client.SweepTables(ctx, sweep)
+	if err != nil {
+		t.Fatalf("SweepTables() unexpected error: %v", err)
+	}
+	if want := []string{"tmp_old"}; !reflect.DeepEqual(swept, want) {
+		t.Errorf("SweepTables() = %v, want %v", swept, want)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("dry run executed queries = %q, want none", queries)
+	}
+
+	sweep.DryRun = false
+	sweep.ArchiveDataset = "archive"
+	if _, err := client.SweepTables(ctx, sweep); err != nil {
+		t.Fatalf("SweepTables() unexpected error: %v", err)
+	}
+	want := []string{
+		"CREATE TABLE `archive`.`tmp_old` COPY `scratch`.`tmp_old`",
+		"DROP TABLE `scratch`.`tmp_old`",
+	}
+	if queries := fake.executedQueries(); !reflect.DeepEqual(queries, want) {
+		t.Errorf("executed queries = %q, want %q", queries, want)
+	}
+}
+
+func TestSweepTablesInvalid(t *testing.T) {
+	client := &Client{}
+	tests := []struct {
+		name  string
+		sweep Sweep
+		err   error
+	}{
+		{"empty dataset", Sweep{Pattern: "*", TTL: time.Hour}, ErrIdentifierEmpty},
+		{"invalid dataset", Sweep{Dataset: "a;b", Pattern: "*", TTL: time.Hour}, ErrIdentifierInvalidChars},
+		{"invalid archive", Sweep{Dataset: "a", ArchiveDataset: "a;b", Pattern: "*", TTL: time.Hour}, ErrIdentifierInvalidChars},
+		{"dataset path", Sweep{Dataset: "other-project.scratch", Pattern: "*", TTL: time.Hour}, ErrIdentifierInvalidChars},
+		{"dotted dataset", Sweep{Dataset: "scratch.tmp", Pattern: "*", TTL: time.Hour}, ErrIdentifierInvalidChars},
+		{"archive path", Sweep{Dataset: "a", ArchiveDataset: "other.archive", Pattern: "*", TTL: time.Hour}, ErrIdentifierInvalidChars},
+		{"archive with dash", Sweep{Dataset: "a", ArchiveDataset: "my-archive", Pattern: "*", TTL: time.Hour}, ErrIdentifierInvalidChars},
+		{"no pattern", Sweep{Dataset: "a", TTL: time.Hour}, ErrInvalidSweep},
+		{"bad pattern", Sweep{Dataset: "a", Pattern: "[", TTL: time.Hour}, ErrInvalidSweep},
+		{"no TTL", Sweep{Dataset: "a", Pattern: "*"}, ErrInvalidSweep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.SweepTables(context.Background(), tt.sweep)
+			if !errors.Is(err, ErrInvalidSweep) || !errors.Is(err, tt.err) {
+				t.Errorf("SweepTables() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
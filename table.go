@@ -0,0 +1,327 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// maxClusteringColumns is the maximum number of clustering columns of a table
+const maxClusteringColumns = 4
+
+// fieldTypes maps the schema field types to their GoogleSQL type names
+var fieldTypes = map[bigquery.FieldType]string{
+	bigquery.StringFieldType:     "STRING",
+	bigquery.BytesFieldType:      "BYTES",
+	bigquery.IntegerFieldType:    "INT64",
+	bigquery.FloatFieldType:      "FLOAT64",
+	bigquery.BooleanFieldType:    "BOOL",
+	bigquery.TimestampFieldType:  "TIMESTAMP",
+	bigquery.DateFieldType:       "DATE",
+	bigquery.TimeFieldType:       "TIME",
+	bigquery.DateTimeFieldType:   "DATETIME",
+	bigquery.NumericFieldType:    "NUMERIC",
+	bigquery.BigNumericFieldType: "BIGNUMERIC",
+	bigquery.GeographyFieldType:  "GEOGRAPHY",
+	bigquery.IntervalFieldType:   "INTERVAL",
+	bigquery.JSONFieldType:       "JSON",
+}
+
+// TableOption configures the CREATE TABLE statement of CreateTable.
+type TableOption func(*tableOptions)
+
+// tableOptions holds the configuration of a CREATE TABLE statement.
+type tableOptions struct {
+	ifNotExists bool
+	orReplace   bool
+	partitionBy string
+	clusterBy   []string
+	expiration  time.Time
+	description string
+	labels      map[string]string
+}
+
+// IfNotExists only creates the table when it does not exist.
+func IfNotExists() TableOption {
+	return func(o *tableOptions) {
+		o.ifNotExists = true
+	}
+}
+
+// OrReplace replaces the table when it exists.
+func OrReplace() TableOption {
+	return func(o *tableOptions) {
+		o.orReplace = true
+	}
+}
+
+// PartitionBy partitions the table by day on the column, that must be a
+// DATE, TIMESTAMP or DATETIME column of the schema.
+func PartitionBy(column string) TableOption {
+	return func(o *tableOptions) {
+		o.partitionBy = column
+	}
+}
+
+// ClusterBy clusters the table on up to four columns of the schema.
+func ClusterBy(columns ...string) TableOption {
+	return func(o *tableOptions) {
+		o.clusterBy = columns
+	}
+}
+
+// ExpiresAt sets the time at which the table is deleted.
+func ExpiresAt(t time.Time) TableOption {
+	return func(o *tableOptions) {
+		o.expiration = t
+	}
+}
+
+// WithDescription sets the description of the table.
+func WithDescription(description string) TableOption {
+	return func(o *tableOptions) {
+		o.description = description
+	}
+}
+
+// WithTableLabels sets the labels of the table.
+func WithTableLabels(labels map[string]string) TableOption {
+	return func(o *tableOptions) {
+		o.labels = labels
+	}
+}
+
+// CreateTable creates the table with the schema by running a CREATE TABLE
+// statement. The dataset and table IDs are validated (with the rules of
+// DatasetID and TableID), the column names with the rules of ColumnName,
+// and all names are quoted. Descriptions and labels are escaped as string
+// literals.
+//
+// Example:
+//
+//	schema := bigquery.Schema{
+//	    {Name: "id", Type: bigquery.IntegerFieldType, Required: true},
+//	    {Name: "created_at", Type: bigquery.TimestampFieldType},
+//	    {Name: "country", Type: bigquery.StringFieldType},
+//	}
+//	err := client.CreateTable(ctx, "analytics", "events_"+tenant, schema,
+//	    saferbq.IfNotExists(),
+//	    saferbq.PartitionBy("created_at"),
+//	    saferbq.ClusterBy("country"),
+//	    saferbq.ExpiresAt(time.Now().AddDate(0, 3, 0)))
+//
+// Returns an error wrapping ErrInvalidDDL if the schema or options are not
+// valid, one of the identifier errors if a name is not valid, or an error
+// if the statement fails.
+func (c *Client) CreateTable(ctx context.Context, dataset, table string, schema bigquery.Schema, opts ...TableOption) error {
+	sql, err := createTableSQL(schema, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	if err := c.execTableStatement(ctx, sql, dataset, table); err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	return nil
+}
+
+// DropTable drops the table by running a DROP TABLE statement, with the
+// dataset and table IDs validated (with the rules of DatasetID and TableID)
+// and quoted. When ifExists is set, a missing table is not an error.
+//
+// Example:
+//
+//	err := client.DropTable(ctx, "scratch", "tmp_"+jobID, true)
+//	// DROP TABLE IF EXISTS `scratch`.`tmp_1234`
+func (c *Client) DropTable(ctx context.Context, dataset, table string, ifExists bool) error {
+	sql := "DROP TABLE $dataset.$table"
+	if ifExists {
+		sql = "DROP TABLE IF EXISTS $dataset.$table"
+	}
+	if err := c.execTableStatement(ctx, sql, dataset, table); err != nil {
+		return fmt.Errorf("failed to drop table: %w", err)
+	}
+	return nil
+}
+
+// TruncateTable deletes all rows of the table by running a TRUNCATE TABLE
+// statement, with the dataset and table IDs validated (with the rules of
+// DatasetID and TableID) and quoted.
+//
+// Example:
+//
+//	err := client.TruncateTable(ctx, "staging", "events_"+day)
+//	// TRUNCATE TABLE `staging`.`events_20240101`
+func (c *Client) TruncateTable(ctx context.Context, dataset, table string) error {
+	if err := c.execTableStatement(ctx, "TRUNCATE TABLE $dataset.$table", dataset, table); err != nil {
+		return fmt.Errorf("failed to truncate table: %w", err)
+	}
+	return nil
+}
+
+// execTableStatement runs the statement with the validated dataset and table
+// IDs bound to $dataset and $table, and removes the table from the schema
+// cache.
+func (c *Client) execTableStatement(ctx context.Context, sql, dataset, table string) error {
+	q := c.Query(sql)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset), "$table": TableID(table)})
+	if _, err := q.Exec(ctx); err != nil {
+		return err
+	}
+	c.InvalidateTableSchema(dataset, table)
+	return nil
+}
+
+// createTableSQL renders the CREATE TABLE statement for the schema, with
+// $dataset.$table as the name of the table.
+func createTableSQL(schema bigquery.Schema, opts ...TableOption) (string, error) {
+	var o tableOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ifNotExists && o.orReplace {
+		return "", fmt.Errorf("%w: IfNotExists and OrReplace can't be combined", ErrInvalidDDL)
+	}
+	if len(schema) == 0 {
+		return "", fmt.Errorf("%w: schema has no columns", ErrInvalidDDL)
+	}
+	columns, err := columnDefinitions(schema)
+	if err != nil {
+		return "", err
+	}
+	var sql strings.Builder
+	sql.WriteString("CREATE ")
+	if o.orReplace {
+		sql.WriteString("OR REPLACE ")
+	}
+	sql.WriteString("TABLE ")
+	if o.ifNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString("$dataset.$table (" + columns + ")")
+	if o.partitionBy != "" {
+		partition, err := partitionExpression(schema, o.partitionBy)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(" PARTITION BY " + partition)
+	}
+	if len(o.clusterBy) > 0 {
+		if len(o.clusterBy) > maxClusteringColumns {
+			return "", fmt.Errorf("%w: at most %d clustering columns", ErrInvalidDDL, maxClusteringColumns)
+		}
+		quoted := make([]string, len(o.clusterBy))
+		for i, column := range o.clusterBy {
+			if schemaField(schema, column) == nil {
+				return "", fmt.Errorf("%w: clustering column %s is not in the schema", ErrInvalidDDL, column)
+			}
+			quoted[i], _ = QuoteIdentifier(column)
+		}
+		sql.WriteString(" CLUSTER BY " + strings.Join(quoted, ", "))
+	}
+	options, err := o.options()
+	if err != nil {
+		return "", err
+	}
+	if len(options) > 0 {
+		sql.WriteString(" OPTIONS (" + strings.Join(options, ", ") + ")")
+	}
+	return sql.String(), nil
+}
+
+// options renders the expiration, description and labels as table options.
+// Returns an error wrapping ErrInvalidLabel if a label is not valid.
+func (o tableOptions) options() ([]string, error) {
+	var options []string
+	if !o.expiration.IsZero() {
+		options = append(options, "expiration_timestamp = TIMESTAMP "+EscapeStringLiteral(o.expiration.UTC().Format(systemTimeLayout)))
+	}
+	if o.description != "" {
+		options = append(options, "description = "+EscapeStringLiteral(o.description))
+	}
+	if len(o.labels) > 0 {
+		labels, err := labelsLiteral(o.labels)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, "labels = "+labels)
+	}
+	return options, nil
+}
+
+// columnDefinitions renders the column definitions of the schema.
+func columnDefinitions(schema bigquery.Schema) (string, error) {
+	columns := make([]string, len(schema))
+	for i, field := range schema {
+		name, err := ColumnName(field.Name).render("column " + field.Name)
+		if err != nil {
+			return "", err
+		}
+		columnType, err := columnType(field)
+		if err != nil {
+			return "", err
+		}
+		columns[i] = name + " " + columnType
+		if field.Required && !field.Repeated {
+			columns[i] += " NOT NULL"
+		}
+		if field.Description != "" {
+			columns[i] += " OPTIONS (description = " + EscapeStringLiteral(field.Description) + ")"
+		}
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// columnType renders the GoogleSQL type of the field.
+func columnType(field *bigquery.FieldSchema) (string, error) {
+	var columnType string
+	if field.Type == bigquery.RecordFieldType {
+		if len(field.Schema) == 0 {
+			return "", fmt.Errorf("%w: record column %s has no fields", ErrInvalidDDL, field.Name)
+		}
+		fields, err := columnDefinitions(field.Schema)
+		if err != nil {
+			return "", err
+		}
+		columnType = "STRUCT<" + fields + ">"
+	} else if name, ok := fieldTypes[field.Type]; ok {
+		columnType = name
+	} else {
+		return "", fmt.Errorf("%w: column %s has unsupported type %q", ErrInvalidDDL, field.Name, field.Type)
+	}
+	if field.Repeated {
+		columnType = "ARRAY<" + columnType + ">"
+	}
+	return columnType, nil
+}
+
+// partitionExpression renders the daily partitioning expression of the column.
+func partitionExpression(schema bigquery.Schema, column string) (string, error) {
+	field := schemaField(schema, column)
+	if field == nil {
+		return "", fmt.Errorf("%w: partitioning column %s is not in the schema", ErrInvalidDDL, column)
+	}
+	quoted, _ := QuoteIdentifier(column)
+	switch field.Type {
+	case bigquery.DateFieldType:
+		return quoted, nil
+	case bigquery.TimestampFieldType, bigquery.DateTimeFieldType:
+		return "DATE(" + quoted + ")", nil
+	default:
+		return "", fmt.Errorf("%w: partitioning column %s must be a DATE, TIMESTAMP or DATETIME", ErrInvalidDDL, column)
+	}
+}
+
+// schemaField returns the top-level field of the schema with the name, or nil.
+func schemaField(schema bigquery.Schema, name string) *bigquery.FieldSchema {
+	i := slices.IndexFunc(schema, func(field *bigquery.FieldSchema) bool {
+		return field.Name == name
+	})
+	if i < 0 {
+		return nil
+	}
+	return schema[i]
+}
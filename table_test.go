@@ -0,0 +1,143 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestClientCreateTable(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	schema := bigquery.Schema{
+		{Name: "id", Type: bigquery.IntegerFieldType, Required: true},
+		{Name: "created_at", Type: bigquery.TimestampFieldType, Description: "time of the 'event'"},
+		{Name: "country", Type: bigquery.StringFieldType},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "device", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "os", Type: bigquery.StringFieldType},
+			{Name: "version", Type: bigquery.FloatFieldType},
+		}},
+	}
+	err := client.CreateTable(context.Background(), "analytics", "events", schema,
+		IfNotExists(),
+		PartitionBy("created_at"),
+		ClusterBy("country", "id"),
+		ExpiresAt(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)),
+		WithDescription("raw events"),
+		WithTableLabels(map[string]string{"team": "data", "env": "prod"}))
+	if err != nil {
+		t.Fatalf("CreateTable() unexpected error: %v", err)
+	}
+	expected := "CREATE TABLE IF NOT EXISTS `analytics`.`events` (" +
+		"`id` INT64 NOT NULL, " +
+		"`created_at` TIMESTAMP OPTIONS (description = 'time of the \\'event\\''), " +
+		"`country` STRING, " +
+		"`tags` ARRAY<STRING>, " +
+		"`device` STRUCT<`os` STRING, `version` FLOAT64>) " +
+		"PARTITION BY DATE(`created_at`) CLUSTER BY `country`, `id` " +
+		"OPTIONS (expiration_timestamp = TIMESTAMP '2024-06-01 12:00:00+00:00', description = 'raw events', " +
+		"labels = [('env', 'prod'), ('team', 'data')])"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("CreateTable() queries = %q, want %q", queries, expected)
+	}
+}
+
+func TestCreateTableSQL(t *testing.T) {
+	schema := bigquery.Schema{
+		{Name: "day", Type: bigquery.DateFieldType},
+		{Name: "name", Type: bigquery.StringFieldType},
+	}
+	tests := []struct {
+		name     string
+		schema   bigquery.Schema
+		opts     []TableOption
+		expected string
+		err      error
+	}{
+		{"plain", schema, nil, "CREATE TABLE $dataset.$table (`day` DATE, `name` STRING)", nil},
+		{"or replace", schema, []TableOption{OrReplace()}, "CREATE OR REPLACE TABLE $dataset.$table (`day` DATE, `name` STRING)", nil},
+		{"date partition", schema, []TableOption{PartitionBy("day")}, "CREATE TABLE $dataset.$table (`day` DATE, `name` STRING) PARTITION BY `day`", nil},
+		{"if not exists and or replace", schema, []TableOption{IfNotExists(), OrReplace()}, "", ErrInvalidDDL},
+		{"empty schema", bigquery.Schema{}, nil, "", ErrInvalidDDL},
+		{"unknown partition column", schema, []TableOption{PartitionBy("created_at")}, "", ErrInvalidDDL},
+		{"string partition column", schema, []TableOption{PartitionBy("name")}, "", ErrInvalidDDL},
+		{"unknown cluster column", schema, []TableOption{ClusterBy("country")}, "", ErrInvalidDDL},
+		{"too many cluster columns", schema, []TableOption{ClusterBy("day", "name", "day", "name", "day")}, "", ErrInvalidDDL},
+		{"unsupported type", bigquery.Schema{{Name: "r", Type: bigquery.RangeFieldType}}, nil, "", ErrInvalidDDL},
+		{"invalid label", schema, []TableOption{WithTableLabels(map[string]string{"team": "Data')] --"})}, "", ErrInvalidLabel},
+		{"empty record", bigquery.Schema{{Name: "r", Type: bigquery.RecordFieldType}}, nil, "", ErrInvalidDDL},
+		{"injected column name", bigquery.Schema{{Name: "a` STRING, b", Type: bigquery.StringFieldType}}, nil, "", ErrIdentifierInvalidChars},
+		{"reserved column name", bigquery.Schema{{Name: "_PARTITIONTIME", Type: bigquery.TimestampFieldType}}, nil, "", ErrIdentifierNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := createTableSQL(tt.schema, tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("createTableSQL() error = %v, want %v", err, tt.err)
+			}
+			if sql != tt.expected {
+				t.Errorf("createTableSQL() = %q, want %q", sql, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClientCreateTableInvalidNames(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	schema := bigquery.Schema{{Name: "id", Type: bigquery.IntegerFieldType}}
+	err := client.CreateTable(context.Background(), "my-dataset", "events", schema)
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("CreateTable() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("CreateTable() queries = %q, want none", queries)
+	}
+}
+
+func TestClientDropAndTruncateTable(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+	if err := client.DropTable(ctx, "scratch", "tmp_1", false); err != nil {
+		t.Fatalf("DropTable() unexpected error: %v", err)
+	}
+	if err := client.DropTable(ctx, "scratch", "tmp_2", true); err != nil {
+		t.Fatalf("DropTable() unexpected error: %v", err)
+	}
+	if err := client.TruncateTable(ctx, "staging", "events"); err != nil {
+		t.Fatalf("TruncateTable() unexpected error: %v", err)
+	}
+	expected := []string{
+		"DROP TABLE `scratch`.`tmp_1`",
+		"DROP TABLE IF EXISTS `scratch`.`tmp_2`",
+		"TRUNCATE TABLE `staging`.`events`",
+	}
+	queries := fake.executedQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("queries = %q, want %q", queries, expected)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], expected[i])
+		}
+	}
+
+	err := client.DropTable(ctx, "scratch", "tmp`; DROP TABLE users; --", true)
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("DropTable() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	err = client.TruncateTable(ctx, "other.staging", "events")
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("TruncateTable() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if queries := fake.executedQueries(); len(queries) != len(expected) {
+		t.Errorf("queries = %q, want no invalid statements", queries[len(expected):])
+	}
+}
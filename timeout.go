@@ -0,0 +1,46 @@
+package saferbq
+
+import (
+	"context"
+	"time"
+)
+
+// WithQueryTimeout bounds every query that the client creates to d. The
+// contexts of Run and Read get a deadline of d (the deadline of Run covers
+// the job until it is done, the deadline of Read also covers reading the
+// results) and the JobTimeout of the query is set to d, so BigQuery stops
+// the job as well. A query can override the timeout with SetTimeout. A
+// timeout of zero (or less) means unbounded.
+//
+// Use it together with WithCancelOnContextDone to cancel the jobs of Run
+// when the deadline passes before the job is done.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithQueryTimeout(5 * time.Minute))
+//
+//	q := client.Query("INSERT INTO $table SELECT * FROM $staging")
+//	q.SetTimeout(time.Hour) // this query may take longer
+func WithQueryTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.queryTimeout = max(d, 0)
+	}
+}
+
+// SetTimeout overrides the timeout of WithQueryTimeout for this query and
+// returns the query to allow chaining. A timeout of zero (or less) means
+// unbounded.
+func (q *Query) SetTimeout(d time.Duration) *Query {
+	q.timeout = max(d, 0)
+	q.JobTimeout = q.timeout
+	return q
+}
+
+// withTimeout returns the context of Run and Read with the deadline of the
+// query timeout, or the context itself when the query has no timeout.
+func (q *Query) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if q.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, q.timeout)
+}
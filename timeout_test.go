@@ -0,0 +1,114 @@
+package saferbq
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	client.Configure(WithQueryTimeout(time.Minute))
+
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	if q.JobTimeout != time.Minute {
+		t.Errorf("JobTimeout = %v, want %v", q.JobTimeout, time.Minute)
+	}
+	job, err := q.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if got := fake.jobs[job.ID()]["jobTimeoutMs"]; got != "60000" {
+		t.Errorf("jobTimeoutMs = %v, want 60000", got)
+	}
+
+	// The timeout can be overridden per query
+	q = client.Query("DELETE FROM $table WHERE true").SetTimeout(time.Hour)
+	if q.JobTimeout != time.Hour || q.timeout != time.Hour {
+		t.Errorf("SetTimeout() = %v, %v, want %v", q.JobTimeout, q.timeout, time.Hour)
+	}
+	q.SetTimeout(0)
+	ctx, cancel := q.withTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("withTimeout() has a deadline, want none for an unbounded query")
+	}
+}
+
+func TestWithQueryTimeoutCancelsJob(t *testing.T) {
+	fake := &fakeBigQuery{running: true}
+	client := newFakeClient(t, fake)
+	client.Configure(WithQueryTimeout(50*time.Millisecond), WithCancelOnContextDone())
+
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	job, err := q.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(fake.cancelledJobs()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cancelled := fake.cancelledJobs(); !slices.Equal(cancelled, []string{job.ID()}) {
+		t.Errorf("cancelled jobs = %v, want %v", cancelled, []string{job.ID()})
+	}
+}
+
+func TestWithQueryTimeoutRead(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	client.Configure(WithQueryTimeout(time.Minute))
+
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "users"})
+	it, err := q.ReadRows(context.Background())
+	if err != nil {
+		t.Fatalf("ReadRows() unexpected error: %v", err)
+	}
+	if it.cancel == nil {
+		t.Fatal("ReadRows() iterator has no deadline to release")
+	}
+	rows := 0
+	for {
+		var row []bigquery.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %v", err)
+		}
+		rows++
+	}
+	if rows != 2 || it.cancel != nil {
+		t.Errorf("rows = %d, cancel released = %v, want 2 rows and a released deadline", rows, it.cancel == nil)
+	}
+}
+
+func TestWithQueryTimeoutReadClose(t *testing.T) {
+	client := newFakeClient(t, newReadFake())
+	client.Configure(WithQueryTimeout(time.Minute))
+
+	q := client.Query("SELECT id, name FROM $table")
+	q.SetParams(map[string]any{"$table": "users"})
+	it, err := q.ReadRows(context.Background())
+	if err != nil {
+		t.Fatalf("ReadRows() unexpected error: %v", err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		t.Fatalf("Next() unexpected error: %v", err)
+	}
+	// Stopping early releases the deadline with Close
+	it.Close()
+	it.Close()
+	if it.cancel != nil {
+		t.Error("Close() did not release the deadline")
+	}
+}
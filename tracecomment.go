@@ -0,0 +1,75 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTraceComments appends a sqlcommenter style comment to the SQL of the
+// jobs of Run and Read, with the W3C traceparent (and tracestate) of the
+// active span and the name of the application, so BigQuery audit logs and
+// INFORMATION_SCHEMA.JOBS can be correlated back to application traces. An
+// empty app leaves out the app key.
+//
+// The comment is only added to the submitted job, the SQL of the query
+// itself (and with it the idempotent job ID and the cache key) is unchanged.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithTraceComments("billing-api"))
+//	// Results: SELECT ... /*app='billing-api',traceparent='00-4bf9...-00f0...-01'*/
+func WithTraceComments(app string) Option {
+	return func(c *Client) {
+		c.traceComments = true
+		c.traceCommentApp = app
+	}
+}
+
+// applyTraceComment appends the trace comment to the SQL of the query when
+// configured, and returns the function that restores the SQL again.
+func (q *Query) applyTraceComment(ctx context.Context) func() {
+	if q.client == nil || !q.client.traceComments {
+		return func() {}
+	}
+	comment := traceComment(trace.SpanContextFromContext(ctx), q.client.traceCommentApp)
+	if comment == "" {
+		return func() {}
+	}
+	sql := q.QueryConfig.Q
+	// A newline keeps the comment out of a trailing line comment
+	q.QueryConfig.Q = sql + "\n" + comment
+	return func() {
+		q.QueryConfig.Q = sql
+	}
+}
+
+// traceComment returns the sqlcommenter comment with the app and the trace
+// context of the span, or an empty string when there is nothing to add.
+// Keys are sorted and values are URL encoded, so they can't end the comment.
+func traceComment(sc trace.SpanContext, app string) string {
+	var pairs []string
+	if app != "" {
+		pairs = append(pairs, commentPair("app", app))
+	}
+	if sc.IsValid() {
+		traceparent := fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags())
+		pairs = append(pairs, commentPair("traceparent", traceparent))
+		if state := sc.TraceState().String(); state != "" {
+			pairs = append(pairs, commentPair("tracestate", state))
+		}
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+// commentPair formats a key and value of a sqlcommenter comment.
+func commentPair(key, value string) string {
+	value = strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	return key + "='" + value + "'"
+}
@@ -0,0 +1,69 @@
+package saferbq
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceComment(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	state, _ := trace.ParseTraceState("vendor=value")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tests := []struct {
+		name string
+		sc   trace.SpanContext
+		app  string
+		want string
+	}{
+		{"empty", trace.SpanContext{}, "", ""},
+		{"app only", trace.SpanContext{}, "billing-api", "/*app='billing-api'*/"},
+		{"trace", sc, "", "/*traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01'*/"},
+		{"trace state", sc.WithTraceState(state), "my app",
+			"/*app='my%20app',traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01',tracestate='vendor%3Dvalue'*/"},
+		{"comment end", trace.SpanContext{}, "x'*/ DROP TABLE t", "/*app='x%27%2A%2F%20DROP%20TABLE%20t'*/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := traceComment(tt.sc, tt.app); got != tt.want {
+				t.Errorf("traceComment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTraceComments(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	client.Configure(
+		WithTracerProvider(sdktrace.NewTracerProvider()),
+		WithTraceComments("billing-api"),
+	)
+
+	q := client.Query("DELETE FROM $table WHERE true")
+	q.SetParams(map[string]any{"$table": "events"})
+	if _, err := q.Run(context.Background()); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	queries := fake.executedQueries()
+	if len(queries) != 1 {
+		t.Fatalf("executed queries = %v, want 1", queries)
+	}
+	prefix := "DELETE FROM `events` WHERE true\n/*app='billing-api',traceparent='00-"
+	if !strings.HasPrefix(queries[0], prefix) || !strings.HasSuffix(queries[0], "-01'*/") {
+		t.Errorf("executed query = %q, want a trace comment", queries[0])
+	}
+	if want := "DELETE FROM `events` WHERE true"; q.Q != want {
+		t.Errorf("SQL after Run = %q, want %q", q.Q, want)
+	}
+}
@@ -0,0 +1,80 @@
+package saferbq
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name of the spans created by saferbq
+const tracerName = "github.com/mevdschee/saferbq"
+
+// WithTracerProvider sets the OpenTelemetry tracer provider that is used to
+// create spans for Run, Read and the translation of queries. When no tracer
+// provider is set, the global provider from otel.GetTracerProvider is used.
+//
+// Spans carry the original SQL template (never the parameter values), the
+// $identifier names and the BigQuery job ID as attributes.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithTracerProvider(tracerProvider))
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracerProvider = tp
+	}
+}
+
+// tracer returns the tracer of the client. The client may be nil.
+func (c *Client) tracer() trace.Tracer {
+	if c == nil || c.tracerProvider == nil {
+		return otel.GetTracerProvider().Tracer(tracerName)
+	}
+	return c.tracerProvider.Tracer(tracerName)
+}
+
+// startSpan starts a span for the query with the SQL template as attribute.
+func (q *Query) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	sql := q.originalSQL
+	if sql == "" {
+		sql = q.QueryConfig.Q
+	}
+	return q.client.tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "bigquery"),
+			attribute.String("db.query.text", sql),
+		),
+	)
+}
+
+// traceTranslate applies the translation inside a span and records the
+// $identifier names on the span and the result in the metrics.
+func (q *Query) traceTranslate(ctx context.Context) error {
+	_, span := q.startSpan(ctx, "saferbq.translate")
+	err := q.translate()
+	identifiers := 0
+	if q.template != nil {
+		identifiers = len(q.template.identifiers)
+		span.SetAttributes(attribute.StringSlice("saferbq.identifiers", sortedKeys(q.template.identifiers)))
+	}
+	endSpan(span, nil, err)
+	q.client.metricsOrNil().observeTranslation(identifiers, err)
+	return err
+}
+
+// endSpan records the job ID and error (if any) on the span and ends it.
+func endSpan(span trace.Span, job *bigquery.Job, err error) {
+	if job != nil {
+		span.SetAttributes(attribute.String("bigquery.job_id", job.ID()))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,73 @@
+package saferbq
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	fake := &fakeBigQuery{schema: []map[string]any{{"name": "id", "type": "INTEGER"}}}
+	client := newFakeClient(t, fake)
+	recorder := tracetest.NewSpanRecorder()
+	client.Configure(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	ctx := context.Background()
+
+	q := client.Query("SELECT id FROM $table WHERE id = @id")
+	q.Parameters = []bigquery.QueryParameter{{Name: "$table", Value: "secret_table"}, {Name: "@id", Value: 1}}
+	if _, err := q.Run(ctx); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	if spans[0].Name() != "saferbq.translate" || spans[1].Name() != "saferbq.Query.Run" {
+		t.Errorf("span names = %q, %q, want saferbq.translate, saferbq.Query.Run", spans[0].Name(), spans[1].Name())
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("translate span should be a child of the Run span")
+	}
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, span := range spans {
+		for _, kv := range span.Attributes() {
+			attributes[kv.Key] = kv.Value
+		}
+	}
+	if got := attributes["db.query.text"].AsString(); got != "SELECT id FROM $table WHERE id = @id" {
+		t.Errorf("db.query.text = %q, want the SQL template", got)
+	}
+	if got := attributes["saferbq.identifiers"].AsStringSlice(); len(got) != 1 || got[0] != "$table" {
+		t.Errorf("saferbq.identifiers = %q, want [$table]", got)
+	}
+	if got := attributes["bigquery.job_id"].AsString(); got == "" {
+		t.Error("bigquery.job_id should be set")
+	}
+}
+
+func TestTracingError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	client := (&Client{}).Configure(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	q := &Query{client: client}
+	q.QueryConfig.Q = "SELECT * FROM $table"
+	if _, err := q.Read(context.Background()); err == nil {
+		t.Fatal("Read() expected error for missing identifier")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	for _, span := range spans {
+		if span.Status().Code != codes.Error {
+			t.Errorf("span %s status = %v, want error", span.Name(), span.Status().Code)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Tx collects the statements of a transaction. The statements are
+// submitted together as a single BigQuery script when the transaction
+// function returns.
+type Tx struct {
+	client  *Client
+	queries []*Query
+}
+
+// Query adds a statement to the transaction and returns it, so its
+// parameters can be set. The statement supports $identifier parameters
+// like any other query.
+func (tx *Tx) Query(sql string) *Query {
+	q := tx.client.Query(sql)
+	tx.queries = append(tx.queries, q)
+	return q
+}
+
+// Transaction collects the statements added by fn and runs them as a
+// BigQuery script within BEGIN TRANSACTION and COMMIT TRANSACTION. When a
+// statement fails the transaction is rolled back. When fn returns an error
+// nothing is submitted and the error is returned.
+//
+// The named parameters of all statements share one namespace, so a name
+// that is used in multiple statements must have the same value. The names
+// that WithNamedPositionals generates for ? placeholders are renamed per
+// statement instead.
+//
+// Example:
+//
+//	err := client.Transaction(ctx, func(tx *saferbq.Tx) error {
+//	    q := tx.Query("DELETE FROM $table WHERE id = @id")
+//	    q.SetParams(map[string]any{"$table": "orders", "@id": 1})
+//	    q = tx.Query("INSERT INTO $archive SELECT * FROM $table WHERE id = @id")
+//	    q.SetParams(map[string]any{"$archive": "orders_archive", "$table": "orders", "@id": 1})
+//	    return nil
+//	})
+//
+// Returns an error if parameter validation of a statement fails, an error
+// wrapping ErrParameterConflict if a named parameter has different values,
+// or an error wrapping ErrJobFailed if the transaction failed.
+func (c *Client) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	tx := &Tx{client: c}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if len(tx.queries) == 0 {
+		return nil
+	}
+	script, err := tx.script()
+	if err != nil {
+		return err
+	}
+	if _, err := script.Exec(ctx); err != nil {
+		return fmt.Errorf("transaction failed: %w", err)
+	}
+	return nil
+}
+
+// script translates all statements and combines them into a single query
+// that runs them in a transaction.
+func (tx *Tx) script() (*Query, error) {
+	var original, translated strings.Builder
+	writeStatement := func(b *strings.Builder, sql string) {
+		b.WriteString("  ")
+		b.WriteString(strings.TrimSpace(statementBody(sql)))
+		b.WriteString(";\n")
+	}
+	for _, b := range []*strings.Builder{&original, &translated} {
+		b.WriteString("BEGIN\n  BEGIN TRANSACTION;\n")
+	}
+	// The parameters generated for ? placeholders are renamed per statement,
+	// the parameters the caller named are shared by all statements
+	taken := map[string]bool{}
+	for i, q := range tx.queries {
+		if err := q.translate(); err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		for _, p := range q.Parameters {
+			if !slices.Contains(q.positionalNames, string(atSign)+p.Name) {
+				taken[p.Name] = true
+			}
+		}
+	}
+	named := map[string]bigquery.QueryParameter{}
+	parameters := []bigquery.QueryParameter{}
+	positional := false
+	for _, q := range tx.queries {
+		var generated []bigquery.QueryParameter
+		for _, p := range q.Parameters {
+			if p.Name == "" {
+				positional = true
+				parameters = append(parameters, p)
+				continue
+			}
+			if slices.Contains(q.positionalNames, string(atSign)+p.Name) {
+				generated = append(generated, p)
+				continue
+			}
+			if existing, ok := named[p.Name]; ok {
+				if !reflect.DeepEqual(existing.Value, p.Value) {
+					return nil, fmt.Errorf("%w: %c%s", ErrParameterConflict, atSign, p.Name)
+				}
+				continue
+			}
+			named[p.Name] = p
+			parameters = append(parameters, p)
+		}
+		sql, generated := renameParameters(q.QueryConfig.Q, generated, taken)
+		parameters = append(parameters, generated...)
+		writeStatement(&original, q.originalSQL)
+		writeStatement(&translated, sql)
+	}
+	if positional && len(named) > 0 {
+		return nil, ErrMixedParameterTypes
+	}
+	for _, b := range []*strings.Builder{&original, &translated} {
+		b.WriteString("  COMMIT TRANSACTION;\nEXCEPTION WHEN ERROR THEN\n  ROLLBACK TRANSACTION;\n  RAISE USING MESSAGE = @@error.message;\nEND;")
+	}
+	// The statements are translated already, so the script is not translated again
+	q := tx.client.Query(original.String())
+	q.originalSQL = original.String()
+	q.QueryConfig.Q = translated.String()
+	q.Parameters = parameters
+	q.translated = true
+	return q, nil
+}
@@ -0,0 +1,156 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+
+	err := client.Transaction(context.Background(), func(tx *Tx) error {
+		q := tx.Query("DELETE FROM $table WHERE id = @id;")
+		q.SetParams(map[string]any{"$table": "orders", "@id": 1})
+		q = tx.Query("INSERT INTO $archive SELECT * FROM $table WHERE id = @id")
+		q.SetParams(map[string]any{"$archive": "orders_archive", "$table": "orders", "@id": 1})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction() unexpected error: %v", err)
+	}
+	want := "BEGIN\n" +
+		"  BEGIN TRANSACTION;\n" +
+		"  DELETE FROM `orders` WHERE id = @id;\n" +
+		"  INSERT INTO `orders_archive` SELECT * FROM `orders` WHERE id = @id;\n" +
+		"  COMMIT TRANSACTION;\n" +
+		"EXCEPTION WHEN ERROR THEN\n" +
+		"  ROLLBACK TRANSACTION;\n" +
+		"  RAISE USING MESSAGE = @@error.message;\n" +
+		"END;"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("executed queries = %q, want [%q]", queries, want)
+	}
+}
+
+func TestTransactionTrailingComment(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+
+	err := client.Transaction(context.Background(), func(tx *Tx) error {
+		q := tx.Query("DELETE FROM $table WHERE true -- clear all;\n")
+		q.SetParams(map[string]any{"$table": "orders"})
+		q = tx.Query("INSERT INTO $table VALUES (1); # first row")
+		q.SetParams(map[string]any{"$table": "orders"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction() unexpected error: %v", err)
+	}
+	want := "BEGIN\n" +
+		"  BEGIN TRANSACTION;\n" +
+		"  DELETE FROM `orders` WHERE true;\n" +
+		"  INSERT INTO `orders` VALUES (1);\n" +
+		"  COMMIT TRANSACTION;\n" +
+		"EXCEPTION WHEN ERROR THEN\n" +
+		"  ROLLBACK TRANSACTION;\n" +
+		"  RAISE USING MESSAGE = @@error.message;\n" +
+		"END;"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("executed queries = %q, want [%q]", queries, want)
+	}
+}
+
+func TestTransactionNamedPositionals(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	client.Configure(WithNamedPositionals())
+
+	err := client.Transaction(context.Background(), func(tx *Tx) error {
+		q := tx.Query("DELETE FROM $table WHERE id = ? AND day = @day")
+		q.SetParams(map[string]any{"$table": "orders", "@day": "2024-01-01"})
+		q.SetPositionalParams(1)
+		q = tx.Query("UPDATE $table SET status = ? WHERE id = ? AND day = @day")
+		q.SetParams(map[string]any{"$table": "orders", "@day": "2024-01-01"})
+		q.SetPositionalParams("archived", 2)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction() unexpected error: %v", err)
+	}
+	want := "BEGIN\n" +
+		"  BEGIN TRANSACTION;\n" +
+		"  DELETE FROM `orders` WHERE id = @p1 AND day = @day;\n" +
+		"  UPDATE `orders` SET status = @p1_1 WHERE id = @p2 AND day = @day;\n" +
+		"  COMMIT TRANSACTION;\n" +
+		"EXCEPTION WHEN ERROR THEN\n" +
+		"  ROLLBACK TRANSACTION;\n" +
+		"  RAISE USING MESSAGE = @@error.message;\n" +
+		"END;"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("executed queries = %q, want [%q]", queries, want)
+	}
+	for _, job := range fake.jobs {
+		params, _ := job["query"].(map[string]any)["queryParameters"].([]any)
+		if len(params) != 4 {
+			t.Errorf("submitted parameters = %v, want day, p1, p1_1 and p2", params)
+		}
+	}
+}
+
+func TestTransactionErrors(t *testing.T) {
+	fnErr := errors.New("abort")
+	tests := []struct {
+		name string
+		fn   func(tx *Tx) error
+		err  error
+	}{
+		{"function error", func(tx *Tx) error {
+			tx.Query("DELETE FROM t WHERE true")
+			return fnErr
+		}, fnErr},
+		{"translation error", func(tx *Tx) error {
+			tx.Query("DELETE FROM $table WHERE true")
+			return nil
+		}, ErrIdentifierNotProvided},
+		{"conflicting parameters", func(tx *Tx) error {
+			tx.Query("DELETE FROM a WHERE id = @id").SetParams(map[string]any{"@id": 1})
+			tx.Query("DELETE FROM b WHERE id = @id").SetParams(map[string]any{"@id": 2})
+			return nil
+		}, ErrParameterConflict},
+		{"mixed parameters", func(tx *Tx) error {
+			tx.Query("DELETE FROM a WHERE id = @id").SetParams(map[string]any{"@id": 1})
+			tx.Query("DELETE FROM b WHERE id = ?").SetPositionalParams(2)
+			return nil
+		}, ErrMixedParameterTypes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBigQuery{}
+			client := newFakeClient(t, fake)
+			err := client.Transaction(context.Background(), tt.fn)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Transaction() error = %v, want %v", err, tt.err)
+			}
+			if queries := fake.executedQueries(); len(queries) != 0 {
+				t.Errorf("executed queries = %q, want none", queries)
+			}
+		})
+	}
+}
+
+func TestTransactionFailed(t *testing.T) {
+	fake := &fakeBigQuery{errorResult: "invalidQuery"}
+	client := newFakeClient(t, fake)
+	err := client.Transaction(context.Background(), func(tx *Tx) error {
+		tx.Query("DELETE FROM t WHERE true")
+		return nil
+	})
+	if !errors.Is(err, ErrJobFailed) {
+		t.Errorf("Transaction() error = %v, want ErrJobFailed", err)
+	}
+}
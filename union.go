@@ -0,0 +1,55 @@
+package saferbq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unionAllTemplate is the per-table template of UnionAll
+const unionAllTemplate = "SELECT * FROM $table"
+
+// UnionAll builds a Fragment that selects all rows of the tables, combined
+// with UNION ALL. Every table is validated and quoted like a $identifier
+// value. It is intended for querying tables that are split by period, such
+// as monthly tables, see UnionAllOf to select specific columns.
+//
+// Example:
+//
+//	tables, err := saferbq.UnionAll([]string{"sales_2024_01", "sales_2024_02"})
+//	if err != nil {
+//	    return err
+//	}
+//	q := client.Query("SELECT region, SUM(amount) FROM ($tables) GROUP BY region")
+//	q.SetParams(map[string]any{"$tables": tables})
+//	// SELECT region, SUM(amount) FROM (SELECT * FROM `sales_2024_01`
+//	//   UNION ALL SELECT * FROM `sales_2024_02`) GROUP BY region
+//
+// Returns an error if no tables are given or if a table is invalid.
+func UnionAll(tables []string) (Fragment, error) {
+	return UnionAllOf(unionAllTemplate, tables)
+}
+
+// UnionAllOf builds a Fragment that repeats the template for every table,
+// combined with UNION ALL. The template must contain the $table identifier,
+// which is replaced by the validated and quoted table.
+//
+// Example:
+//
+//	tables, err := saferbq.UnionAllOf("SELECT region, amount FROM $table WHERE amount > 0", months)
+//
+// Returns an error if no tables are given, if the template contains other
+// parameters, or if a table is invalid.
+func UnionAllOf(template string, tables []string) (Fragment, error) {
+	if len(tables) == 0 {
+		return Fragment{}, fmt.Errorf("%w: no tables to combine", ErrIdentifierEmpty)
+	}
+	selects := make([]string, 0, len(tables))
+	for _, table := range tables {
+		fragment, err := NewFragment(template, map[string]any{"$table": table})
+		if err != nil {
+			return Fragment{}, err
+		}
+		selects = append(selects, fragment.sql)
+	}
+	return Fragment{sql: strings.Join(selects, " UNION ALL ")}, nil
+}
@@ -0,0 +1,43 @@
+package saferbq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnionAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		tables   []string
+		fragOut  string
+		err      error
+	}{
+		{"single table", "", []string{"sales_2024_01"}, "SELECT * FROM `sales_2024_01`", nil},
+		{"multiple tables", "", []string{"sales_2024_01", "archive.sales_2024_02"}, "SELECT * FROM `sales_2024_01` UNION ALL SELECT * FROM `archive`.`sales_2024_02`", nil},
+		{"template", "SELECT region, amount FROM $table WHERE amount > 0", []string{"a", "b"}, "SELECT region, amount FROM `a` WHERE amount > 0 UNION ALL SELECT region, amount FROM `b` WHERE amount > 0", nil},
+		{"no tables", "", nil, "", ErrIdentifierEmpty},
+		{"invalid table", "", []string{"a", "b; DROP TABLE c"}, "", ErrIdentifierInvalidChars},
+		{"template without table", "SELECT 1", []string{"a"}, "", ErrIdentifierNotFound},
+		{"template with other identifier", "SELECT $column FROM $table", []string{"a"}, "", ErrIdentifierNotProvided},
+		{"template with named parameter", "SELECT * FROM $table WHERE id = @id", []string{"a"}, "", ErrInvalidParameterName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fragment Fragment
+			var err error
+			if tt.template == "" {
+				fragment, err = UnionAll(tt.tables)
+			} else {
+				fragment, err = UnionAllOf(tt.template, tt.tables)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("UnionAll() error = %v, want %v", err, tt.err)
+			}
+			if fragment.String() != tt.fragOut {
+				t.Errorf("UnionAll() = %q, want %q", fragment.String(), tt.fragOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package saferbq
+
+import (
+	"reflect"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// WithAutoUnnest rewrites "IN @param" to "IN UNNEST(@param)" in the
+// translated SQL when the value of the named parameter is a slice or an
+// array (other than []byte), as BigQuery only accepts array parameters in
+// an IN clause through UNNEST. Forgetting UNNEST otherwise fails the query
+// at runtime.
+//
+// Example:
+//
+//	client.Configure(saferbq.WithAutoUnnest())
+//
+//	q := client.Query("SELECT * FROM $table WHERE id IN @ids")
+//	q.SetParams(map[string]any{"$table": "users", "@ids": []int{1, 2, 3}})
+//	// Results: SELECT * FROM `users` WHERE id IN UNNEST(@ids)
+func WithAutoUnnest() Option {
+	return func(c *Client) {
+		c.autoUnnest = true
+	}
+}
+
+// expandUnnest wraps the named parameters with an array value that directly
+// follow the IN keyword in UNNEST().
+func expandUnnest(sql string, params []bigquery.QueryParameter) string {
+	arrays := map[string]bool{}
+	for _, p := range params {
+		if p.Name != "" && isArrayValue(p.Value) {
+			arrays["@"+p.Name] = true
+		}
+	}
+	if len(arrays) == 0 {
+		return sql
+	}
+	var result strings.Builder
+	result.Grow(len(sql))
+	previous := token{}
+	for _, t := range scan(sql) {
+		if t.kind == tokenNamedParam && arrays[t.text] && previous.kind == tokenWord && strings.EqualFold(previous.text, "IN") {
+			result.WriteString("UNNEST(" + t.text + ")")
+		} else {
+			result.WriteString(t.text)
+		}
+		// Whitespace and comments may separate IN from the parameter
+		if t.kind != tokenWhitespace && t.kind != tokenComment {
+			previous = t
+		}
+	}
+	return result.String()
+}
+
+// isArrayValue reports whether the parameter value is passed to BigQuery
+// as an ARRAY, which is any slice or array except []byte (BYTES).
+func isArrayValue(value any) bool {
+	if _, ok := value.([]byte); ok {
+		return false
+	}
+	kind := reflect.ValueOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
@@ -0,0 +1,72 @@
+package saferbq
+
+import (
+	"testing"
+)
+
+func TestWithAutoUnnest(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		params map[string]any
+		want   string
+	}{
+		{
+			name:   "slice",
+			sql:    "SELECT * FROM $table WHERE id IN @ids",
+			params: map[string]any{"$table": "users", "@ids": []int{1, 2}},
+			want:   "SELECT * FROM `users` WHERE id IN UNNEST(@ids)",
+		},
+		{
+			name:   "not in with comment",
+			sql:    "SELECT * FROM $table WHERE name not in /* names */ @names",
+			params: map[string]any{"$table": "users", "@names": [2]string{"a", "b"}},
+			want:   "SELECT * FROM `users` WHERE name not in /* names */ UNNEST(@names)",
+		},
+		{
+			name:   "already unnested",
+			sql:    "SELECT * FROM $table WHERE id IN UNNEST(@ids)",
+			params: map[string]any{"$table": "users", "@ids": []int{1, 2}},
+			want:   "SELECT * FROM `users` WHERE id IN UNNEST(@ids)",
+		},
+		{
+			name:   "scalar",
+			sql:    "SELECT * FROM $table WHERE id IN @id",
+			params: map[string]any{"$table": "users", "@id": 1},
+			want:   "SELECT * FROM `users` WHERE id IN @id",
+		},
+		{
+			name:   "bytes",
+			sql:    "SELECT * FROM $table WHERE hash IN @hash",
+			params: map[string]any{"$table": "users", "@hash": []byte("abc")},
+			want:   "SELECT * FROM `users` WHERE hash IN @hash",
+		},
+		{
+			name:   "not after IN",
+			sql:    "SELECT * FROM $table WHERE id = ANY(@ids) AND 'IN @ids' != ''",
+			params: map[string]any{"$table": "users", "@ids": []int{1, 2}},
+			want:   "SELECT * FROM `users` WHERE id = ANY(@ids) AND 'IN @ids' != ''",
+		},
+	}
+
+	client := (&Client{}).Configure(WithAutoUnnest())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := client.Query(tt.sql)
+			q.SetParams(tt.params)
+			if err := q.translate(); err != nil {
+				t.Fatalf("translate() unexpected error: %v", err)
+			}
+			if q.Q != tt.want {
+				t.Errorf("SQL = %q, want %q", q.Q, tt.want)
+			}
+		})
+	}
+
+	// Without the option the SQL is not changed
+	q := (&Client{}).Query("SELECT * FROM t WHERE id IN @ids")
+	q.SetParams(map[string]any{"@ids": []int{1, 2}})
+	if err := q.translate(); err != nil || q.Q != "SELECT * FROM t WHERE id IN @ids" {
+		t.Errorf("translate() = %q, %v, want the SQL unchanged", q.Q, err)
+	}
+}
@@ -0,0 +1,103 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Upsert inserts the rows into the table, or updates the existing rows
+// with the same values in the key columns, by running a generated MERGE
+// statement. The rows must be a slice of structs (or pointers to structs);
+// the column names are derived from the struct fields and their bigquery
+// tags, as in bigquery.InferSchema, and validated with the rules of
+// ColumnName. The rows are bound as a single array parameter, so the
+// statement contains no values and is subject to the query parameter size
+// limit of BigQuery (split large slices into batches).
+//
+// Example:
+//
+//	type Customer struct {
+//	    ID    int64  `bigquery:"id"`
+//	    Name  string `bigquery:"name"`
+//	    Email string `bigquery:"email"`
+//	}
+//	affected, err := client.Upsert(ctx, "crm", "customers_"+tenant, customers, []string{"id"})
+//	// MERGE `crm`.`customers_acme` AS target USING UNNEST(@rows) AS source
+//	// ON target.`id` = source.`id`
+//	// WHEN MATCHED THEN UPDATE SET `name` = source.`name`, `email` = source.`email`
+//	// WHEN NOT MATCHED THEN INSERT (`id`, `name`, `email`) VALUES (source.`id`, source.`name`, source.`email`)
+//
+// Returns the number of inserted and updated rows (0 without rows), or an
+// error wrapping ErrInvalidDDL if the rows are not a slice of structs or a
+// key column is missing, an error if an identifier is not valid, or an
+// error if the statement fails.
+func (c *Client) Upsert(ctx context.Context, dataset, table string, rows any, keyCols []string) (int64, error) {
+	sql, n, err := upsertSQL(rows, keyCols)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert: %w", err)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	q := c.Query(sql)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset), "$table": TableID(table), "@rows": rows})
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert: %w", err)
+	}
+	return result.RowsAffected, nil
+}
+
+// upsertSQL renders the MERGE statement for the rows, with $dataset.$table
+// as the target and @rows as the source. It returns the number of rows.
+func upsertSQL(rows any, keyCols []string) (string, int, error) {
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice {
+		return "", 0, fmt.Errorf("%w: rows must be a slice of structs, not %T", ErrInvalidDDL, rows)
+	}
+	elem := value.Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return "", 0, fmt.Errorf("%w: rows must be a slice of structs, not %T", ErrInvalidDDL, rows)
+	}
+	schema, err := bigquery.InferSchema(reflect.New(elem).Elem().Interface())
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %w", ErrInvalidDDL, err)
+	}
+	if len(keyCols) == 0 {
+		return "", 0, fmt.Errorf("%w: upsert needs at least one key column", ErrInvalidDDL)
+	}
+	for _, key := range keyCols {
+		if schemaField(schema, key) == nil {
+			return "", 0, fmt.Errorf("%w: key column %s is not a field of %s", ErrInvalidDDL, key, elem)
+		}
+	}
+	var columns, values, keys, updates []string
+	for _, field := range schema {
+		column, err := ColumnName(field.Name).render("column " + field.Name)
+		if err != nil {
+			return "", 0, err
+		}
+		columns = append(columns, column)
+		values = append(values, "source."+column)
+		if slices.Contains(keyCols, field.Name) {
+			keys = append(keys, "target."+column+" = source."+column)
+		} else {
+			updates = append(updates, column+" = source."+column)
+		}
+	}
+	var sql strings.Builder
+	sql.WriteString("MERGE $dataset.$table AS target USING UNNEST(@rows) AS source ON " + strings.Join(keys, " AND "))
+	if len(updates) > 0 {
+		sql.WriteString(" WHEN MATCHED THEN UPDATE SET " + strings.Join(updates, ", "))
+	}
+	sql.WriteString(" WHEN NOT MATCHED THEN INSERT (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ")")
+	return sql.String(), value.Len(), nil
+}
@@ -0,0 +1,106 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type upsertCustomer struct {
+	ID    int64  `bigquery:"id"`
+	Name  string `bigquery:"name"`
+	Email string
+	Notes string `bigquery:"-"`
+}
+
+func TestClientUpsert(t *testing.T) {
+	fake := &fakeBigQuery{statistics: map[string]any{"numDmlAffectedRows": "2"}}
+	client := newFakeClient(t, fake)
+	rows := []upsertCustomer{{ID: 1, Name: "Ann", Email: "ann@example.com"}, {ID: 2, Name: "Bob"}}
+	affected, err := client.Upsert(context.Background(), "crm", "customers", rows, []string{"id"})
+	if err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if affected != 2 {
+		t.Errorf("Upsert() affected = %d, want 2", affected)
+	}
+	expected := "MERGE `crm`.`customers` AS target USING UNNEST(@rows) AS source ON target.`id` = source.`id` " +
+		"WHEN MATCHED THEN UPDATE SET `name` = source.`name`, `Email` = source.`Email` " +
+		"WHEN NOT MATCHED THEN INSERT (`id`, `name`, `Email`) VALUES (source.`id`, source.`name`, source.`Email`)"
+	queries := fake.executedQueries()
+	if len(queries) != 1 || queries[0] != expected {
+		t.Fatalf("Upsert() queries = %q, want %q", queries, expected)
+	}
+	for _, job := range fake.jobs {
+		params := job["query"].(map[string]any)["queryParameters"].([]any)
+		param := params[0].(map[string]any)
+		paramType := fmt.Sprint(param["parameterType"])
+		if param["name"] != "rows" || paramType != "map[arrayType:map[structTypes:[map[name:id type:map[type:INT64]] map[name:name type:map[type:STRING]] map[name:Email type:map[type:STRING]]] type:STRUCT] type:ARRAY]" {
+			t.Errorf("Upsert() parameter = %v", param)
+		}
+	}
+}
+
+func TestClientUpsertKeysOnly(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	type tag struct {
+		Name string `bigquery:"name"`
+	}
+	if _, err := client.Upsert(context.Background(), "crm", "tags", []*tag{{Name: "vip"}}, []string{"name"}); err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	expected := "MERGE `crm`.`tags` AS target USING UNNEST(@rows) AS source ON target.`name` = source.`name` " +
+		"WHEN NOT MATCHED THEN INSERT (`name`) VALUES (source.`name`)"
+	if queries := fake.executedQueries(); len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Upsert() queries = %q, want %q", queries, expected)
+	}
+}
+
+func TestClientUpsertValidation(t *testing.T) {
+	type reserved struct {
+		Partition string `bigquery:"_PARTITIONTIME"`
+	}
+	rows := []upsertCustomer{{ID: 1}}
+	tests := []struct {
+		name  string
+		table string
+		rows  any
+		keys  []string
+		err   error
+	}{
+		{"not a slice", "customers", upsertCustomer{}, []string{"id"}, ErrInvalidDDL},
+		{"not structs", "customers", []int{1}, []string{"id"}, ErrInvalidDDL},
+		{"no keys", "customers", rows, nil, ErrInvalidDDL},
+		{"unknown key", "customers", rows, []string{"Notes"}, ErrInvalidDDL},
+		{"reserved column", "customers", []reserved{{}}, []string{"_PARTITIONTIME"}, ErrIdentifierNotAllowed},
+		{"invalid table", "customers`; --", rows, []string{"id"}, ErrIdentifierInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBigQuery{}
+			client := newFakeClient(t, fake)
+			_, err := client.Upsert(context.Background(), "crm", tt.table, tt.rows, tt.keys)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Upsert() error = %v, want %v", err, tt.err)
+			}
+			if queries := fake.executedQueries(); len(queries) != 0 {
+				t.Errorf("Upsert() queries = %q, want none", queries)
+			}
+		})
+	}
+}
+
+func TestClientUpsertNoRows(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	affected, err := client.Upsert(context.Background(), "crm", "customers", []upsertCustomer{}, []string{"id"})
+	if err != nil || affected != 0 {
+		t.Errorf("Upsert() = %d, %v, want 0, nil", affected, err)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("Upsert() queries = %q, want none", queries)
+	}
+}
@@ -0,0 +1,134 @@
+package saferbq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ViewOptions configures the CREATE VIEW statement of CreateView.
+type ViewOptions struct {
+	// OrReplace replaces the view when it exists
+	OrReplace bool
+	// IfNotExists only creates the view when it does not exist
+	IfNotExists bool
+	// Materialized creates a materialized view
+	Materialized bool
+	// Description is the description of the view
+	Description string
+	// Labels are the labels of the view
+	Labels map[string]string
+	// ExpiresAt is the time at which the view is deleted
+	ExpiresAt time.Time
+	// DisableRefresh turns off the automatic refresh of a materialized view
+	DisableRefresh bool
+	// RefreshInterval is the interval between automatic refreshes of a
+	// materialized view, in whole minutes
+	RefreshInterval time.Duration
+	// MaxStaleness is the staleness a query on a materialized view accepts
+	// before it reads the base tables
+	MaxStaleness time.Duration
+}
+
+// CreateView creates a view (or a materialized view) by running a CREATE
+// VIEW statement with the translated query as its definition. The dataset
+// and view IDs are validated (with the rules of DatasetID and TableID) and
+// quoted, and the description and labels are escaped as string literals.
+// The query may use $identifiers, but no @named or ? positional parameters,
+// as BigQuery does not store parameter values in a view.
+//
+// Example:
+//
+//	q := client.Query("SELECT * FROM $table WHERE tenant_id = 42")
+//	q.SetParams(map[string]any{"$table": "analytics.events"})
+//	err := client.CreateView(ctx, "tenant_42", "events", q, saferbq.ViewOptions{OrReplace: true})
+//	// CREATE OR REPLACE VIEW `tenant_42`.`events` AS (SELECT * FROM `analytics`.`events` WHERE tenant_id = 42)
+//
+// Returns an error wrapping ErrInvalidDDL if the options are not valid or
+// the query has parameters, an error if the query or an identifier is not
+// valid, or an error if the statement fails.
+func (c *Client) CreateView(ctx context.Context, dataset, view string, query *Query, opts ViewOptions) error {
+	sql, err := createViewSQL(query, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	q := c.Query(sql)
+	q.SetParams(map[string]any{"$dataset": DatasetID(dataset), "$view": TableID(view), "$query": query})
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	return nil
+}
+
+// createViewSQL translates the query and renders the CREATE VIEW statement,
+// with $dataset.$view as the name of the view and $query as its definition.
+func createViewSQL(query *Query, opts ViewOptions) (string, error) {
+	if query == nil {
+		return "", fmt.Errorf("%w: view has no query", ErrInvalidDDL)
+	}
+	if opts.IfNotExists && opts.OrReplace {
+		return "", fmt.Errorf("%w: IfNotExists and OrReplace can't be combined", ErrInvalidDDL)
+	}
+	if err := query.translate(); err != nil {
+		return "", err
+	}
+	if len(query.Parameters) > 0 {
+		return "", fmt.Errorf("%w: view query can't have query parameters", ErrInvalidDDL)
+	}
+	options, err := tableOptions{expiration: opts.ExpiresAt, description: opts.Description, labels: opts.Labels}.options()
+	if err != nil {
+		return "", err
+	}
+	if opts.Materialized {
+		refresh, err := refreshOptions(opts)
+		if err != nil {
+			return "", err
+		}
+		options = append(options, refresh...)
+	} else if opts.DisableRefresh || opts.RefreshInterval != 0 || opts.MaxStaleness != 0 {
+		return "", fmt.Errorf("%w: refresh options need a materialized view", ErrInvalidDDL)
+	}
+	var sql strings.Builder
+	sql.WriteString("CREATE ")
+	if opts.OrReplace {
+		sql.WriteString("OR REPLACE ")
+	}
+	if opts.Materialized {
+		sql.WriteString("MATERIALIZED ")
+	}
+	sql.WriteString("VIEW ")
+	if opts.IfNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString("$dataset.$view")
+	if len(options) > 0 {
+		sql.WriteString(" OPTIONS (" + strings.Join(options, ", ") + ")")
+	}
+	sql.WriteString(" AS $query")
+	return sql.String(), nil
+}
+
+// refreshOptions renders the refresh options of a materialized view.
+func refreshOptions(opts ViewOptions) ([]string, error) {
+	var options []string
+	if opts.DisableRefresh {
+		if opts.RefreshInterval != 0 {
+			return nil, fmt.Errorf("%w: RefreshInterval needs refresh to be enabled", ErrInvalidDDL)
+		}
+		options = append(options, "enable_refresh = false")
+	}
+	if opts.RefreshInterval != 0 {
+		if opts.RefreshInterval < time.Minute || opts.RefreshInterval%time.Minute != 0 {
+			return nil, fmt.Errorf("%w: RefreshInterval must be a positive number of minutes", ErrInvalidDDL)
+		}
+		options = append(options, fmt.Sprintf("refresh_interval_minutes = %d", opts.RefreshInterval/time.Minute))
+	}
+	if opts.MaxStaleness != 0 {
+		if opts.MaxStaleness < time.Second || opts.MaxStaleness%time.Second != 0 {
+			return nil, fmt.Errorf("%w: MaxStaleness must be a positive number of seconds", ErrInvalidDDL)
+		}
+		options = append(options, "max_staleness = "+intervalLiteral(opts.MaxStaleness))
+	}
+	return options, nil
+}
@@ -0,0 +1,92 @@
+package saferbq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClientCreateView(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	ctx := context.Background()
+
+	q := client.Query("SELECT * FROM $table WHERE tenant_id = 42")
+	q.SetParams(map[string]any{"$table": "analytics.events"})
+	if err := client.CreateView(ctx, "tenant_42", "events", q, ViewOptions{OrReplace: true, Description: "tenant's events"}); err != nil {
+		t.Fatalf("CreateView() unexpected error: %v", err)
+	}
+	mv := client.Query("SELECT day, COUNT(*) AS n FROM $table GROUP BY day")
+	mv.SetParams(map[string]any{"$table": "analytics.events"})
+	err := client.CreateView(ctx, "reporting", "daily_counts", mv, ViewOptions{
+		Materialized:    true,
+		IfNotExists:     true,
+		RefreshInterval: 30 * time.Minute,
+		MaxStaleness:    4*time.Hour + 30*time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("CreateView() unexpected error: %v", err)
+	}
+	expected := []string{
+		"CREATE OR REPLACE VIEW `tenant_42`.`events` OPTIONS (description = 'tenant\\'s events') " +
+			"AS (SELECT * FROM `analytics`.`events` WHERE tenant_id = 42)",
+		"CREATE MATERIALIZED VIEW IF NOT EXISTS `reporting`.`daily_counts` " +
+			"OPTIONS (refresh_interval_minutes = 30, max_staleness = INTERVAL '4:30:0' HOUR TO SECOND) " +
+			"AS (SELECT day, COUNT(*) AS n FROM `analytics`.`events` GROUP BY day)",
+	}
+	queries := fake.executedQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("queries = %q, want %q", queries, expected)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], expected[i])
+		}
+	}
+}
+
+func TestCreateViewValidation(t *testing.T) {
+	client := &Client{}
+	query := func(sql string, params map[string]any) *Query {
+		q := client.Query(sql)
+		q.SetParams(params)
+		return q
+	}
+	tests := []struct {
+		name  string
+		query *Query
+		opts  ViewOptions
+		err   error
+	}{
+		{"no query", nil, ViewOptions{}, ErrInvalidDDL},
+		{"named parameter", query("SELECT * FROM t WHERE id = @id", map[string]any{"@id": 1}), ViewOptions{}, ErrInvalidDDL},
+		{"invalid identifier", query("SELECT * FROM $table", map[string]any{"$table": "a;b"}), ViewOptions{}, ErrIdentifierInvalidChars},
+		{"if not exists and or replace", query("SELECT 1", nil), ViewOptions{IfNotExists: true, OrReplace: true}, ErrInvalidDDL},
+		{"refresh on view", query("SELECT 1", nil), ViewOptions{RefreshInterval: time.Hour}, ErrInvalidDDL},
+		{"partial minutes", query("SELECT 1", nil), ViewOptions{Materialized: true, RefreshInterval: 90 * time.Second}, ErrInvalidDDL},
+		{"disabled refresh interval", query("SELECT 1", nil), ViewOptions{Materialized: true, DisableRefresh: true, RefreshInterval: time.Hour}, ErrInvalidDDL},
+		{"negative staleness", query("SELECT 1", nil), ViewOptions{Materialized: true, MaxStaleness: -time.Hour}, ErrInvalidDDL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := createViewSQL(tt.query, tt.opts)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("createViewSQL() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestClientCreateViewInvalidName(t *testing.T) {
+	fake := &fakeBigQuery{}
+	client := newFakeClient(t, fake)
+	err := client.CreateView(context.Background(), "reporting", "v`; DROP TABLE x; --", client.Query("SELECT 1"), ViewOptions{})
+	if !errors.Is(err, ErrIdentifierInvalidChars) {
+		t.Errorf("CreateView() error = %v, want %v", err, ErrIdentifierInvalidChars)
+	}
+	if queries := fake.executedQueries(); len(queries) != 0 {
+		t.Errorf("CreateView() queries = %q, want none", queries)
+	}
+}